@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "math"
+
+// SumIncluding returns the sum of the weights of the intervals that overlap with the given value,
+// as returned by weight for each matching original index, without collecting the matches.
+func (t *INTree) SumIncluding(val float64, weight func(index int) float64) float64 {
+	return FoldIncluding(t, val, 0.0, func(sum float64, index int) float64 {
+		return sum + weight(index)
+	})
+}
+
+// FoldIncluding aggregates the intervals that overlap with the given value, starting from init
+// and calling fn with the accumulated result and the original index of every match, in the same order as Including.
+func FoldIncluding[A any](t *INTree, val float64, init A, fn func(acc A, index int) A) A {
+	acc := init
+
+	t.traverse(val, val, func(pos int) bool {
+		acc = fn(acc, t.indexes[pos])
+		return true
+	})
+
+	return acc
+}
+
+// FoldOverlapping aggregates the intervals that overlap with the given range, starting from init
+// and calling fn with the accumulated result and the original index of every match, in the same order as Overlapping.
+// Returns init if lower is greater than upper.
+func FoldOverlapping[A any](t *INTree, lower, upper float64, init A, fn func(acc A, index int) A) A {
+	acc := init
+
+	if lower > upper {
+		return acc
+	}
+
+	t.traverse(lower, upper, func(pos int) bool {
+		acc = fn(acc, t.indexes[pos])
+		return true
+	})
+
+	return acc
+}
+
+// MaxIncluding returns the original index of the highest scoring interval among those that overlap with the
+// given value, as scored by score for each matching original index, such as the highest priority matching rule.
+// Ties are broken by the lowest index, so that the result does not depend on the tree layout; NaN scores never
+// win. Returns false if no interval matches.
+func (t *INTree) MaxIncluding(val float64, score func(index int) float64) (int, bool) {
+	return t.bestIncluding(val, score, func(s, best float64) bool { return s > best })
+}
+
+// MinIncluding is the lowest scoring counterpart of MaxIncluding, such as the narrowest matching interval.
+func (t *INTree) MinIncluding(val float64, score func(index int) float64) (int, bool) {
+	return t.bestIncluding(val, score, func(s, best float64) bool { return s < best })
+}
+
+// bestIncluding is an internal utility function, returning the original index of the matching interval
+// whose score is better than any other according to better, breaking ties by the lowest index.
+func (t *INTree) bestIncluding(val float64, score func(index int) float64, better func(s, best float64) bool) (int, bool) {
+	index, best := -1, math.NaN()
+
+	t.traverse(val, val, func(pos int) bool {
+		idx := t.indexes[pos]
+
+		s := score(idx)
+		if math.IsNaN(s) {
+			return true
+		}
+
+		if index < 0 || better(s, best) || s == best && idx < index {
+			index, best = idx, s
+		}
+
+		return true
+	})
+
+	return index, index >= 0
+}
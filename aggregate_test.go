@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_SumIncluding(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		// Bandwidth allocated by overlapping reservations
+		bandwidth := []float64{10.0, 25.0, 5.0}
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 4.0},
+			&testBounds{Lower: 2.0, Upper: 6.0},
+			&testBounds{Lower: 3.0, Upper: 3.5},
+		})
+
+		weight := func(index int) float64 { return bandwidth[index] }
+
+		assert.EqualValues(t, 10.0, tree.SumIncluding(1.0, weight))
+		assert.EqualValues(t, 40.0, tree.SumIncluding(3.0, weight))
+		assert.EqualValues(t, 25.0, tree.SumIncluding(5.0, weight))
+		assert.EqualValues(t, 0.0, tree.SumIncluding(7.0, weight))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, 0.0, intree.NewINTree(nil).SumIncluding(1.0, func(int) float64 { return 1.0 }))
+	})
+}
+
+func Test_Tree_Fold(t *testing.T) {
+	t.Run("Case_Example/including", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		matches := intree.FoldIncluding(tree, 4.3, []int{}, func(acc []int, index int) []int {
+			return append(acc, index)
+		})
+		assert.EqualValues(t, tree.Including(4.3), matches)
+
+		// Narrowest matching interval
+		narrowest := intree.FoldIncluding(tree, 4.3, math.Inf(1), func(acc float64, index int) float64 {
+			l, u := exampleBounds()[index].Limits()
+			return math.Min(acc, u-l)
+		})
+		assert.InDelta(t, 0.8, narrowest, 1e-9) // [4.1, 4.9]
+	})
+	t.Run("Case_Example/overlapping", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		count := intree.FoldOverlapping(tree, 4.0, 6.0, 0, func(acc int, index int) int {
+			return acc + 1
+		})
+		assert.EqualValues(t, len(tree.Overlapping(4.0, 6.0)), count)
+
+		assert.EqualValues(t, -1, intree.FoldOverlapping(tree, 6.0, 4.0, -1, func(acc int, index int) int {
+			return acc + 1
+		}))
+	})
+}
+
+func Test_Tree_MaxIncluding(t *testing.T) {
+	t.Run("Case_Priority", func(t *testing.T) {
+		// Rule priorities, highest wins
+		priority := []float64{1.0, 5.0, 3.0, 5.0}
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 10.0},
+			&testBounds{Lower: 2.0, Upper: 4.0},
+			&testBounds{Lower: 3.0, Upper: 8.0},
+			&testBounds{Lower: 3.5, Upper: 3.8},
+		})
+
+		score := func(index int) float64 { return priority[index] }
+
+		idx, ok := tree.MaxIncluding(1.0, score)
+		assert.True(t, ok)
+		assert.EqualValues(t, 0, idx)
+
+		idx, _ = tree.MaxIncluding(6.0, score)
+		assert.EqualValues(t, 2, idx)
+
+		// Ties are broken by the lowest index
+		idx, _ = tree.MaxIncluding(3.6, score)
+		assert.EqualValues(t, 1, idx)
+
+		idx, ok = tree.MinIncluding(3.6, score)
+		assert.True(t, ok)
+		assert.EqualValues(t, 0, idx)
+	})
+	t.Run("Case_Longest", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 10.0))
+		intervals := tree.Intervals()
+		length := func(index int) float64 { return intervals[index][1] - intervals[index][0] }
+
+		for val := 0.0; val <= 100.0; val += 11.3 {
+			expected, ok := -1, false
+			for _, idx := range tree.IncludingSorted(val) {
+				if !ok || length(idx) > length(expected) {
+					expected, ok = idx, true
+				}
+			}
+
+			idx, found := tree.MaxIncluding(val, length)
+			assert.EqualValues(t, ok, found)
+			assert.EqualValues(t, expected, idx, "at %.1f", val)
+		}
+	})
+	t.Run("Case_Border/no_matches", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		idx, ok := tree.MaxIncluding(-100.0, func(int) float64 { return 1.0 })
+		assert.False(t, ok)
+		assert.EqualValues(t, -1, idx)
+
+		_, ok = tree.MaxIncluding(4.3, func(int) float64 { return math.NaN() })
+		assert.False(t, ok)
+	})
+}
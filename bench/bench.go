@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package bench holds reproducible benchmarks for the intree package, along with a naive linear scan
+// baseline to compare against, so that performance regressions can be caught and break-even points between
+// both validated for a given data distribution. Run them with go test -bench . ./bench.
+package bench
+
+import (
+	"math/rand"
+
+	"github.com/lggomez/intree"
+)
+
+// Interval is a closed interval implementing intree.Bounds.
+type Interval struct {
+	Lower, Upper float64
+}
+
+// Limits accesses the interval limits.
+func (i Interval) Limits() (float64, float64) {
+	return i.Lower, i.Upper
+}
+
+// RandomBounds returns n intervals with lower limits in [0, domain) and lengths in [0, maxLength),
+// drawn from the given seed. The expected number of intervals including a value well inside the domain
+// is n*maxLength/(2*domain).
+func RandomBounds(n int, domain, maxLength float64, seed int64) []intree.Bounds {
+	rnd := rand.New(rand.NewSource(seed))
+	bounds := make([]intree.Bounds, n)
+
+	for i := range bounds {
+		lower := rnd.Float64() * domain
+		bounds[i] = Interval{Lower: lower, Upper: lower + rnd.Float64()*maxLength}
+	}
+
+	return bounds
+}
+
+// Linear is the naive baseline, scanning every interval on each search.
+type Linear struct {
+	limits []float64
+}
+
+// NewLinear creates the baseline from the given Slice of Bounds.
+func NewLinear(bounds []intree.Bounds) *Linear {
+	l := &Linear{limits: make([]float64, 2*len(bounds))}
+
+	for i, b := range bounds {
+		l.limits[2*i], l.limits[2*i+1] = b.Limits()
+	}
+
+	return l
+}
+
+// Including collects the original indices of the intervals that include the given value, in ascending order.
+func (l *Linear) Including(val float64) []int {
+	result := []int{}
+
+	for i := 0; i < len(l.limits); i += 2 {
+		if l.limits[i] <= val && val <= l.limits[i+1] {
+			result = append(result, i/2)
+		}
+	}
+
+	return result
+}
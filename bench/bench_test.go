@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package bench_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/lggomez/intree/bench"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	seed   = 42
+	domain = 1000000.0
+)
+
+// sizes are the interval counts benchmarked, and lengths the maximum interval lengths, setting the
+// expected match count of every query.
+var (
+	sizes   = []int{1000, 10000, 100000, 1000000}
+	lengths = []float64{10.0, 1000.0, 100000.0}
+)
+
+func Test_Linear(t *testing.T) {
+	bounds := bench.RandomBounds(10000, domain, 1000.0, seed)
+	tree := intree.NewINTree(bounds)
+	linear := bench.NewLinear(bounds)
+
+	for val := 0.0; val < domain; val += domain / 1000 {
+		assert.ElementsMatch(t, linear.Including(val), tree.Including(val), "at %v", val)
+	}
+}
+
+func Benchmark_Build(b *testing.B) {
+	for _, n := range sizes {
+		bounds := bench.RandomBounds(n, domain, 1000.0, seed)
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				intree.NewINTree(bounds)
+			}
+		})
+	}
+}
+
+func Benchmark_Including(b *testing.B) {
+	for _, n := range sizes {
+		for _, length := range lengths {
+			bounds := bench.RandomBounds(n, domain, length, seed)
+			tree := intree.NewINTree(bounds)
+
+			b.Run(fmt.Sprintf("n=%d/matches=%d", n, int(float64(n)*length/(2*domain)+0.5)), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					tree.Including(float64(i%1000) * domain / 1000)
+				}
+			})
+		}
+	}
+}
+
+func Benchmark_IncludingInto(b *testing.B) {
+	for _, n := range sizes {
+		tree := intree.NewINTree(bench.RandomBounds(n, domain, 1000.0, seed))
+		buf := make([]int, 0, 64)
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				buf = tree.IncludingInto(float64(i%1000)*domain/1000, buf)
+			}
+		})
+	}
+}
+
+func Benchmark_Linear(b *testing.B) {
+	for _, n := range sizes {
+		for _, length := range lengths {
+			linear := bench.NewLinear(bench.RandomBounds(n, domain, length, seed))
+			b.Run(fmt.Sprintf("n=%d/matches=%d", n, int(float64(n)*length/(2*domain)+0.5)), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					linear.Including(float64(i%1000) * domain / 1000)
+				}
+			})
+		}
+	}
+}
+
+func Benchmark_IncludingInto_EytzingerLayout(b *testing.B) {
+	for _, n := range append(sizes, 4000000) {
+		bounds := bench.RandomBounds(n, domain, 1000.0, seed)
+		buf := make([]int, 0, 64)
+
+		for _, layout := range []struct {
+			name string
+			opts []intree.Option
+		}{
+			{"in-order", nil},
+			{"eytzinger", []intree.Option{intree.WithEytzingerLayout()}},
+		} {
+			tree := intree.NewINTree(bounds, layout.opts...)
+
+			b.Run(fmt.Sprintf("n=%d/%s", n, layout.name), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					buf = tree.IncludingInto(float64(i%1000)*domain/1000, buf)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"math/big"
+	"slices"
+)
+
+// BigBounds is the main interface expected by NewBigTree(); requires Limits method to access the arbitrary
+// precision interval limits, which must not be nil.
+type BigBounds interface {
+	Limits() (lower, upper *big.Float)
+}
+
+// BigTree is the arbitrary precision counterpart of INTree, trading speed for exactness;
+// holds Slice of reference indices and the respective interval limits as big.Float values, compared exactly
+// regardless of their magnitude, so that sub-ULP ranges of float64 remain apart.
+type BigTree struct {
+	indexes []int
+	limits  []*big.Float
+}
+
+// NewBigTree is the arbitrary precision initialization function;
+// creates the tree from the given Slice of BigBounds, copying their limits at their own precision.
+func NewBigTree(bounds []BigBounds) *BigTree {
+	tree := BigTree{indexes: make([]int, len(bounds)), limits: make([]*big.Float, 3*len(bounds))}
+	lowers := make([]*big.Float, len(bounds))
+	uppers := make([]*big.Float, len(bounds))
+
+	for i, b := range bounds {
+		l, u := b.Limits()
+		tree.indexes[i] = i
+		lowers[i], uppers[i] = new(big.Float).Set(l), new(big.Float).Set(u)
+	}
+
+	// Stable sorting keeps the build deterministic, as no pivot source is involved
+	slices.SortStableFunc(tree.indexes, func(i, j int) int {
+		return lowers[i].Cmp(lowers[j])
+	})
+
+	for pos, idx := range tree.indexes {
+		tree.limits[3*pos], tree.limits[3*pos+1] = lowers[idx], uppers[idx]
+	}
+
+	augmentBig(tree.limits, len(tree.indexes))
+
+	return &tree
+}
+
+// Len returns the number of intervals stored in the tree.
+func (t *BigTree) Len() int {
+	return len(t.indexes)
+}
+
+// Including traverses the tree and collects intervals that overlap with the given value, boundaries included.
+func (t *BigTree) Including(val *big.Float) []int {
+	return t.Overlapping(val, val)
+}
+
+// CountIncluding returns the number of intervals that overlap with the given value, without collecting them.
+func (t *BigTree) CountIncluding(val *big.Float) int {
+	count := 0
+
+	t.traverse(val, val, func(int) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+// Overlapping traverses the tree and collects intervals that overlap with the given range, boundaries included.
+// Returns an empty Slice if lower is greater than upper.
+func (t *BigTree) Overlapping(lower, upper *big.Float) []int {
+	result := []int{}
+
+	if lower.Cmp(upper) > 0 {
+		return result
+	}
+
+	t.traverse(lower, upper, func(pos int) bool {
+		result = append(result, t.indexes[pos])
+		return true
+	})
+
+	return result
+}
+
+// traverse is the internal tree search function;
+// calls fn with the position of every node overlapping with the given range, stopping as soon as fn returns false.
+func (t *BigTree) traverse(lower, upper *big.Float, fn func(pos int) bool) {
+	if len(t.indexes) == 0 {
+		return
+	}
+
+	var stock [stockSize]int
+	idxStock := append(stock[:0], 0, len(t.indexes)-1)
+
+	for len(idxStock) > 0 {
+		// Retrieve right and left boundaries from index stock; only non empty ones are pushed
+		n := len(idxStock)
+		lBoundIdx, rBoundIdx := idxStock[n-2], idxStock[n-1]
+		idxStock = idxStock[:n-2]
+
+		centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+		if lower.Cmp(t.limits[3*centerIdx+2]) <= 0 && lBoundIdx < centerIdx {
+			idxStock = append(idxStock, lBoundIdx, centerIdx-1)
+		}
+
+		if t.limits[3*centerIdx].Cmp(upper) <= 0 {
+			if centerIdx < rBoundIdx {
+				idxStock = append(idxStock, centerIdx+1, rBoundIdx)
+			}
+
+			if lower.Cmp(t.limits[3*centerIdx+1]) <= 0 && !fn(centerIdx) {
+				return
+			}
+		}
+	}
+}
+
+// augmentBig is the arbitrary precision counterpart of augment, storing on each node the greatest upper limit
+// of its subtree, and returning it. The augmented limits share the upper limit values.
+func augmentBig(limits []*big.Float, n int) *big.Float {
+	if n < 1 {
+		return nil
+	}
+
+	r := n >> 1
+	max := limits[3*r+1]
+
+	if left := augmentBig(limits[:3*r], r); left != nil && left.Cmp(max) > 0 {
+		max = left
+	}
+
+	if right := augmentBig(limits[3*r+3:], n-r-1); right != nil && right.Cmp(max) > 0 {
+		max = right
+	}
+
+	limits[3*r+2] = max
+
+	return max
+}
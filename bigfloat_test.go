@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+type bigTestBounds struct {
+	Lower, Upper *big.Float
+}
+
+func (bb bigTestBounds) Limits() (*big.Float, *big.Float) {
+	return bb.Lower, bb.Upper
+}
+
+// bigOffset returns 1 + n * 2^-100, whose offset is far below the float64 ULP of 1
+func bigOffset(n int64) *big.Float {
+	offset := new(big.Float).SetMantExp(big.NewFloat(float64(n)), -100)
+
+	return new(big.Float).SetPrec(200).Add(offset, big.NewFloat(1))
+}
+
+func Test_BigTree(t *testing.T) {
+	t.Run("Case_Sub_ULP_ranges", func(t *testing.T) {
+		tree := intree.NewBigTree([]intree.BigBounds{
+			bigTestBounds{Lower: bigOffset(1), Upper: bigOffset(2)},
+			bigTestBounds{Lower: bigOffset(4), Upper: bigOffset(6)},
+			bigTestBounds{Lower: bigOffset(0), Upper: bigOffset(5)},
+		})
+
+		assert.EqualValues(t, 3, tree.Len())
+		assert.ElementsMatch(t, []int{0, 2}, tree.Including(bigOffset(2)))
+		assert.ElementsMatch(t, []int{2}, tree.Including(bigOffset(3)))
+		assert.ElementsMatch(t, []int{1, 2}, tree.Including(bigOffset(5)))
+		assert.ElementsMatch(t, []int{1}, tree.Including(bigOffset(6)))
+		assert.Empty(t, tree.Including(bigOffset(7)))
+		assert.EqualValues(t, 2, tree.CountIncluding(bigOffset(4)))
+		assert.ElementsMatch(t, []int{0, 1, 2}, tree.Overlapping(bigOffset(2), bigOffset(4)))
+		assert.Empty(t, tree.Overlapping(bigOffset(4), bigOffset(2)))
+
+		// The same ranges collapse into a single point as float64
+		one, _ := bigOffset(3).Float64()
+		assert.EqualValues(t, 1, one)
+	})
+	t.Run("Case_Random/linear_scan", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		bounds := make([]intree.BigBounds, 500)
+		limits := make([][2]int64, len(bounds))
+
+		for i := range bounds {
+			lower := rnd.Int63n(1000)
+			upper := lower + rnd.Int63n(50)
+			limits[i] = [2]int64{lower, upper}
+			bounds[i] = bigTestBounds{Lower: bigOffset(lower), Upper: bigOffset(upper)}
+		}
+
+		tree := intree.NewBigTree(bounds)
+
+		for n := int64(0); n < 1100; n += 7 {
+			expected := []int{}
+			for i, l := range limits {
+				if l[0] <= n && n <= l[1] {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.Including(bigOffset(n)), "value %d", n)
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewBigTree(nil)
+
+		assert.EqualValues(t, 0, tree.Len())
+		assert.Empty(t, tree.Including(big.NewFloat(1)))
+		assert.EqualValues(t, 0, tree.CountIncluding(big.NewFloat(1)))
+	})
+}
@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "math/bits"
+
+// Bitset is a compact set of interval indices, holding one bit per interval of the tree it was collected from.
+// Bitsets of the same tree can be combined with And and Or, as in the results of multiple searches.
+type Bitset struct {
+	words []uint64
+}
+
+// IncludingBitset is the bitset counterpart of Including;
+// collects the indices of the intervals that overlap with the given value into a Bitset,
+// taking a single bit per interval of the tree instead of a Slice entry per match.
+func (t *INTree) IncludingBitset(val float64) *Bitset {
+	b := &Bitset{words: make([]uint64, (len(t.indexes)+63)/64)}
+
+	t.traverse(val, val, func(pos int) bool {
+		idx := t.indexes[pos]
+		b.words[idx/64] |= 1 << (idx % 64)
+
+		return true
+	})
+
+	return b
+}
+
+// Contains reports whether the given index is in the set.
+func (b *Bitset) Contains(idx int) bool {
+	return idx >= 0 && idx/64 < len(b.words) && b.words[idx/64]&(1<<(idx%64)) != 0
+}
+
+// Count returns the number of indices in the set.
+func (b *Bitset) Count() int {
+	count := 0
+
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+
+	return count
+}
+
+// And intersects the set with the given one in place, returning the set.
+// Indices past the length of the given set are removed.
+func (b *Bitset) And(other *Bitset) *Bitset {
+	for i := range b.words {
+		if i < len(other.words) {
+			b.words[i] &= other.words[i]
+		} else {
+			b.words[i] = 0
+		}
+	}
+
+	return b
+}
+
+// Or merges the given set into the set in place, returning the set.
+// The set grows to hold the indices of the given set if it is longer.
+func (b *Bitset) Or(other *Bitset) *Bitset {
+	if len(other.words) > len(b.words) {
+		b.words = append(b.words, make([]uint64, len(other.words)-len(b.words))...)
+	}
+
+	for i, w := range other.words {
+		b.words[i] |= w
+	}
+
+	return b
+}
+
+// Iterate calls fn with every index in the set in ascending order, stopping as soon as fn returns false.
+func (b *Bitset) Iterate(fn func(idx int) bool) {
+	for i, w := range b.words {
+		for w != 0 {
+			if !fn(64*i + bits.TrailingZeros64(w)) {
+				return
+			}
+
+			// Clear the lowest set bit
+			w &= w - 1
+		}
+	}
+}
+
+// Indices returns the indices in the set in ascending order.
+func (b *Bitset) Indices() []int {
+	result := make([]int, 0, b.Count())
+
+	b.Iterate(func(idx int) bool {
+		result = append(result, idx)
+		return true
+	})
+
+	return result
+}
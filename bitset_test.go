@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_IncludingBitset(t *testing.T) {
+	t.Run("Case_Matches", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 10.0))
+
+		for val := 0.0; val <= 100.0; val += 4.9 {
+			b := tree.IncludingBitset(val)
+			expected := tree.IncludingSorted(val)
+
+			assert.EqualValues(t, expected, b.Indices(), "at %.1f", val)
+			assert.EqualValues(t, len(expected), b.Count())
+
+			for _, idx := range expected {
+				assert.True(t, b.Contains(idx))
+			}
+		}
+	})
+	t.Run("Case_SetOperations", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 20.0))
+
+		and := tree.IncludingBitset(40.0).And(tree.IncludingBitset(45.0))
+		assert.EqualValues(t, slices.Sorted(slices.Values(tree.IncludingAllOf([]float64{40.0, 45.0}))), and.Indices())
+
+		or := tree.IncludingBitset(10.0).Or(tree.IncludingBitset(80.0))
+		assert.EqualValues(t, slices.Sorted(slices.Values(tree.IncludingAnyOf([]float64{10.0, 80.0}))), or.Indices())
+	})
+	t.Run("Case_Iterate", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		b := tree.IncludingBitset(4.3)
+
+		first := []int{}
+		b.Iterate(func(idx int) bool {
+			first = append(first, idx)
+			return len(first) < 2
+		})
+
+		assert.EqualValues(t, tree.IncludingSorted(4.3)[:2], first)
+	})
+	t.Run("Case_Border/out_of_range", func(t *testing.T) {
+		b := intree.NewINTree(exampleBounds()).IncludingBitset(4.3)
+		assert.False(t, b.Contains(-1))
+		assert.False(t, b.Contains(1000))
+
+		empty := intree.NewINTree(nil).IncludingBitset(4.3)
+		assert.Zero(t, empty.Count())
+		assert.Empty(t, empty.Indices())
+		assert.Zero(t, b.And(empty).Count())
+	})
+}
+
+func Benchmark_IncludingBitset(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 100.0))
+
+	b.Run("Including", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tree.Including(float64(i % 1000))
+		}
+	})
+	b.Run("IncludingBitset", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tree.IncludingBitset(float64(i % 1000))
+		}
+	})
+}
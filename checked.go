@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInvalidBounds is returned by the checked initialization functions when an interval has NaN or infinite
+// limits, or a lower limit greater than its upper limit.
+var ErrInvalidBounds = errors.New("intree: invalid bounds")
+
+// NewINTreeChecked is the validating counterpart of NewINTree;
+// returns an ErrInvalidBounds wrapped error describing the first invalid interval and its index, if any.
+func NewINTreeChecked(bounds []Bounds, opts ...Option) (*INTree, error) {
+	for i, b := range bounds {
+		if err := checkBounds(i, b); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewINTree(bounds, opts...), nil
+}
+
+// NewINTreeVChecked is the validating counterpart of NewINTreeV;
+// returns an ErrInvalidBounds wrapped error describing the first invalid interval and its index, if any.
+func NewINTreeVChecked(bounds []ValuedBounds, opts ...Option) (*INTree, error) {
+	for i, b := range bounds {
+		if err := checkBounds(i, b); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewINTreeV(bounds, opts...), nil
+}
+
+// BuildReport lists the intervals that often reveal upstream data bugs, as found by NewINTreeWithReport.
+type BuildReport struct {
+	// Duplicates holds the groups of indices of intervals with equal limits, each in ascending order
+	Duplicates [][]int
+	// Points holds the indices of zero length intervals, in ascending order
+	Points []int
+	// Chains holds containment chains: indices of intervals each containing the following one, from one that
+	// no other interval contains down to one that contains no other. Only the first interval of each group
+	// of duplicates takes part in chains.
+	Chains [][]int
+}
+
+// NewINTreeWithReport is the reporting counterpart of NewINTreeChecked;
+// also returns a BuildReport of the duplicate, zero length and nested intervals of the given bounds,
+// computed in O(n log n) time plus the length of the chains.
+func NewINTreeWithReport(bounds []Bounds, opts ...Option) (*INTree, BuildReport, error) {
+	tree, err := NewINTreeChecked(bounds, opts...)
+	if err != nil {
+		return nil, BuildReport{}, err
+	}
+
+	return tree, buildReport(tree.Intervals()), nil
+}
+
+// buildReport is an internal utility function, computing the BuildReport of the given interval limits.
+func buildReport(limits [][2]float64) BuildReport {
+	report := BuildReport{Duplicates: [][]int{}, Points: []int{}, Chains: [][]int{}}
+
+	// Containers sort before the intervals they contain: by ascending lower limit, then descending upper limit
+	order := make([]int, len(limits))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(i, j int) int {
+		if c := cmp.Compare(limits[i][0], limits[j][0]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(limits[j][1], limits[i][1])
+	})
+
+	// An interval is contained in another one if any interval sorted before it reaches its upper limit; the latest
+	// such one is the tightest container. Candidates ending before a later interval are dropped for good, as the
+	// later one both reaches further and sorts closer to the following intervals
+	parent := make([]int, len(limits))
+	containers := []int{}
+
+	for n, idx := range order {
+		parent[idx] = -1
+
+		if limits[idx][0] == limits[idx][1] {
+			report.Points = append(report.Points, idx)
+		}
+
+		// Duplicates sort next to each other in ascending index order; followers are flagged with -2
+		if n > 0 && limits[order[n-1]] == limits[idx] {
+			if prev := order[n-1]; parent[prev] != -2 {
+				report.Duplicates = append(report.Duplicates, []int{prev})
+			}
+
+			last := len(report.Duplicates) - 1
+			report.Duplicates[last] = append(report.Duplicates[last], idx)
+			parent[idx] = -2
+
+			continue
+		}
+
+		for len(containers) > 0 && limits[containers[len(containers)-1]][1] < limits[idx][1] {
+			containers = containers[:len(containers)-1]
+		}
+
+		if len(containers) > 0 {
+			parent[idx] = containers[len(containers)-1]
+		}
+
+		containers = append(containers, idx)
+	}
+
+	// An interval contains another one if any interval sorted after it, other than its duplicates,
+	// ends within it; chains end at the contained intervals that contain no other
+	nearest := math.Inf(1)
+	ends := make([]bool, len(limits))
+
+	for n := len(order) - 1; n >= 0; n-- {
+		if idx := order[n]; parent[idx] != -2 {
+			ends[idx] = parent[idx] >= 0 && nearest > limits[idx][1]
+			nearest = math.Min(nearest, limits[idx][1])
+		}
+	}
+
+	for _, idx := range order {
+		if !ends[idx] {
+			continue
+		}
+
+		chain := []int{idx}
+		for p := parent[idx]; p >= 0; p = parent[p] {
+			chain = append(chain, p)
+		}
+
+		slices.Reverse(chain)
+		report.Chains = append(report.Chains, chain)
+	}
+
+	slices.Sort(report.Points)
+
+	return report
+}
+
+// checkBounds is an internal utility function, validating the limits of the interval at the given index.
+func checkBounds(index int, b Bounds) error {
+	if b == nil {
+		return fmt.Errorf("%w: nil bounds at index %d", ErrInvalidBounds, index)
+	}
+
+	lower, upper := b.Limits()
+
+	return checkLimits(index, lower, upper)
+}
+
+// checkLimits is an internal utility function, returning an ErrInvalidBounds wrapped error if the given limits
+// are NaN or infinite, or the lower one is greater than the upper one.
+func checkLimits(index int, lower, upper float64) error {
+	switch {
+	case math.IsNaN(lower) || math.IsInf(lower, 0):
+		return fmt.Errorf("%w: non finite lower limit %v at index %d", ErrInvalidBounds, lower, index)
+	case math.IsNaN(upper) || math.IsInf(upper, 0):
+		return fmt.Errorf("%w: non finite upper limit %v at index %d", ErrInvalidBounds, upper, index)
+	case lower > upper:
+		return fmt.Errorf("%w: lower limit %v greater than upper limit %v at index %d", ErrInvalidBounds, lower, upper, index)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Checked(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree, err := intree.NewINTreeChecked(exampleBounds())
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+	})
+	t.Run("Case_Example/valued", func(t *testing.T) {
+		tree, err := intree.NewINTreeVChecked([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 0.0, Upper: 2.0, value: 1},
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []interface{}{1}, tree.IncludingValues(1.0))
+
+		_, err = intree.NewINTreeVChecked([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 3.0, Upper: 2.0, value: 1},
+		})
+		assert.True(t, errors.Is(err, intree.ErrInvalidBounds))
+	})
+	t.Run("Case_Invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			lower, upper float64
+			message      string
+		}{
+			{math.NaN(), 1.0, "intree: invalid bounds: non finite lower limit NaN at index 1"},
+			{math.Inf(-1), 1.0, "intree: invalid bounds: non finite lower limit -Inf at index 1"},
+			{0.0, math.NaN(), "intree: invalid bounds: non finite upper limit NaN at index 1"},
+			{0.0, math.Inf(1), "intree: invalid bounds: non finite upper limit +Inf at index 1"},
+			{2.0, 1.0, "intree: invalid bounds: lower limit 2 greater than upper limit 1 at index 1"},
+		} {
+			tree, err := intree.NewINTreeChecked([]intree.Bounds{
+				&testBounds{Lower: 0.0, Upper: 1.0},
+				&testBounds{Lower: tc.lower, Upper: tc.upper},
+			})
+
+			assert.Nil(t, tree)
+			assert.True(t, errors.Is(err, intree.ErrInvalidBounds))
+			assert.EqualError(t, err, tc.message)
+		}
+	})
+	t.Run("Case_Border/point_interval", func(t *testing.T) {
+		_, err := intree.NewINTreeChecked([]intree.Bounds{&testBounds{Lower: 1.0, Upper: 1.0}})
+		assert.NoError(t, err)
+	})
+	t.Run("Case_Border/nil_element", func(t *testing.T) {
+		_, err := intree.NewINTreeChecked([]intree.Bounds{nil})
+		assert.EqualError(t, err, "intree: invalid bounds: nil bounds at index 0")
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree, err := intree.NewINTreeChecked(nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0, tree.Len())
+	})
+}
+
+func Test_Tree_WithReport(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree, report, err := intree.NewINTreeWithReport([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 10.0},
+			&testBounds{Lower: 2.0, Upper: 8.0},
+			&testBounds{Lower: 3.0, Upper: 3.0},
+			&testBounds{Lower: 2.0, Upper: 8.0},
+			&testBounds{Lower: 9.0, Upper: 12.0},
+			&testBounds{Lower: 11.0, Upper: 11.5},
+			&testBounds{Lower: 20.0, Upper: 21.0},
+			&testBounds{Lower: 2.0, Upper: 8.0},
+			&testBounds{Lower: 20.0, Upper: 20.0},
+			&testBounds{Lower: 20.0, Upper: 20.0},
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 10, tree.Len())
+
+		assert.EqualValues(t, [][]int{{1, 3, 7}, {8, 9}}, report.Duplicates)
+		assert.EqualValues(t, []int{2, 8, 9}, report.Points)
+		assert.EqualValues(t, [][]int{{0, 1, 2}, {4, 5}, {6, 8}}, report.Chains)
+	})
+	t.Run("Case_Chains", func(t *testing.T) {
+		bounds := randomBounds(300, 100.0, 30.0)
+		_, report, err := intree.NewINTreeWithReport(bounds)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, report.Chains)
+
+		contains := func(outer, inner int) bool {
+			ol, ou := bounds[outer].Limits()
+			il, iu := bounds[inner].Limits()
+			return ol <= il && iu <= ou
+		}
+
+		ends := map[int]bool{}
+		for _, chain := range report.Chains {
+			assert.GreaterOrEqual(t, len(chain), 2)
+			for i := 1; i < len(chain); i++ {
+				assert.True(t, contains(chain[i-1], chain[i]), "chain %v", chain)
+			}
+
+			for j := range bounds {
+				assert.False(t, j != chain[0] && contains(j, chain[0]), "chain %v starts inside %d", chain, j)
+				assert.False(t, j != chain[len(chain)-1] && contains(chain[len(chain)-1], j), "chain %v ends around %d", chain, j)
+			}
+
+			ends[chain[len(chain)-1]] = true
+		}
+
+		// Every contained interval containing no other one ends a chain
+		for i := range bounds {
+			contained, container := false, false
+			for j := range bounds {
+				contained = contained || j != i && contains(j, i)
+				container = container || j != i && contains(i, j)
+			}
+
+			assert.EqualValues(t, contained && !container, ends[i], "at index %d", i)
+		}
+	})
+	t.Run("Case_Invalid", func(t *testing.T) {
+		tree, _, err := intree.NewINTreeWithReport([]intree.Bounds{&testBounds{Lower: 2.0, Upper: 1.0}})
+		assert.Nil(t, tree)
+		assert.True(t, errors.Is(err, intree.ErrInvalidBounds))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		_, report, err := intree.NewINTreeWithReport(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, report.Duplicates)
+		assert.Empty(t, report.Points)
+		assert.Empty(t, report.Chains)
+	})
+}
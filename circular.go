@@ -0,0 +1,173 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrInvalidModulus is returned by NewCircularTree given a modulus that is not a positive finite number.
+var ErrInvalidModulus = errors.New("intree: invalid modulus")
+
+// CircularTree is the circular domain counterpart of INTree, for values wrapping around a modulus such as hours
+// of a 24h clock or angles; intervals whose upper limit is below their lower limit, such as [22, 2] on
+// a 24h clock, wrap around it. Returns indices to the initial bounds array, in ascending order.
+type CircularTree struct {
+	tree    *INTree
+	modulus float64
+	// sources maps the inner tree indices to the original ones
+	sources []int
+	// whole holds the indices of the intervals spanning a whole period, which include every value
+	whole []int
+	n     int
+}
+
+// NewCircularTree is the circular domain initialization function;
+// creates a tree over the [0, modulus) domain from the given Slice of Bounds, whose limits are taken modulo
+// the given modulus. Intervals are stored unsplit, starting within the domain and ending up to a period past it,
+// so that the tree endpoints apply to the original limits even across the wrap point.
+// Returns an ErrInvalidModulus wrapped error if the modulus is not a positive finite number.
+func NewCircularTree(bounds []Bounds, modulus float64, opts ...Option) (*CircularTree, error) {
+	if !(modulus > 0) || math.IsInf(modulus, 1) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModulus, modulus)
+	}
+
+	t := CircularTree{modulus: modulus, n: len(bounds)}
+	unwrapped := make([]Bounds, 0, len(bounds))
+
+	for i, b := range bounds {
+		lower, upper := b.Limits()
+
+		length := upper - lower
+		if length < 0 {
+			length += modulus
+		}
+
+		if length >= modulus {
+			t.whole = append(t.whole, i)
+			continue
+		}
+
+		lower = t.normalize(lower)
+		unwrapped = append(unwrapped, Interval{Lower: lower, Upper: lower + length})
+		t.sources = append(t.sources, i)
+	}
+
+	t.tree = NewINTree(unwrapped, opts...)
+
+	return &t, nil
+}
+
+// Len returns the number of intervals stored in the tree.
+func (t *CircularTree) Len() int {
+	return t.n
+}
+
+// Modulus returns the period of the tree domain.
+func (t *CircularTree) Modulus() float64 {
+	return t.modulus
+}
+
+// Including traverses the tree and collects the intervals that include the given value, taken modulo the
+// tree modulus. Values are searched for one period up as well, matching the part of the intervals that wrap
+// around past the modulus. Returns an empty Slice if the value is NaN or infinite.
+func (t *CircularTree) Including(val float64) []int {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return []int{}
+	}
+
+	result := slices.Clone(t.whole)
+	val = t.normalize(val)
+
+	for _, v := range [2]float64{val, val + t.modulus} {
+		t.tree.IncludingFunc(v, func(idx int) bool {
+			result = append(result, t.sources[idx])
+			return true
+		})
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// Overlapping traverses the tree and collects the intervals that overlap with the given range, whose limits
+// are taken modulo the tree modulus; ranges whose upper limit is below their lower limit wrap around.
+// Returns an empty Slice if either limit is NaN or infinite.
+func (t *CircularTree) Overlapping(lower, upper float64) []int {
+	if math.IsNaN(lower) || math.IsNaN(upper) || math.IsInf(lower, 0) || math.IsInf(upper, 0) {
+		return []int{}
+	}
+
+	length := upper - lower
+	if length < 0 {
+		length += t.modulus
+	}
+
+	result := slices.Clone(t.whole)
+
+	if length >= t.modulus {
+		result = append(result, t.sources...)
+		slices.Sort(result)
+
+		return result
+	}
+
+	lower = t.normalize(lower)
+
+	// Stored intervals start within [0, modulus) and end before 2*modulus, so that ranges shifted one period
+	// down or up catch both the wrapping intervals and the wrapping range
+	seen := map[int]bool{}
+
+	for _, shift := range [3]float64{-t.modulus, 0, t.modulus} {
+		for _, idx := range t.tree.Overlapping(lower+shift, lower+length+shift) {
+			if !seen[idx] {
+				seen[idx] = true
+				result = append(result, t.sources[idx])
+			}
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// normalize is an internal utility function, mapping the given finite value into the [0, modulus) domain.
+func (t *CircularTree) normalize(val float64) float64 {
+	val = math.Mod(val, t.modulus)
+
+	if val < 0 {
+		val += t.modulus
+	}
+
+	// Adding the modulus to tiny negative remainders rounds up to it
+	if val >= t.modulus {
+		val = 0
+	}
+
+	return val
+}
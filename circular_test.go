@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+// circularOffset returns the distance from a to b going up around a circle of the given modulus.
+func circularOffset(a, b, modulus float64) float64 {
+	d := math.Mod(b-a, modulus)
+	if d < 0 {
+		d += modulus
+	}
+
+	return d
+}
+
+func Test_CircularTree_Including(t *testing.T) {
+	t.Run("Case_Clock", func(t *testing.T) {
+		tree, err := intree.NewCircularTree([]intree.Bounds{
+			intree.Interval{Lower: 22.0, Upper: 2.0}, // night shift
+			intree.Interval{Lower: 9.0, Upper: 17.0},
+			intree.Interval{Lower: 23.0, Upper: 25.0},
+			intree.Interval{Lower: -1.0, Upper: 0.5},
+			intree.Interval{Lower: 0.0, Upper: 24.0},
+		}, 24.0)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 5, tree.Len())
+		assert.EqualValues(t, 24.0, tree.Modulus())
+
+		assert.EqualValues(t, []int{0, 4}, tree.Including(22.0))
+		assert.EqualValues(t, []int{0, 2, 3, 4}, tree.Including(0.0))
+		assert.EqualValues(t, []int{0, 2, 3, 4}, tree.Including(24.0))
+		assert.EqualValues(t, []int{0, 2, 4}, tree.Including(1.0))
+		assert.EqualValues(t, []int{0, 2, 4}, tree.Including(-23.0))
+		assert.EqualValues(t, []int{1, 4}, tree.Including(12.0))
+		assert.EqualValues(t, []int{4}, tree.Including(3.0))
+		assert.Empty(t, tree.Including(math.NaN()))
+
+		assert.EqualValues(t, []int{0, 2, 3, 4}, tree.Overlapping(23.5, 0.5))
+		assert.EqualValues(t, []int{0, 1, 2, 3, 4}, tree.Overlapping(16.0, 15.0))
+		assert.EqualValues(t, []int{1, 4}, tree.Overlapping(5.0, 9.0))
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		tree, err := intree.NewCircularTree([]intree.Bounds{
+			intree.Interval{Lower: 350.0, Upper: 10.0},
+			intree.Interval{Lower: 10.0, Upper: 20.0},
+		}, 360.0, intree.WithEndpoints(intree.UpperOpen))
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, []int{0}, tree.Including(0.0))
+		assert.EqualValues(t, []int{0}, tree.Including(350.0))
+		assert.EqualValues(t, []int{1}, tree.Including(10.0))
+		assert.EqualValues(t, []int{1}, tree.Including(370.0))
+	})
+	t.Run("Case_Reference", func(t *testing.T) {
+		const modulus = 24.0
+		rnd := rand.New(rand.NewSource(3))
+		bounds := make([]intree.Bounds, 500)
+		for i := range bounds {
+			lower := float64(rnd.Intn(96)-24) / 2
+			bounds[i] = intree.Interval{Lower: lower, Upper: lower + float64(rnd.Intn(40))/2}
+		}
+
+		tree, err := intree.NewCircularTree(bounds, modulus)
+		assert.NoError(t, err)
+
+		for val := -30.0; val <= 30.0; val += 0.25 {
+			expected := []int{}
+			for i, b := range bounds {
+				l, u := b.Limits()
+				if u-l >= modulus || circularOffset(l, val, modulus) <= u-l {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.EqualValues(t, expected, tree.Including(val), "at %.2f", val)
+		}
+
+		for lower := -12.0; lower <= 36.0; lower += 1.25 {
+			for _, length := range []float64{0.0, 0.5, 3.0, 11.75, 23.5, 30.0} {
+				expected := []int{}
+				for i, b := range bounds {
+					l, u := b.Limits()
+					if u-l >= modulus || length >= modulus ||
+						circularOffset(lower, l, modulus) <= length || circularOffset(l, lower, modulus) <= u-l {
+						expected = append(expected, i)
+					}
+				}
+
+				assert.EqualValues(t, expected, tree.Overlapping(lower, lower+length), "at %.2f+%.2f", lower, length)
+			}
+		}
+	})
+	t.Run("Case_Border/invalid_modulus", func(t *testing.T) {
+		for _, modulus := range []float64{0.0, -1.0, math.NaN(), math.Inf(1)} {
+			_, err := intree.NewCircularTree(nil, modulus)
+			assert.ErrorIs(t, err, intree.ErrInvalidModulus)
+		}
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		tree, err := intree.NewCircularTree(nil, 24.0)
+		assert.NoError(t, err)
+		assert.Empty(t, tree.Including(1.0))
+		assert.Empty(t, tree.Overlapping(22.0, 2.0))
+	})
+}
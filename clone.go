@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the tree, so that a consistent snapshot can be handed to background analysis
+// while the tree keeps being updated. Node Slices, values, retained bounds, staged intervals, removed interval
+// flags, metadata, priorities, the Eytzinger layout and the lower ordered view are all copied; the values, bounds and annotations themselves are
+// shared. Clones of read-only trees, such as memory mapped ones, live on the heap and remain usable once the
+// original is closed.
+func (t *INTree) Clone() *INTree {
+	clone := INTree{
+		indexes:             slices.Clone(t.indexes),
+		limits:              slices.Clone(t.limits),
+		positions:           slices.Clone(t.positions),
+		values:              slices.Clone(t.values),
+		bounds:              slices.Clone(t.bounds),
+		staged:              slices.Clone(t.staged),
+		buffered:            t.buffered,
+		unordered:           t.unordered,
+		endpoints:           t.endpoints,
+		tombstones:          slices.Clone(t.tombstones),
+		removed:             t.removed,
+		compactionThreshold: t.compactionThreshold,
+		metadata:            maps.Clone(t.metadata),
+		priorities:          slices.Clone(t.priorities),
+		priorityMax:         slices.Clone(t.priorityMax),
+		traceHook:           t.traceHook,
+	}
+
+	if t.layout != nil {
+		clone.layout = &eytzinger{limits: slices.Clone(t.layout.limits), positions: slices.Clone(t.layout.positions)}
+	}
+
+	if t.view != nil {
+		clone.view = &orderedView{
+			indexes:     slices.Clone(t.view.indexes),
+			limits:      slices.Clone(t.view.limits),
+			positions:   slices.Clone(t.view.positions),
+			priorityMax: slices.Clone(t.view.priorityMax),
+		}
+	}
+
+	return &clone
+}
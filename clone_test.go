@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Clone(t *testing.T) {
+	t.Run("Case_Snapshot", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithRetainedBounds(), intree.WithEytzingerLayout(),
+			intree.WithEndpoints(intree.UpperOpen))
+		clone := tree.Clone()
+
+		assert.NoError(t, clone.CheckInvariants())
+		assert.EqualValues(t, tree.Intervals(), clone.Intervals())
+		assert.EqualValues(t, intree.UpperOpen, clone.Endpoints())
+		assert.ElementsMatch(t, tree.Including(4.3), clone.Including(4.3))
+		assert.ElementsMatch(t, tree.IncludingBounds(4.3), clone.IncludingBounds(4.3))
+
+		// Updates on either tree must not leak into the other
+		tree.Insert(&testBounds{Lower: 4.0, Upper: 4.5})
+		assert.NoError(t, tree.Delete(0))
+		assert.NoError(t, clone.Delete(2))
+
+		assert.ElementsMatch(t, []int{0, 5, 8, 10}, clone.Including(4.3))
+		assert.ElementsMatch(t, []int{2, 5, 8, 10, 13}, tree.Including(4.3))
+		assert.NoError(t, tree.CheckInvariants())
+		assert.NoError(t, clone.CheckInvariants())
+	})
+	t.Run("Case_Mapped", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		mapped, err := intree.OpenMMap(writeTree(t, data))
+		assert.NoError(t, err)
+
+		clone := mapped.Clone()
+		assert.NoError(t, mapped.Close())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, clone.Including(4.3))
+	})
+	t.Run("Case_Border/zero_value", func(t *testing.T) {
+		clone := (&intree.INTree{}).Clone()
+
+		assert.EqualValues(t, 0, clone.Len())
+		assert.Empty(t, clone.Including(1.0))
+	})
+}
@@ -0,0 +1,217 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command intree builds interval trees from CSV, TSV, JSON or binary files (or standard input), optionally
+// writes them in the binary encoding, and answers stabbing and range queries over them.
+//
+// Usage:
+//
+//	intree [flags]
+//
+// CSV and TSV input hold one lower,upper pair per record, as read by intree.LoadCSV; JSON input holds an array
+// of [lower, upper] pairs; binary input is a tree written by a previous run with -out. Every query prints a line
+// with the query followed by the ascending original indices of the matching intervals, which are numbered in
+// input order.
+//
+// Examples:
+//
+//	intree -in slots.csv -at 4.3 -at 10
+//	intree -in slots.json -out slots.bin
+//	cat slots.csv | intree -range 1:3
+//	intree -in slots.bin -at 4.3
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lggomez/intree"
+)
+
+// Input formats.
+const (
+	formatCSV    = "csv"
+	formatTSV    = "tsv"
+	formatJSON   = "json"
+	formatBinary = "bin"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		// Usage was already printed by the flag set
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+
+		fmt.Fprintln(os.Stderr, "intree:", err)
+		os.Exit(1)
+	}
+}
+
+// run is the command entry point, reading input from stdin unless a file is given, writing
+// query results to stdout and usage errors to stderr.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("intree", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	in := fs.String("in", "-", "input `file`, or - for standard input")
+	format := fs.String("format", "", "input `format`: csv, tsv, json or bin (defaults to the input file extension, or csv)")
+	header := fs.Bool("header", false, "skip the first CSV or TSV record")
+	out := fs.String("out", "", "write the tree binary encoding to `file`")
+	stats := fs.Bool("stats", false, "print the tree statistics")
+
+	var points []float64
+	var ranges [][2]float64
+
+	fs.Func("at", "print the intervals including `value` (repeatable)", func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		points = append(points, v)
+
+		return err
+	})
+	fs.Func("range", "print the intervals overlapping `lower:upper` (repeatable)", func(s string) error {
+		l, u, ok := strings.Cut(s, ":")
+		if !ok {
+			return fmt.Errorf("missing ':' in range %q", s)
+		}
+
+		lower, err := strconv.ParseFloat(l, 64)
+		if err != nil {
+			return err
+		}
+
+		upper, err := strconv.ParseFloat(u, 64)
+		ranges = append(ranges, [2]float64{lower, upper})
+
+		return err
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*in), ".")
+		if *format != formatTSV && *format != formatJSON && *format != formatBinary {
+			*format = formatCSV
+		}
+	}
+
+	r := stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	tree, err := load(r, *format, *header)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		data, err := tree.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(*out, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	if *stats {
+		s := tree.Stats()
+		fmt.Fprintf(stdout, "nodes %d, depth %d, degenerate %d, inverted %d, limits [%v, %v], %d bytes\n",
+			s.Nodes, s.Depth, s.Degenerate, s.Inverted, s.Min, s.Max, s.MemoryBytes)
+	}
+
+	for _, v := range points {
+		printMatches(stdout, fmt.Sprintf("at %v", v), tree.IncludingSorted(v))
+	}
+
+	for _, rg := range ranges {
+		matches := tree.Overlapping(rg[0], rg[1])
+		slices.Sort(matches)
+		printMatches(stdout, fmt.Sprintf("range %v:%v", rg[0], rg[1]), matches)
+	}
+
+	return nil
+}
+
+// load reads a tree from the given input in the given format.
+func load(r io.Reader, format string, header bool) (*intree.INTree, error) {
+	switch format {
+	case formatCSV, formatTSV:
+		opts := intree.LoadOptions{Header: header}
+		if format == formatTSV {
+			opts.Comma = '\t'
+		}
+
+		bounds, err := intree.LoadCSV(r, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return intree.NewINTreeChecked(bounds)
+	case formatJSON:
+		var set intree.IntervalSet
+		if err := json.NewDecoder(r).Decode(&set); err != nil {
+			return nil, fmt.Errorf("decoding JSON intervals: %w", err)
+		}
+
+		return intree.NewINTreeChecked(set.Bounds())
+	case formatBinary:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		tree := &intree.INTree{}
+
+		return tree, tree.UnmarshalBinary(data)
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// printMatches prints a query result line.
+func printMatches(w io.Writer, query string, matches []int) {
+	fields := make([]string, len(matches))
+	for i, idx := range matches {
+		fields[i] = strconv.Itoa(idx)
+	}
+
+	fmt.Fprintf(w, "%s: %s\n", query, strings.Join(fields, " "))
+}
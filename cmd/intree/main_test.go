@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleCSV = `lower,upper
+0,2
+5,6
+1,3
+`
+
+func Test_Run(t *testing.T) {
+	t.Run("Case_CSV", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := run([]string{"-header", "-at", "1.5", "-at", "4", "-range", "2.5:5"}, strings.NewReader(exampleCSV), out, io.Discard)
+		assert.NoError(t, err)
+		assert.EqualValues(t, "at 1.5: 0 2\nat 4: \nrange 2.5:5: 1 2\n", out.String())
+	})
+	t.Run("Case_TSV", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "intervals.tsv")
+		assert.NoError(t, os.WriteFile(path, []byte("0\t2\n5\t6\n"), 0o600))
+
+		out := &bytes.Buffer{}
+		assert.NoError(t, run([]string{"-in", path, "-at", "5.5"}, nil, out, io.Discard))
+		assert.EqualValues(t, "at 5.5: 1\n", out.String())
+	})
+	t.Run("Case_JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "intervals.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`[[0, 2], [5, 6], [1, 3]]`), 0o600))
+
+		out := &bytes.Buffer{}
+		assert.NoError(t, run([]string{"-in", path, "-at", "5.5"}, nil, out, io.Discard))
+		assert.EqualValues(t, "at 5.5: 1\n", out.String())
+	})
+	t.Run("Case_Binary", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tree.bin")
+
+		out := &bytes.Buffer{}
+		assert.NoError(t, run([]string{"-header", "-out", path}, strings.NewReader(exampleCSV), out, io.Discard))
+		assert.Empty(t, out.String())
+
+		assert.NoError(t, run([]string{"-in", path, "-at", "1.5", "-stats"}, nil, out, io.Discard))
+		assert.EqualValues(t, "nodes 3, depth 2, degenerate 0, inverted 0, limits [0, 6], 120 bytes\nat 1.5: 0 2\n", out.String())
+	})
+	t.Run("Case_Border/invalid_input", func(t *testing.T) {
+		for _, tc := range []struct {
+			args  []string
+			input string
+		}{
+			{[]string{}, "0,2\n5\n"},
+			{[]string{}, "0,x\n"},
+			{[]string{}, "3,2\n"},
+			{[]string{"-format", "json"}, "{"},
+			{[]string{"-format", "bin"}, "INTR"},
+			{[]string{"-format", "xml"}, ""},
+			{[]string{"-range", "1"}, ""},
+			{[]string{"-at", "x"}, ""},
+			{[]string{"-in", filepath.Join(t.TempDir(), "missing.csv")}, ""},
+		} {
+			err := run(tc.args, strings.NewReader(tc.input), &bytes.Buffer{}, io.Discard)
+			assert.Error(t, err, "args %v, input %q", tc.args, tc.input)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Concurrent is a thread-safe INTree wrapper, serving searches concurrently with updates.
+// Searches load the current tree without locking, while updates build or modify a separate tree
+// and atomically swap it in, so searches never wait for writers and never see a partial update.
+type Concurrent struct {
+	// mu serializes writers, so that concurrent updates do not drop each other
+	mu   sync.Mutex
+	tree atomic.Pointer[INTree]
+}
+
+// NewConcurrent wraps the given tree, which must not be accessed directly afterwards.
+// A nil tree is replaced by an empty one.
+func NewConcurrent(t *INTree) *Concurrent {
+	if t == nil {
+		t = NewINTree(nil)
+	}
+
+	c := &Concurrent{}
+	c.tree.Store(t)
+
+	return c
+}
+
+// Including collects the intervals that contain the given value, boundaries included.
+func (c *Concurrent) Including(val float64) []int {
+	return c.tree.Load().Including(val)
+}
+
+// IncludingValues collects the values of the intervals that contain the given value, boundaries included.
+func (c *Concurrent) IncludingValues(val float64) []interface{} {
+	return c.tree.Load().IncludingValues(val)
+}
+
+// Overlapping collects the intervals that overlap with the given range, boundaries included.
+func (c *Concurrent) Overlapping(lower, upper float64) []int {
+	return c.tree.Load().Overlapping(lower, upper)
+}
+
+// CountIncluding counts the intervals that contain the given value, boundaries included.
+func (c *Concurrent) CountIncluding(val float64) int {
+	return c.tree.Load().CountIncluding(val)
+}
+
+// Len returns the number of intervals stored in the tree.
+func (c *Concurrent) Len() int {
+	return c.tree.Load().Len()
+}
+
+// View runs fn with the current tree, for searches made of several queries over the same snapshot.
+// The tree must not be modified by fn.
+func (c *Concurrent) View(fn func(t *INTree)) {
+	fn(c.tree.Load())
+}
+
+// Update runs fn with a clone of the current tree, for in-place updates such as Insert or Delete,
+// and swaps the clone in once fn returns. Cloning takes O(n), so batches of updates belong in a single fn.
+// The tree must not be retained by fn.
+func (c *Concurrent) Update(fn func(t *INTree)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tree := c.tree.Load().Clone()
+	fn(tree)
+	c.tree.Store(tree)
+}
+
+// Swap replaces the current tree with the given one, returning the previous tree.
+// The given tree must not be accessed directly afterwards. A nil tree is replaced by an empty one.
+// Searches started before the swap may still be running on the previous tree, so it must not be modified.
+func (c *Concurrent) Swap(t *INTree) *INTree {
+	if t == nil {
+		t = NewINTree(nil)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tree.Swap(t)
+}
+
+// Refresh builds a new tree from the given bounds and options without holding any lock, then swaps it in.
+func (c *Concurrent) Refresh(bounds []Bounds, opts ...Option) {
+	c.Swap(NewINTree(bounds, opts...))
+}
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Concurrent(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		c := intree.NewConcurrent(intree.NewINTree(exampleBounds()))
+
+		assert.EqualValues(t, 13, c.Len())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, c.Including(4.3))
+		assert.EqualValues(t, 5, c.CountIncluding(4.3))
+
+		c.Update(func(tree *intree.INTree) {
+			tree.Insert(&testBounds{Lower: 4.0, Upper: 4.5})
+		})
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10, 13}, c.Including(4.3))
+
+		previous := c.Swap(intree.NewINTree(exampleBounds()[:3]))
+		assert.EqualValues(t, 14, previous.Len())
+		assert.ElementsMatch(t, []int{0, 2}, c.Including(4.3))
+
+		c.Refresh(nil)
+		assert.EqualValues(t, 0, c.Len())
+		assert.Empty(t, c.Including(4.3))
+	})
+	t.Run("Case_Border/nil_tree", func(t *testing.T) {
+		c := intree.NewConcurrent(nil)
+		assert.EqualValues(t, 0, c.Len())
+		assert.Empty(t, c.Overlapping(0.0, 10.0))
+	})
+	t.Run("Case_Parallel", func(t *testing.T) {
+		bounds := randomBounds(1000, 100.0, 5.0)
+		c := intree.NewConcurrent(intree.NewINTree(bounds))
+		reference := intree.NewINTree(bounds)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for val := 0.0; val < 100.0; val += 0.5 {
+					// Both refreshed trees hold the same intervals, so results stay stable
+					assert.ElementsMatch(t, reference.Including(val), c.Including(val))
+				}
+			}()
+		}
+
+		for i := 0; i < 10; i++ {
+			c.Refresh(bounds)
+		}
+
+		wg.Wait()
+	})
+	t.Run("Case_Parallel/updates", func(t *testing.T) {
+		bounds := randomBounds(1000, 100.0, 5.0)
+		c := intree.NewConcurrent(intree.NewINTree(bounds))
+		reference := intree.NewINTree(bounds)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for val := 0.0; val < 100.0; val += 0.5 {
+					// Updates only add intervals past the searched range, so results stay stable
+					assert.ElementsMatch(t, reference.Including(val), c.Including(val))
+				}
+			}()
+		}
+
+		for i := 0; i < 10; i++ {
+			c.Update(func(tree *intree.INTree) {
+				tree.Insert(&testBounds{Lower: 200.0, Upper: 200.0 + float64(i)})
+			})
+		}
+
+		wg.Wait()
+		assert.EqualValues(t, 1010, c.Len())
+	})
+}
@@ -0,0 +1,593 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+	"slices"
+)
+
+// CoverageLostIfRemoved returns the coordinate segments that would become uncovered
+// if the interval at the given original index were removed from the tree.
+// Only segments of positive length are reported; returns nil for an out of range index.
+func (t *INTree) CoverageLostIfRemoved(index int) [][2]float64 {
+	t = t.lowerOrdered()
+
+	pos := t.position(index)
+	if pos < 0 || t.isRemoved(pos) {
+		return nil
+	}
+
+	return t.exclusiveCoverage(pos)
+}
+
+// CoverageRatio returns the fraction of the given range covered by at least one interval.
+// A degenerate range (lower equal to upper) yields 1 if the point is covered and 0 otherwise;
+// returns 0 if lower is greater than upper.
+func (t *INTree) CoverageRatio(lower, upper float64) float64 {
+	if lower > upper {
+		return 0
+	}
+
+	if lower == upper {
+		if len(t.coverageSegments(lower, upper)) > 0 {
+			return 1
+		}
+
+		return 0
+	}
+
+	return t.CoveredLength(lower, upper) / (upper - lower)
+}
+
+// CoveredLength returns the total length of the given range covered by at least one interval, sweeping
+// the overlapping nodes in order without building the covered segments. Returns 0 if lower is greater than upper.
+func (t *INTree) CoveredLength(lower, upper float64) float64 {
+	if lower > upper {
+		return 0
+	}
+
+	t = t.lowerOrdered()
+	covered := 0.0
+	cursor := lower
+
+	t.overlapsInOrder(0, len(t.indexes)-1, lower, upper, func(pos int) bool {
+		l, u := math.Max(t.limits[3*pos], cursor), math.Min(t.limits[3*pos+1], upper)
+
+		if u > l {
+			covered += u - l
+		}
+
+		cursor = math.Max(cursor, u)
+
+		return cursor < upper
+	})
+
+	return covered
+}
+
+// CoverageIn returns the union of the intervals overlapping with the given range, as ascending disjoint
+// segments clipped to it. Returns an empty Slice if lower is greater than upper.
+func (t *INTree) CoverageIn(lower, upper float64) [][2]float64 {
+	return t.coverageSegments(lower, upper)
+}
+
+// Gaps returns the sub-ranges of the given range not covered by any interval, sorted and without overlaps.
+// Gap limits other than the range limits are covered by the neighbouring intervals, so gaps exclude them;
+// a range limit is excluded as well when an interval covers it. Returns an empty Slice if lower is greater than upper.
+func (t *INTree) Gaps(lower, upper float64) []Interval {
+	result := []Interval{}
+
+	if lower > upper {
+		return result
+	}
+
+	segments := t.coverageSegments(lower, upper)
+	if len(segments) == 0 {
+		return append(result, Interval{Lower: lower, Upper: upper})
+	}
+
+	start := lower
+	for _, s := range segments {
+		if s[0] > start {
+			result = append(result, Interval{Lower: start, Upper: s[0]})
+		}
+
+		start = s[1]
+	}
+
+	if upper > start {
+		result = append(result, Interval{Lower: start, Upper: upper})
+	}
+
+	return result
+}
+
+// MaxDepth returns the greatest number of intervals overlapping on a single point of the given range,
+// along with the lowest such point (clipped to the range). Sweeps the limits of the overlapping nodes only; touching
+// intervals count as overlapping. Returns a depth of 0 at lower if no interval overlaps the range or lower is
+// greater than upper.
+func (t *INTree) MaxDepth(lower, upper float64) (depth int, at float64) {
+	at = lower
+
+	if lower > upper {
+		return 0, at
+	}
+
+	t = t.lowerOrdered()
+	lowers, uppers := []float64{}, []float64{}
+
+	t.overlapsInOrder(0, len(t.indexes)-1, lower, upper, func(pos int) bool {
+		lowers = append(lowers, math.Max(t.limits[3*pos], lower))
+		uppers = append(uppers, t.limits[3*pos+1])
+
+		return true
+	})
+
+	slices.Sort(uppers)
+
+	// Lower limits are already sorted; openings go first on ties, as intervals are closed
+	current, u := 0, 0
+	for _, l := range lowers {
+		for uppers[u] < l {
+			current--
+			u++
+		}
+
+		if current++; current > depth {
+			depth, at = current, l
+		}
+	}
+
+	return depth, at
+}
+
+// DepthProfile returns a histogram of the overlap depth over the given range, split into the given number of
+// equal width buckets: the greatest number of intervals overlapping on a single point of each bucket, in ascending
+// order. Buckets exclude their upper limit, except for the last one. Sweeps the limits of the overlapping nodes only, in
+// O(k log k + buckets) time for k overlapping intervals. Returns an empty Slice if buckets is zero or negative,
+// or lower is greater than upper.
+func (t *INTree) DepthProfile(lower, upper float64, buckets int) []int {
+	if buckets <= 0 || lower > upper {
+		return []int{}
+	}
+
+	result := make([]int, buckets)
+
+	if lower == upper {
+		depth, _ := t.MaxDepth(lower, upper)
+		for i := range result {
+			result[i] = depth
+		}
+
+		return result
+	}
+
+	t = t.lowerOrdered()
+	lowers, uppers := []float64{}, []float64{}
+
+	t.overlapsInOrder(0, len(t.indexes)-1, lower, upper, func(pos int) bool {
+		lowers = append(lowers, math.Max(t.limits[3*pos], lower))
+		uppers = append(uppers, math.Min(t.limits[3*pos+1], upper))
+
+		return true
+	})
+
+	slices.Sort(uppers)
+
+	width := (upper - lower) / float64(buckets)
+	bucket := func(x float64) int {
+		return min(int((x-lower)/width), buckets-1)
+	}
+
+	// Every event sets the depth up to the next one; openings go first on ties, as intervals are closed
+	current, l, u := 0, 0, 0
+	for l < len(lowers) || u < len(uppers) {
+		var x float64
+
+		if l < len(lowers) && lowers[l] <= uppers[u] {
+			x = lowers[l]
+			current++
+			l++
+		} else {
+			x = uppers[u]
+			current--
+			u++
+		}
+
+		next := upper
+		if l < len(lowers) {
+			next = min(lowers[l], uppers[u])
+		} else if u < len(uppers) {
+			next = uppers[u]
+		}
+
+		if current > 0 {
+			for b := bucket(x); b <= bucket(next); b++ {
+				result[b] = max(result[b], current)
+			}
+		}
+	}
+
+	return result
+}
+
+// BucketStats holds the interval density statistics of a bucket, as returned by Buckets.
+type BucketStats struct {
+	Lower, Upper float64
+	// Count is the number of intervals overlapping the bucket
+	Count int
+	// Coverage is the fraction of the bucket covered by at least one interval
+	Coverage float64
+	// MaxDepth is the greatest number of intervals overlapping on a single point of the bucket
+	MaxDepth int
+}
+
+// Buckets returns the interval density statistics of the given range split into the given number of equal width
+// buckets, in ascending order, ready to be plotted: the number of overlapping intervals, the covered fraction and
+// the overlap depth of each bucket, as DepthProfile does. Buckets are assigned as in DepthProfile. Sweeps the
+// overlapping nodes only, in O(k log k + buckets) time for k overlapping intervals. Returns an empty Slice if buckets
+// is zero or negative, or lower is greater than upper.
+func (t *INTree) Buckets(lower, upper float64, buckets int) []BucketStats {
+	if buckets <= 0 || lower > upper {
+		return []BucketStats{}
+	}
+
+	result := make([]BucketStats, buckets)
+	width := (upper - lower) / float64(buckets)
+
+	for i, depth := range t.DepthProfile(lower, upper, buckets) {
+		result[i] = BucketStats{Lower: lower + float64(i)*width, Upper: lower + float64(i+1)*width, MaxDepth: depth}
+	}
+
+	result[buckets-1].Upper = upper
+
+	if lower == upper {
+		covered := 0.0
+		if result[0].MaxDepth > 0 {
+			covered = 1
+		}
+
+		for i := range result {
+			result[i].Count, result[i].Coverage = result[i].MaxDepth, covered
+		}
+
+		return result
+	}
+
+	bucket := func(x float64) int {
+		return min(int((x-lower)/width), buckets-1)
+	}
+
+	// Intervals are counted on every bucket they span through a difference array
+	t = t.lowerOrdered()
+	counts := make([]int, buckets+1)
+
+	t.overlapsInOrder(0, len(t.indexes)-1, lower, upper, func(pos int) bool {
+		counts[bucket(math.Max(t.limits[3*pos], lower))]++
+		counts[bucket(math.Min(t.limits[3*pos+1], upper))+1]--
+
+		return true
+	})
+
+	for i, count := 0, 0; i < buckets; i++ {
+		count += counts[i]
+		result[i].Count = count
+	}
+
+	for _, s := range t.coverageSegments(lower, upper) {
+		for b := bucket(s[0]); b <= bucket(s[1]); b++ {
+			if covered := math.Min(s[1], result[b].Upper) - math.Max(s[0], result[b].Lower); covered > 0 {
+				result[b].Coverage += covered / width
+			}
+		}
+	}
+
+	return result
+}
+
+// CoverageSeq returns an iterator lazily yielding the union of all intervals as ascending disjoint segments,
+// merging overlapping and touching intervals as the tree is traversed in order.
+func (t *INTree) CoverageSeq() iter.Seq[[2]float64] {
+	return func(yield func([2]float64) bool) {
+		o := t.lowerOrdered()
+		started := false
+		current := [2]float64{}
+
+		completed := o.overlapsInOrder(0, len(o.indexes)-1, math.Inf(-1), math.Inf(1), func(pos int) bool {
+			l, u := o.limits[3*pos], o.limits[3*pos+1]
+
+			if started && l <= current[1] {
+				current[1] = math.Max(current[1], u)
+				return true
+			}
+
+			if started && !yield(current) {
+				return false
+			}
+
+			current, started = [2]float64{l, u}, true
+
+			return true
+		})
+
+		if completed && started {
+			yield(current)
+		}
+	}
+}
+
+// WouldExtendCoverage reports whether the given interval covers any coordinate not already covered by
+// the union of the stored intervals, meaning that inserting it would change the coverage.
+// Returns false if lower is greater than upper.
+func (t *INTree) WouldExtendCoverage(lower, upper float64) bool {
+	if lower > upper {
+		return false
+	}
+
+	segments := t.coverageSegments(lower, upper)
+
+	return len(segments) != 1 || segments[0][0] > lower || segments[0][1] < upper
+}
+
+// CoverageFraction returns the fraction of stored intervals that overlap with the given value;
+// returns 0 for an empty tree.
+func (t *INTree) CoverageFraction(val float64) float64 {
+	if t.Len() == 0 {
+		return 0
+	}
+
+	return float64(t.CountIncluding(val)) / float64(t.Len())
+}
+
+// RollingCoverage returns the CoverageRatio of the window [pos, pos+window] for every position
+// from start to end (both included) advancing by step.
+// Windows are anchored at their lower edge and are not truncated at end, so the last windows
+// also account for the coverage past end. Returns nil if step is not positive, window is negative
+// or end is lower than start.
+func (t *INTree) RollingCoverage(start, end, window, step float64) []float64 {
+	if step <= 0 || window < 0 || end < start {
+		return nil
+	}
+
+	steps := int(math.Floor((end-start)/step)) + 1
+	result := make([]float64, steps)
+
+	for i := range result {
+		pos := start + float64(i)*step
+		result[i] = t.CoverageRatio(pos, pos+window)
+	}
+
+	return result
+}
+
+// CoverageEntropy returns the Shannon entropy, in bits, of the coverage depth distribution weighted by
+// segment length, over the span from the lowest lower limit to the greatest upper limit (uncovered gaps
+// count as depth zero). Uniform coverage yields 0; returns 0 for an empty tree or a zero length span.
+func (t *INTree) CoverageEntropy() float64 {
+	histogram := map[int]float64{}
+	span := 0.0
+
+	for _, s := range t.depthSegments() {
+		histogram[s.depth] += s.upper - s.lower
+		span += s.upper - s.lower
+	}
+
+	entropy := 0.0
+
+	for _, length := range histogram {
+		p := length / span
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// CoverageByTier returns, for each of the given coverage count thresholds, the total length over which
+// at least that many intervals overlap, in the same order. Lengths are measured over the span from the lowest
+// lower limit to the greatest upper limit, so a threshold of zero or below yields the whole span length.
+func (t *INTree) CoverageByTier(tiers []float64) []float64 {
+	result := make([]float64, len(tiers))
+
+	for _, s := range t.depthSegments() {
+		for i, tier := range tiers {
+			if float64(s.depth) >= tier {
+				result[i] += s.upper - s.lower
+			}
+		}
+	}
+
+	return result
+}
+
+// exclusiveCoverage is an internal utility function, collecting the segments of the node at the given
+// position that are not covered by any other node not removed. Relies on nodes being sorted by lower limit.
+func (t *INTree) exclusiveCoverage(pos int) [][2]float64 {
+	lower, upper := t.limits[3*pos], t.limits[3*pos+1]
+	result := [][2]float64{}
+	cursor := lower
+
+	for i := range t.indexes {
+		if i == pos || t.isRemoved(i) {
+			continue
+		}
+
+		l, u := t.limits[3*i], t.limits[3*i+1]
+
+		if l > upper {
+			break
+		}
+
+		if u <= cursor {
+			continue
+		}
+
+		if l > cursor {
+			result = append(result, [2]float64{cursor, l})
+		}
+
+		cursor = u
+
+		if cursor >= upper {
+			return result
+		}
+	}
+
+	if cursor < upper {
+		result = append(result, [2]float64{cursor, upper})
+	}
+
+	return result
+}
+
+// coverageSegments is an internal utility function, merging the nodes overlapping the given range
+// into disjoint segments clipped to it, in ascending order.
+func (t *INTree) coverageSegments(lower, upper float64) [][2]float64 {
+	t = t.lowerOrdered()
+	result := [][2]float64{}
+
+	if lower > upper {
+		return result
+	}
+
+	t.overlapsInOrder(0, len(t.indexes)-1, lower, upper, func(pos int) bool {
+		l, u := math.Max(t.limits[3*pos], lower), math.Min(t.limits[3*pos+1], upper)
+
+		if n := len(result); n > 0 && l <= result[n-1][1] {
+			if u > result[n-1][1] {
+				result[n-1][1] = u
+			}
+
+			return true
+		}
+
+		result = append(result, [2]float64{l, u})
+
+		return true
+	})
+
+	return result
+}
+
+// overlapsInOrder is an internal utility function, calling fn with the position of every node in the
+// given bounds that overlaps the given range, in ascending lower limit order.
+// Stops the traversal and returns false as soon as fn returns false.
+func (t *INTree) overlapsInOrder(lBoundIdx, rBoundIdx int, lower, upper float64, fn func(pos int) bool) bool {
+	if lBoundIdx > rBoundIdx {
+		return true
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+	// Prune the subtree if no upper limit in it reaches the range
+	if lower > t.limits[3*centerIdx+2] {
+		return true
+	}
+
+	if !t.overlapsInOrder(lBoundIdx, centerIdx-1, lower, upper, fn) {
+		return false
+	}
+
+	// Center and right subtree nodes all start past the range
+	if t.limits[3*centerIdx] > upper {
+		return true
+	}
+
+	if lower <= t.limits[3*centerIdx+1] && !fn(centerIdx) {
+		return false
+	}
+
+	return t.overlapsInOrder(centerIdx+1, rBoundIdx, lower, upper, fn)
+}
+
+// depthSegment is a coordinate segment of the coverage step function, along with its coverage depth.
+type depthSegment struct {
+	lower, upper float64
+	depth        int
+}
+
+// depthSegments is an internal utility function, sweeping the node limits into the coverage step function:
+// ascending, contiguous segments of positive length from the lowest lower limit to the greatest upper limit.
+// Removed nodes are skipped.
+func (t *INTree) depthSegments() []depthSegment {
+	t = t.lowerOrdered()
+	result := []depthSegment{}
+	uppers := &limitHeap{}
+	depth := 0
+	prev := 0.0
+	started := false
+
+	for pos := 0; pos < len(t.indexes) || uppers.Len() > 0; {
+		if pos < len(t.indexes) && t.isRemoved(pos) {
+			pos++
+			continue
+		}
+
+		// Lower limits are already sorted; upper limits come from the heap
+		var x float64
+
+		opening := pos < len(t.indexes) && (uppers.Len() == 0 || t.limits[3*pos] <= (*uppers)[0])
+
+		if opening {
+			x = t.limits[3*pos]
+		} else {
+			x = (*uppers)[0]
+		}
+
+		// The first event is always the lowest lower limit
+		if started && x > prev {
+			result = append(result, depthSegment{lower: prev, upper: x, depth: depth})
+		}
+
+		if opening {
+			heap.Push(uppers, t.limits[3*pos+1])
+			depth++
+			pos++
+		} else {
+			heap.Pop(uppers)
+			depth--
+		}
+
+		prev, started = x, true
+	}
+
+	return result
+}
+
+// limitHeap is a min-heap of limits implementing heap.Interface.
+type limitHeap []float64
+
+func (h limitHeap) Len() int            { return len(h) }
+func (h limitHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h limitHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *limitHeap) Push(x interface{}) { *h = append(*h, x.(float64)) }
+
+func (h *limitHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return x
+}
@@ -0,0 +1,539 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_CoverageLostIfRemoved(t *testing.T) {
+	inputBounds := []intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 4.0},
+		&testBounds{Lower: 2.0, Upper: 6.0},
+		&testBounds{Lower: 1.0, Upper: 3.0},
+		&testBounds{Lower: 0.0, Upper: 10.0},
+		&testBounds{Lower: 12.0, Upper: 14.0},
+	}
+
+	t.Run("Case_Redundant", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		assert.Empty(t, tree.CoverageLostIfRemoved(2))
+		assert.Empty(t, tree.CoverageLostIfRemoved(1))
+	})
+	t.Run("Case_Essential", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		assert.EqualValues(t, [][2]float64{{6.0, 10.0}}, tree.CoverageLostIfRemoved(3))
+		assert.EqualValues(t, [][2]float64{{12.0, 14.0}}, tree.CoverageLostIfRemoved(4))
+	})
+	t.Run("Case_Essential/multiple_segments", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 10.0},
+			&testBounds{Lower: 2.0, Upper: 3.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+		})
+
+		assert.EqualValues(t, [][2]float64{{0.0, 2.0}, {3.0, 5.0}, {6.0, 10.0}}, tree.CoverageLostIfRemoved(0))
+	})
+	t.Run("Case_Border/out_of_range", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		assert.Nil(t, tree.CoverageLostIfRemoved(-1))
+		assert.Nil(t, tree.CoverageLostIfRemoved(len(inputBounds)))
+		assert.Nil(t, intree.NewINTree(nil).CoverageLostIfRemoved(0))
+	})
+}
+
+func Test_Tree_CoverageRatio(t *testing.T) {
+	tree := intree.NewINTree([]intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 1.0, Upper: 3.0},
+		&testBounds{Lower: 5.0, Upper: 6.0},
+	})
+
+	t.Run("Case_Example", func(t *testing.T) {
+		assert.InDelta(t, 1.0, tree.CoverageRatio(0.0, 3.0), 1e-9)
+		assert.InDelta(t, 0.5, tree.CoverageRatio(2.0, 6.0), 1e-9)
+		assert.InDelta(t, 0.4, tree.CoverageRatio(0.0, 10.0), 1e-9)
+		assert.InDelta(t, 0.0, tree.CoverageRatio(3.5, 4.5), 1e-9)
+	})
+	t.Run("Case_Border/degenerate_range", func(t *testing.T) {
+		assert.EqualValues(t, 1.0, tree.CoverageRatio(5.0, 5.0))
+		assert.EqualValues(t, 0.0, tree.CoverageRatio(4.0, 4.0))
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		assert.EqualValues(t, 0.0, tree.CoverageRatio(6.0, 5.0))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, 0.0, intree.NewINTree(nil).CoverageRatio(0.0, 1.0))
+	})
+}
+
+func Test_Tree_RollingCoverage(t *testing.T) {
+	tree := intree.NewINTree([]intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 4.0, Upper: 8.0},
+	})
+
+	t.Run("Case_Example", func(t *testing.T) {
+		// Windows [0,3], [1,4], [2,5], [3,6]
+		expected := []float64{2.0 / 3.0, 1.0 / 3.0, 1.0 / 3.0, 2.0 / 3.0}
+		result := tree.RollingCoverage(0.0, 3.0, 3.0, 1.0)
+
+		assert.EqualValues(t, len(expected), len(result))
+		for i := range expected {
+			assert.InDelta(t, expected[i], result[i], 1e-9)
+		}
+	})
+	t.Run("Case_Border/domain_edges", func(t *testing.T) {
+		// The last window [7,9] extends past end and past the last interval
+		// Windows [0,2], [3.5,5.5], [7,9]
+		expected := []float64{1.0, 0.75, 0.5}
+		result := tree.RollingCoverage(0.0, 7.0, 2.0, 3.5)
+
+		assert.EqualValues(t, len(expected), len(result))
+		for i := range expected {
+			assert.InDelta(t, expected[i], result[i], 1e-9)
+		}
+	})
+	t.Run("Case_Border/invalid_arguments", func(t *testing.T) {
+		assert.Nil(t, tree.RollingCoverage(0.0, 3.0, 1.0, 0.0))
+		assert.Nil(t, tree.RollingCoverage(0.0, 3.0, -1.0, 1.0))
+		assert.Nil(t, tree.RollingCoverage(3.0, 0.0, 1.0, 1.0))
+	})
+}
+
+func Test_Tree_CoverageFraction(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.InDelta(t, 5.0/13.0, tree.CoverageFraction(4.3), 1e-9)
+		assert.EqualValues(t, 0.0, tree.CoverageFraction(9.5))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, 0.0, intree.NewINTree(nil).CoverageFraction(4.3))
+	})
+}
+
+func Test_Tree_CoverageEntropy(t *testing.T) {
+	t.Run("Case_Uniform", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 10.0},
+			&testBounds{Lower: 0.0, Upper: 10.0},
+		})
+		assert.InDelta(t, 0.0, tree.CoverageEntropy(), 1e-9)
+
+		tree = intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 5.0},
+			&testBounds{Lower: 5.0, Upper: 10.0},
+		})
+		assert.InDelta(t, 0.0, tree.CoverageEntropy(), 1e-9)
+	})
+	t.Run("Case_Varied", func(t *testing.T) {
+		// Depths 1, 2, 3, 2, 1 over [0,2], [2,3], [3,4], [4,5], [5,6]
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 4.0},
+			&testBounds{Lower: 2.0, Upper: 6.0},
+			&testBounds{Lower: 3.0, Upper: 5.0},
+		})
+
+		expected := -(0.5*math.Log2(0.5) + (1.0/3.0)*math.Log2(1.0/3.0) + (1.0/6.0)*math.Log2(1.0/6.0))
+		assert.InDelta(t, expected, tree.CoverageEntropy(), 1e-9)
+		assert.Greater(t, tree.CoverageEntropy(), 1.0)
+	})
+	t.Run("Case_Varied/gaps", func(t *testing.T) {
+		// Uncovered [1,2] counts as depth zero
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 2.0, Upper: 3.0},
+		})
+
+		expected := -((2.0/3.0)*math.Log2(2.0/3.0) + (1.0/3.0)*math.Log2(1.0/3.0))
+		assert.InDelta(t, expected, tree.CoverageEntropy(), 1e-9)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, 0.0, intree.NewINTree(nil).CoverageEntropy())
+	})
+	t.Run("Case_Border/point_intervals", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 1.0, Upper: 1.0},
+			&testBounds{Lower: 1.0, Upper: 1.0},
+		})
+		assert.EqualValues(t, 0.0, tree.CoverageEntropy())
+
+		// Uncovered [1,3] after the point interval counts as depth zero
+		tree = intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 1.0, Upper: 1.0},
+			&testBounds{Lower: 3.0, Upper: 4.0},
+		})
+		expected := -((2.0/3.0)*math.Log2(2.0/3.0) + (1.0/3.0)*math.Log2(1.0/3.0))
+		assert.InDelta(t, expected, tree.CoverageEntropy(), 1e-9)
+	})
+}
+
+func Test_Tree_CoverageIn(t *testing.T) {
+	tree := intree.NewINTree(exampleBounds())
+
+	assert.EqualValues(t, [][2]float64{{1.0, 9.0}}, tree.CoverageIn(math.Inf(-1), math.Inf(1)))
+	assert.EqualValues(t, [][2]float64{{2.5, 3.5}}, tree.CoverageIn(2.5, 3.5))
+	assert.EqualValues(t, 0, len(tree.CoverageIn(9.5, 10.0)))
+	assert.EqualValues(t, 0, len(tree.CoverageIn(3.5, 2.5)))
+
+	tree = intree.NewINTree([]intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 5.0, Upper: 6.0},
+		&testBounds{Lower: 1.0, Upper: 3.0},
+	})
+	assert.EqualValues(t, [][2]float64{{1.5, 3.0}, {5.0, 5.5}}, tree.CoverageIn(1.5, 5.5))
+}
+
+func Test_Tree_CoveredLength(t *testing.T) {
+	t.Run("Case_Example_bounds", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 1.0, Upper: 3.0},
+		})
+
+		assert.EqualValues(t, 4.0, tree.CoveredLength(math.Inf(-1), math.Inf(1)))
+		assert.EqualValues(t, 2.0, tree.CoveredLength(1.5, 5.5))
+		assert.EqualValues(t, 0.0, tree.CoveredLength(3.0, 5.0))
+		assert.EqualValues(t, 0.0, tree.CoveredLength(1.0, 1.0))
+		assert.EqualValues(t, 0.0, tree.CoveredLength(2.0, 1.0))
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 1000.0, 2.0))
+
+		for i := 0; i < 100; i++ {
+			lower := float64(i) * 10.0
+			upper := lower + 15.0
+
+			covered := 0.0
+			for _, s := range tree.CoverageIn(lower, upper) {
+				covered += s[1] - s[0]
+			}
+
+			assert.InDelta(t, covered, tree.CoveredLength(lower, upper), 1e-9)
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, 0.0, intree.NewINTree(nil).CoveredLength(0.0, 10.0))
+	})
+}
+
+func Test_Tree_Gaps(t *testing.T) {
+	t.Run("Case_Example_bounds", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 1.0, Upper: 3.0},
+		})
+
+		assert.EqualValues(t, []intree.Interval{{Lower: -1.0, Upper: 0.0}, {Lower: 3.0, Upper: 5.0}, {Lower: 6.0, Upper: 7.0}},
+			tree.Gaps(-1.0, 7.0))
+		assert.EqualValues(t, []intree.Interval{{Lower: 3.0, Upper: 5.0}}, tree.Gaps(1.5, 5.5))
+		assert.EqualValues(t, []intree.Interval{}, tree.Gaps(0.5, 2.5))
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 1000.0, 2.0))
+
+		for i := 0; i < 100; i++ {
+			lower := float64(i) * 10.0
+			upper := lower + 15.0
+
+			covered := 0.0
+			for _, s := range tree.CoverageIn(lower, upper) {
+				covered += s[1] - s[0]
+			}
+
+			uncovered := 0.0
+			for _, g := range tree.Gaps(lower, upper) {
+				assert.True(t, g.Lower >= lower && g.Upper <= upper)
+				uncovered += g.Upper - g.Lower
+			}
+
+			assert.InDelta(t, upper-lower, covered+uncovered, 1e-9)
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+		assert.EqualValues(t, []intree.Interval{{Lower: 1.0, Upper: 2.0}}, tree.Gaps(1.0, 2.0))
+		assert.EqualValues(t, []intree.Interval{{Lower: 1.0, Upper: 1.0}}, tree.Gaps(1.0, 1.0))
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.EqualValues(t, 0, len(tree.Gaps(3.5, 2.5)))
+	})
+}
+
+func Test_Tree_MaxDepth(t *testing.T) {
+	t.Run("Case_Example_bounds", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 1.0, Upper: 3.0},
+			&testBounds{Lower: 1.5, Upper: 1.8},
+			&testBounds{Lower: 6.0, Upper: 7.0},
+		})
+
+		depth, at := tree.MaxDepth(math.Inf(-1), math.Inf(1))
+		assert.EqualValues(t, 3, depth)
+		assert.EqualValues(t, 1.5, at)
+
+		depth, at = tree.MaxDepth(4.0, 10.0)
+		assert.EqualValues(t, 2, depth)
+		assert.EqualValues(t, 6.0, at)
+
+		depth, at = tree.MaxDepth(1.9, 10.0)
+		assert.EqualValues(t, 2, depth)
+		assert.EqualValues(t, 1.9, at)
+
+		depth, at = tree.MaxDepth(3.5, 4.5)
+		assert.EqualValues(t, 0, depth)
+		assert.EqualValues(t, 3.5, at)
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		bounds := randomBounds(1000, 1000.0, 2.0)
+		tree := intree.NewINTree(bounds)
+
+		for i := 0; i < 100; i++ {
+			lower := float64(i) * 10.0
+			upper := lower + 15.0
+
+			depth, at := tree.MaxDepth(lower, upper)
+			assert.True(t, at >= lower && at <= upper)
+			assert.EqualValues(t, depth, tree.CountIncluding(at))
+
+			// No interval lower limit in the range may exceed the reported depth
+			for _, b := range bounds {
+				if l, _ := b.Limits(); l >= lower && l <= upper {
+					assert.True(t, tree.CountIncluding(l) <= depth)
+				}
+			}
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		depth, at := intree.NewINTree(nil).MaxDepth(0.0, 10.0)
+		assert.EqualValues(t, 0, depth)
+		assert.EqualValues(t, 0.0, at)
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		depth, at := intree.NewINTree(exampleBounds()).MaxDepth(3.0, 2.0)
+		assert.EqualValues(t, 0, depth)
+		assert.EqualValues(t, 3.0, at)
+	})
+}
+
+func Test_Tree_DepthProfile(t *testing.T) {
+	t.Run("Case_Example_bounds", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 1.0, Upper: 3.0},
+			&testBounds{Lower: 1.5, Upper: 1.8},
+			&testBounds{Lower: 6.5, Upper: 7.0},
+		})
+
+		// Depth 2 is reached at 1.0 and 2.0, which belong to the buckets starting there
+		assert.EqualValues(t, []int{1, 3, 2, 1, 0, 1, 1, 1}, tree.DepthProfile(0.0, 8.0, 8))
+		assert.EqualValues(t, []int{3, 1}, tree.DepthProfile(0.0, 8.0, 2))
+		assert.EqualValues(t, []int{0, 0}, tree.DepthProfile(3.5, 4.5, 2))
+		assert.EqualValues(t, []int{3, 3, 3}, tree.DepthProfile(1.6, 1.6, 3))
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 1000.0, 20.0))
+
+		for _, buckets := range []int{1, 7, 64, 1000} {
+			profile := tree.DepthProfile(100.0, 612.0, buckets)
+			assert.EqualValues(t, buckets, len(profile))
+
+			width := 512.0 / float64(buckets)
+			for i, depth := range profile {
+				expected, _ := tree.MaxDepth(100.0+float64(i)*width, 100.0+float64(i+1)*width)
+				assert.EqualValues(t, expected, depth, "%d buckets, bucket %d", buckets, i)
+			}
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, []int{0, 0, 0}, intree.NewINTree(nil).DepthProfile(0.0, 1.0, 3))
+	})
+	t.Run("Case_Border/invalid_arguments", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.Empty(t, tree.DepthProfile(0.0, 1.0, 0))
+		assert.Empty(t, tree.DepthProfile(2.0, 1.0, 3))
+	})
+}
+
+func Test_Tree_Buckets(t *testing.T) {
+	t.Run("Case_Example_bounds", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 1.0, Upper: 3.0},
+			&testBounds{Lower: 1.5, Upper: 1.8},
+			&testBounds{Lower: 6.5, Upper: 7.0},
+		})
+
+		buckets := tree.Buckets(0.0, 8.0, 4)
+		assert.EqualValues(t, 4, len(buckets))
+
+		for i, b := range buckets {
+			assert.EqualValues(t, 2.0*float64(i), b.Lower)
+			assert.EqualValues(t, 2.0*float64(i+1), b.Upper)
+		}
+
+		assert.EqualValues(t, []int{3, 2, 1, 2}, []int{buckets[0].Count, buckets[1].Count, buckets[2].Count, buckets[3].Count})
+		assert.EqualValues(t, tree.DepthProfile(0.0, 8.0, 4),
+			[]int{buckets[0].MaxDepth, buckets[1].MaxDepth, buckets[2].MaxDepth, buckets[3].MaxDepth})
+		assert.InDelta(t, 1.0, buckets[0].Coverage, 1e-9)
+		assert.InDelta(t, 0.5, buckets[1].Coverage, 1e-9)
+		assert.InDelta(t, 0.5, buckets[2].Coverage, 1e-9)
+		assert.InDelta(t, 0.25, buckets[3].Coverage, 1e-9)
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 1000.0, 20.0))
+
+		for _, n := range []int{1, 7, 64} {
+			buckets := tree.Buckets(100.0, 612.0, n)
+			assert.EqualValues(t, n, len(buckets))
+
+			for i, b := range buckets {
+				assert.InDelta(t, tree.CoverageRatio(b.Lower, b.Upper), b.Coverage, 1e-9, "%d buckets, bucket %d", n, i)
+				assert.LessOrEqual(t, b.MaxDepth, b.Count)
+			}
+
+			if n == 1 {
+				assert.EqualValues(t, len(tree.Overlapping(100.0, 612.0)), buckets[0].Count)
+			}
+		}
+	})
+	t.Run("Case_Border/degenerate_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		buckets := tree.Buckets(4.3, 4.3, 2)
+		assert.EqualValues(t, 5, buckets[1].Count)
+		assert.EqualValues(t, 1.0, buckets[1].Coverage)
+	})
+	t.Run("Case_Border/invalid_arguments", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.Empty(t, tree.Buckets(0.0, 8.0, 0))
+		assert.Empty(t, tree.Buckets(8.0, 0.0, 4))
+	})
+}
+
+func Test_Tree_CoverageSeq(t *testing.T) {
+	t.Run("Case_Full_sequence", func(t *testing.T) {
+		for _, bounds := range [][]intree.Bounds{exampleBounds(), randomBounds(1000, 1000.0, 2.0)} {
+			tree := intree.NewINTree(bounds)
+
+			segments := [][2]float64{}
+			for s := range tree.CoverageSeq() {
+				segments = append(segments, s)
+			}
+
+			assert.EqualValues(t, tree.CoverageIn(math.Inf(-1), math.Inf(1)), segments)
+		}
+	})
+	t.Run("Case_Early_break", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 1000.0, 2.0))
+		expected := tree.CoverageIn(math.Inf(-1), math.Inf(1))
+
+		segments := [][2]float64{}
+		for s := range tree.CoverageSeq() {
+			segments = append(segments, s)
+			if len(segments) == 3 {
+				break
+			}
+		}
+
+		assert.EqualValues(t, expected[:3], segments)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		count := 0
+		for range intree.NewINTree(nil).CoverageSeq() {
+			count++
+		}
+
+		assert.EqualValues(t, 0, count)
+	})
+}
+
+func Test_Tree_WouldExtendCoverage(t *testing.T) {
+	tree := intree.NewINTree([]intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 1.0, Upper: 4.0},
+		&testBounds{Lower: 4.0, Upper: 5.0},
+		&testBounds{Lower: 7.0, Upper: 8.0},
+	})
+
+	t.Run("Case_Redundant", func(t *testing.T) {
+		assert.False(t, tree.WouldExtendCoverage(0.5, 4.5))
+		assert.False(t, tree.WouldExtendCoverage(0.0, 5.0))
+		assert.False(t, tree.WouldExtendCoverage(7.0, 8.0))
+		assert.False(t, tree.WouldExtendCoverage(7.5, 7.5))
+	})
+	t.Run("Case_Extending", func(t *testing.T) {
+		assert.True(t, tree.WouldExtendCoverage(4.5, 7.5))
+		assert.True(t, tree.WouldExtendCoverage(-1.0, 1.0))
+		assert.True(t, tree.WouldExtendCoverage(7.5, 8.5))
+		assert.True(t, tree.WouldExtendCoverage(6.0, 6.0))
+		assert.True(t, tree.WouldExtendCoverage(0.0, 8.0))
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		assert.False(t, tree.WouldExtendCoverage(8.5, 6.0))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.True(t, intree.NewINTree(nil).WouldExtendCoverage(1.0, 2.0))
+	})
+}
+
+func Test_Tree_CoverageByTier(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		// Depths 1, 2, 3, 2, 1 over [0,2], [2,3], [3,4], [4,5], [5,6], then 0 over [6,8] and 1 over [8,9]
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 4.0},
+			&testBounds{Lower: 2.0, Upper: 6.0},
+			&testBounds{Lower: 3.0, Upper: 5.0},
+			&testBounds{Lower: 8.0, Upper: 9.0},
+		})
+
+		result := tree.CoverageByTier([]float64{0, 1, 2, 3, 4, 1.5})
+		expected := []float64{9.0, 7.0, 3.0, 1.0, 0.0, 3.0}
+
+		assert.EqualValues(t, len(expected), len(result))
+		for i := range expected {
+			assert.InDelta(t, expected[i], result[i], 1e-9, "tier %d", i)
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, []float64{0, 0}, intree.NewINTree(nil).CoverageByTier([]float64{0, 1}))
+	})
+	t.Run("Case_Border/no_tiers", func(t *testing.T) {
+		assert.EqualValues(t, 0, len(intree.NewINTree(exampleBounds()).CoverageByTier(nil)))
+	})
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// DOT writes the implicit binary tree of the nodes in the Graphviz DOT language, labelling each node with its
+// position, original index, interval limits and augmented limit, and each edge with the child side, so that small
+// trees can be visualized (as in `dot -Tsvg`) when learning the package or debugging it. Removed intervals are
+// drawn dashed. Trees whose nodes are not sorted by lower limit are drawn through a sorted copy.
+// Returns the first error of the given writer.
+func (t *INTree) DOT(w io.Writer) error {
+	t = t.lowerOrdered()
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("digraph intree {\n\tnode [shape=box, fontname=monospace];\n")
+	t.dotNodes(bw, 0, len(t.indexes)-1)
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// dotNodes is an internal utility function, writing the nodes and edges of the subtree in the given bounds.
+// Returns the position of the subtree root, or -1 if it is empty.
+func (t *INTree) dotNodes(bw *bufio.Writer, lBoundIdx, rBoundIdx int) int {
+	if lBoundIdx > rBoundIdx {
+		return -1
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+	id := "n" + strconv.Itoa(centerIdx)
+
+	bw.WriteString("\t" + id + " [label=\"#" + strconv.Itoa(centerIdx) + " index " + strconv.Itoa(t.indexes[centerIdx]) +
+		"\\n[" + formatLimit(t.limits[3*centerIdx]) + ", " + formatLimit(t.limits[3*centerIdx+1]) +
+		"]\\nmax " + formatLimit(t.limits[3*centerIdx+2]) + "\"")
+
+	if t.isRemoved(centerIdx) {
+		bw.WriteString(", style=dashed")
+	}
+
+	bw.WriteString("];\n")
+
+	for _, child := range [2]struct {
+		side string
+		pos  int
+	}{
+		{"L", t.dotNodes(bw, lBoundIdx, centerIdx-1)},
+		{"R", t.dotNodes(bw, centerIdx+1, rBoundIdx)},
+	} {
+		if child.pos >= 0 {
+			bw.WriteString("\t" + id + " -> n" + strconv.Itoa(child.pos) + " [label=\"" + child.side + "\"];\n")
+		}
+	}
+
+	return centerIdx
+}
+
+// formatLimit is an internal utility function, formatting a limit in its shortest exact representation.
+func formatLimit(l float64) string {
+	return strconv.FormatFloat(l, 'g', -1, 64)
+}
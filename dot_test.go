@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter is an io.Writer failing every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func Test_Tree_DOT(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.5},
+			&testBounds{Lower: 2.0, Upper: 3.0},
+			&testBounds{Lower: 4.0, Upper: 6.0},
+		}, intree.WithDeterministicSort())
+		assert.NoError(t, tree.Remove(0))
+
+		var sb strings.Builder
+		assert.NoError(t, tree.DOT(&sb))
+		assert.EqualValues(t, `digraph intree {
+	node [shape=box, fontname=monospace];
+	n1 [label="#1 index 1\n[2, 3]\nmax 6"];
+	n0 [label="#0 index 0\n[0, -Inf]\nmax -Inf", style=dashed];
+	n2 [label="#2 index 2\n[4, 6]\nmax 6"];
+	n1 -> n0 [label="L"];
+	n1 -> n2 [label="R"];
+}
+`, sb.String())
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(100, 100.0, 10.0))
+
+		var sb strings.Builder
+		assert.NoError(t, tree.DOT(&sb))
+		assert.EqualValues(t, tree.Len(), strings.Count(sb.String(), "[label=\"#"))
+		assert.EqualValues(t, tree.Len()-1, strings.Count(sb.String(), " -> "))
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		var sb strings.Builder
+		assert.NoError(t, intree.NewINTree(nil).DOT(&sb))
+		assert.EqualValues(t, "digraph intree {\n\tnode [shape=box, fontname=monospace];\n}\n", sb.String())
+	})
+	t.Run("Case_Border/write_error", func(t *testing.T) {
+		assert.Error(t, intree.NewINTree(exampleBounds()).DOT(failingWriter{}))
+	})
+}
@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// stringMaxDepth is the number of tree levels printed by String.
+const stringMaxDepth = 4
+
+var _ fmt.Stringer = (*INTree)(nil)
+
+// String returns a readable in-order view of the top levels of the tree, as printed by Dump.
+func (t *INTree) String() string {
+	var sb strings.Builder
+	_ = t.Dump(&sb, stringMaxDepth)
+
+	return sb.String()
+}
+
+// Dump writes a readable in-order view of the tree, after a header line with its length and endpoints: one line
+// per node with its interval limits, original index and augmented limit, indented by its depth so that the tree
+// reads sideways with the root at the left. Subtrees below the given number of levels are summarized by their
+// node count; a zero or negative maxDepth prints every level. Trees whose nodes are not sorted by lower limit are
+// printed through a sorted copy. Returns the first error of the given writer.
+func (t *INTree) Dump(w io.Writer, maxDepth int) error {
+	t = t.lowerOrdered()
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "INTree of %d intervals %v\n", len(t.indexes), t.endpoints)
+	t.dumpNodes(bw, 0, len(t.indexes)-1, 0, maxDepth)
+
+	return bw.Flush()
+}
+
+// dumpNodes is an internal utility function, writing the nodes of the subtree in the given bounds in order.
+func (t *INTree) dumpNodes(bw *bufio.Writer, lBoundIdx, rBoundIdx, depth, maxDepth int) {
+	if lBoundIdx > rBoundIdx {
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+
+	if maxDepth > 0 && depth >= maxDepth {
+		if n := rBoundIdx - lBoundIdx + 1; n == 1 {
+			bw.WriteString(indent + "... 1 node\n")
+		} else {
+			bw.WriteString(indent + "... " + strconv.Itoa(n) + " nodes\n")
+		}
+
+		return
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+	t.dumpNodes(bw, lBoundIdx, centerIdx-1, depth+1, maxDepth)
+
+	bw.WriteString(indent + "[" + formatLimit(t.limits[3*centerIdx]) + ", " + formatLimit(t.limits[3*centerIdx+1]) +
+		"] index " + strconv.Itoa(t.indexes[centerIdx]) + " max " + formatLimit(t.limits[3*centerIdx+2]))
+
+	if t.isRemoved(centerIdx) {
+		bw.WriteString(" removed")
+	}
+
+	bw.WriteString("\n")
+
+	t.dumpNodes(bw, centerIdx+1, rBoundIdx, depth+1, maxDepth)
+}
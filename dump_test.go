@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Dump(t *testing.T) {
+	bounds := []intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 1.5},
+		&testBounds{Lower: 2.0, Upper: 3.0},
+		&testBounds{Lower: 4.0, Upper: 6.0},
+		&testBounds{Lower: 5.0, Upper: 5.5},
+	}
+
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(bounds, intree.WithDeterministicSort())
+		assert.NoError(t, tree.Remove(1))
+
+		var sb strings.Builder
+		assert.NoError(t, tree.Dump(&sb, 0))
+		assert.EqualValues(t, `INTree of 4 intervals [lower, upper]
+    [0, 1.5] index 0 max 1.5
+  [2, -Inf] index 1 max 1.5 removed
+[4, 6] index 2 max 6
+  [5, 5.5] index 3 max 5.5
+`, sb.String())
+
+		sb.Reset()
+		assert.NoError(t, tree.Dump(&sb, 1))
+		assert.EqualValues(t, `INTree of 4 intervals [lower, upper]
+  ... 2 nodes
+[4, 6] index 2 max 6
+  ... 1 node
+`, sb.String())
+	})
+	t.Run("Case_String", func(t *testing.T) {
+		tree := intree.NewINTree(bounds, intree.WithEndpoints(intree.UpperOpen))
+
+		var sb strings.Builder
+		assert.NoError(t, tree.Dump(&sb, 4))
+		assert.EqualValues(t, sb.String(), tree.String())
+		assert.EqualValues(t, sb.String(), fmt.Sprint(tree))
+		assert.True(t, strings.HasPrefix(tree.String(), "INTree of 4 intervals [lower, upper)\n"))
+
+		large := intree.NewINTree(randomBounds(1000, 100.0, 10.0))
+		assert.Contains(t, large.String(), "... ")
+		assert.Less(t, strings.Count(large.String(), "\n"), 40)
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		assert.EqualValues(t, "INTree of 0 intervals [lower, upper]\n", intree.NewINTree(nil).String())
+	})
+	t.Run("Case_Border/write_error", func(t *testing.T) {
+		assert.Error(t, intree.NewINTree(exampleBounds()).Dump(failingWriter{}, 0))
+	})
+}
@@ -0,0 +1,345 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// encodingMagic opens the binary encoding, telling it apart from unrelated files.
+const encodingMagic = "INTR"
+
+// encodingVersion is the version byte written after the magic of the binary encoding.
+const encodingVersion byte = 3
+
+// headerSize is the size of the binary encoding header: magic, version, byte order, endpoints and flags bytes,
+// keeping the nodes 8 bytes aligned, plus indexes and limits lengths.
+const headerSize = 8 + 8 + 8
+
+// Byte order flags of the binary encoding.
+const (
+	littleEndianFlag byte = 0
+	bigEndianFlag    byte = 1
+)
+
+// nativeOrderFlag is the byte order flag of the platform.
+var nativeOrderFlag = func() byte {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) == 1 {
+		return littleEndianFlag
+	}
+
+	return bigEndianFlag
+}()
+
+// unorderedFlag is set on the flags byte of the binary encoding when the nodes are not sorted by lower limit,
+// as in custom node orders.
+const unorderedFlag byte = 1 << 0
+
+// removedFlag is set on the flags byte of the binary encoding when it holds intervals removed by Remove, whose
+// flags then follow the nodes as a bitmap of 64 bit words by original index.
+const removedFlag byte = 1 << 1
+
+// ErrInvalidEncoding is returned by UnmarshalBinary when the given data is not a valid tree encoding.
+var ErrInvalidEncoding = errors.New("intree: invalid binary encoding")
+
+var (
+	_ encoding.BinaryMarshaler   = (*INTree)(nil)
+	_ encoding.BinaryUnmarshaler = (*INTree)(nil)
+)
+
+// header holds the decoded binary encoding header.
+type header struct {
+	order     binary.ByteOrder
+	orderFlag byte
+	endpoints Endpoints
+	// ordered reports whether the encoding states the nodes are sorted by lower limit, nil for raw nodes
+	ordered *bool
+	nodes   int
+	// tombstones flags the removed intervals by original index, nil if the encoding holds none
+	tombstones []bool
+}
+
+// MarshalBinary encodes the tree nodes into a little endian binary form, as MarshalBinaryOrder does.
+// Values associated to ValuedBounds, retained bounds and staged intervals are not encoded.
+func (t *INTree) MarshalBinary() ([]byte, error) {
+	return t.MarshalBinaryOrder(binary.LittleEndian)
+}
+
+// MarshalBinaryOrder encodes the tree nodes into a binary form of the given byte order, which must be
+// binary.LittleEndian, binary.BigEndian or binary.NativeEndian: a header holding the magic, the version,
+// byte order, endpoints and flags bytes and the indexes and limits lengths, followed by both Slices and, if any
+// interval was removed by Remove and not compacted yet, the bitmap of removed intervals.
+// Encodings of either byte order are decoded on any platform; OpenMMap only uses them in place on platforms
+// of the same byte order. Values associated to ValuedBounds, retained bounds and staged intervals are not encoded.
+func (t *INTree) MarshalBinaryOrder(order binary.ByteOrder) ([]byte, error) {
+	t = t.merged()
+
+	var orderFlag byte
+
+	switch order {
+	case binary.LittleEndian:
+		orderFlag = littleEndianFlag
+	case binary.BigEndian:
+		orderFlag = bigEndianFlag
+	case binary.NativeEndian:
+		orderFlag = nativeOrderFlag
+	default:
+		return nil, fmt.Errorf("%w: unsupported byte order %v", ErrInvalidEncoding, order)
+	}
+
+	words := 0
+	if t.removed > 0 {
+		words = (len(t.indexes) + 63) / 64
+	}
+
+	data := make([]byte, headerSize+8*len(t.indexes)+8*len(t.limits)+8*words)
+
+	copy(data, encodingMagic)
+	data[4] = encodingVersion
+	data[5] = orderFlag
+	data[6] = byte(t.endpoints)
+
+	if t.unordered {
+		data[7] |= unorderedFlag
+	}
+
+	if words > 0 {
+		data[7] |= removedFlag
+	}
+
+	order.PutUint64(data[8:], uint64(len(t.indexes)))
+	order.PutUint64(data[16:], uint64(len(t.limits)))
+
+	offset := headerSize
+	for _, idx := range t.indexes {
+		order.PutUint64(data[offset:], uint64(idx))
+		offset += 8
+	}
+
+	for _, l := range t.limits {
+		order.PutUint64(data[offset:], math.Float64bits(l))
+		offset += 8
+	}
+
+	for w := 0; w < words; w++ {
+		var word uint64
+
+		for b, dead := range t.tombstones[64*w : min(64*w+64, len(t.tombstones))] {
+			if dead {
+				word |= 1 << b
+			}
+		}
+
+		order.PutUint64(data[offset:], word)
+		offset += 8
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a tree previously encoded by MarshalBinary or MarshalBinaryOrder, replacing the tree
+// contents and discarding any staged interval.
+// Returns an ErrInvalidEncoding wrapped error on an unknown version, truncated data or inconsistent lengths,
+// also wrapping ErrCorruptedTree if the decoded nodes fail Validate.
+func (t *INTree) UnmarshalBinary(data []byte) error {
+	h, err := decodeHeader(data)
+	if err != nil {
+		return err
+	}
+
+	indexes := make([]int, h.nodes)
+	limits := make([]float64, 3*h.nodes)
+	offset := headerSize
+
+	for i := range indexes {
+		indexes[i] = int(h.order.Uint64(data[offset:]))
+		offset += 8
+	}
+
+	for i := range limits {
+		limits[i] = math.Float64frombits(h.order.Uint64(data[offset:]))
+		offset += 8
+	}
+
+	return t.setDecodedNodes(h, indexes, limits)
+}
+
+// setDecodedNodes is an internal utility function, replacing the tree contents with the given decoded nodes
+// and settings once validated. The tree is left untouched on error.
+func (t *INTree) setDecodedNodes(h header, indexes []int, limits []float64) error {
+	decoded := INTree{}
+	if err := decoded.setNodes(indexes, limits); err != nil {
+		return err
+	}
+
+	decoded.endpoints = h.endpoints
+
+	if h.ordered != nil {
+		decoded.unordered = !*h.ordered
+	}
+
+	for idx, dead := range h.tombstones {
+		if !dead {
+			continue
+		}
+
+		if !math.IsInf(limits[3*decoded.positions[idx]+1], -1) {
+			return fmt.Errorf("%w: removed index %d is still searchable", ErrInvalidEncoding, idx)
+		}
+
+		decoded.removed++
+	}
+
+	if decoded.removed > 0 {
+		decoded.tombstones = h.tombstones
+	}
+
+	decoded.sortView()
+
+	if err := decoded.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+
+	*t = decoded
+
+	return nil
+}
+
+// decodeHeader is an internal utility function, validating the encoding header and lengths, and decoding
+// the bitmap of removed intervals, if any.
+func decodeHeader(data []byte) (header, error) {
+	h := header{order: binary.LittleEndian, orderFlag: littleEndianFlag}
+
+	if len(data) < headerSize {
+		return h, fmt.Errorf("%w: truncated header (%d bytes)", ErrInvalidEncoding, len(data))
+	}
+
+	if string(data[:len(encodingMagic)]) != encodingMagic {
+		return h, fmt.Errorf("%w: missing magic", ErrInvalidEncoding)
+	}
+
+	if data[4] != encodingVersion {
+		return h, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, data[4])
+	}
+
+	switch data[5] {
+	case littleEndianFlag:
+	case bigEndianFlag:
+		h.order, h.orderFlag = binary.BigEndian, bigEndianFlag
+	default:
+		return h, fmt.Errorf("%w: invalid byte order %d", ErrInvalidEncoding, data[5])
+	}
+
+	if h.endpoints = Endpoints(data[6]); h.endpoints&^Open != 0 {
+		return h, fmt.Errorf("%w: invalid endpoints %d", ErrInvalidEncoding, h.endpoints)
+	}
+
+	if data[7]&^(unorderedFlag|removedFlag) != 0 {
+		return h, fmt.Errorf("%w: invalid flags %d", ErrInvalidEncoding, data[7])
+	}
+
+	ordered := data[7]&unorderedFlag == 0
+	h.ordered = &ordered
+
+	nIndexes := h.order.Uint64(data[8:])
+	nLimits := h.order.Uint64(data[16:])
+
+	if nLimits%3 != 0 || nLimits/3 != nIndexes {
+		return h, fmt.Errorf("%w: length mismatch between %d indexes and %d limits", ErrInvalidEncoding, nIndexes, nLimits)
+	}
+
+	// Each node takes 8 bytes for its index and 24 bytes for its limits, plus a bit of the removed intervals bitmap
+	var words uint64
+
+	size := uint64(len(data) - headerSize)
+	if nIndexes <= size/32 && data[7]&removedFlag != 0 {
+		words = (nIndexes + 63) / 64
+	}
+
+	if nIndexes > size/32 || size != 32*nIndexes+8*words {
+		return h, fmt.Errorf("%w: %d bytes of nodes do not match %d indexes", ErrInvalidEncoding, size, nIndexes)
+	}
+
+	h.nodes = int(nIndexes)
+
+	if words > 0 {
+		h.tombstones = make([]bool, h.nodes)
+		offset := headerSize + 32*h.nodes
+
+		for w := 0; w < int(words); w++ {
+			word := h.order.Uint64(data[offset+8*w:])
+
+			for b := 0; b < 64; b++ {
+				if word&(1<<b) == 0 {
+					continue
+				}
+
+				if 64*w+b >= h.nodes {
+					return h, fmt.Errorf("%w: removed index %d out of %d indexes", ErrInvalidEncoding, 64*w+b, h.nodes)
+				}
+
+				h.tombstones[64*w+b] = true
+			}
+		}
+	}
+
+	return h, nil
+}
+
+// setNodes is an internal utility function, validating the decoded indexes as a permutation
+// and replacing the tree contents with the given nodes.
+func (t *INTree) setNodes(indexes []int, limits []float64) error {
+	seen := make([]bool, len(indexes))
+
+	for i, idx := range indexes {
+		if idx < 0 || idx >= len(indexes) || seen[idx] {
+			return fmt.Errorf("%w: invalid index %d at position %d", ErrInvalidEncoding, uint64(idx), i)
+		}
+
+		seen[idx] = true
+	}
+
+	t.indexes = indexes
+	t.limits = limits
+	t.positions = mapPositions(indexes)
+	t.values = nil
+	t.bounds = nil
+	t.staged = nil
+	t.buffered = 0
+	t.unordered = !isLowerSorted(limits)
+	t.readOnly = false
+	t.endpoints = Closed
+	t.layout = nil
+	t.tombstones = nil
+	t.removed = 0
+	t.metadata = nil
+	t.priorities = nil
+	t.priorityMax = nil
+	t.view = nil
+
+	return nil
+}
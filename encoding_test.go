@@ -0,0 +1,249 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Binary(t *testing.T) {
+	t.Run("Case_Roundtrip", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+
+		assert.EqualValues(t, tree.Len(), decoded.Len())
+		assert.EqualValues(t, tree.Intervals(), decoded.Intervals())
+		for val := -1.0; val <= 106.0; val += 0.25 {
+			assert.EqualValues(t, tree.Including(val), decoded.Including(val))
+		}
+	})
+	t.Run("Case_Roundtrip/example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.EqualValues(t, tree.Including(4.3), decoded.Including(4.3))
+	})
+	t.Run("Case_Roundtrip/byte_orders", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian, binary.NativeEndian} {
+			data, err := tree.MarshalBinaryOrder(order)
+			assert.NoError(t, err)
+			assert.EqualValues(t, "INTR", string(data[:4]))
+			assert.EqualValues(t, 1000, order.Uint64(data[8:]))
+
+			decoded := &intree.INTree{}
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.EqualValues(t, tree.Intervals(), decoded.Intervals())
+			for val := -1.0; val <= 106.0; val += 0.25 {
+				assert.EqualValues(t, tree.Including(val), decoded.Including(val))
+			}
+		}
+
+		_, err := tree.MarshalBinaryOrder(wrappedOrder{binary.LittleEndian})
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+	})
+	t.Run("Case_Roundtrip/custom_order", func(t *testing.T) {
+		bounds := exampleBounds()
+		tree, err := intree.NewINTreeSortedBy(bounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, data[7])
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.EqualValues(t, tree.Including(4.3), decoded.Including(4.3))
+	})
+	t.Run("Case_Roundtrip/staged", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		tree.Add(&testBounds{Lower: 4.0, Upper: 5.0})
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		// Staged intervals are neither encoded nor kept by the decoded tree
+		decoded := intree.NewINTree(nil)
+		decoded.Add(&testBounds{Lower: 4.0, Upper: 5.0})
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+
+		decoded.Rebuild()
+		assert.EqualValues(t, 13, decoded.Len())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, decoded.Including(4.3))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		data, err := intree.NewINTree(nil).MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.EqualValues(t, 0, decoded.Len())
+		assert.EqualValues(t, 0, len(decoded.Including(4.3)))
+	})
+	t.Run("Case_Border/truncated", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		for size := 0; size < len(data); size++ {
+			err := (&intree.INTree{}).UnmarshalBinary(data[:size])
+			assert.True(t, errors.Is(err, intree.ErrInvalidEncoding), "size %d", size)
+		}
+	})
+	t.Run("Case_Border/length_mismatch", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		binary.LittleEndian.PutUint64(data[16:], 3*13+1)
+		err = (&intree.INTree{}).UnmarshalBinary(data)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+
+		binary.LittleEndian.PutUint64(data[8:], 1<<62)
+		binary.LittleEndian.PutUint64(data[16:], 3<<62)
+		err = (&intree.INTree{}).UnmarshalBinary(data)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+	})
+	t.Run("Case_Border/invalid_index", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		binary.LittleEndian.PutUint64(data[24:], 13)
+		err = (&intree.INTree{}).UnmarshalBinary(data)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+	})
+	t.Run("Case_Border/unsupported_version", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		data[4] = 0xff
+		err = (&intree.INTree{}).UnmarshalBinary(data)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+
+		data[0] = 0xff
+		err = (&intree.INTree{}).UnmarshalBinary(data)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+	})
+	t.Run("Case_Border/invalid_header_bytes", func(t *testing.T) {
+		for offset, value := range map[int]byte{5: 2, 6: 4, 7: 4} {
+			data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+			assert.NoError(t, err)
+
+			data[offset] = value
+			err = (&intree.INTree{}).UnmarshalBinary(data)
+			assert.True(t, errors.Is(err, intree.ErrInvalidEncoding), "byte %d", offset)
+		}
+	})
+	t.Run("Case_Border/removed_bitmap", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.NoError(t, tree.Remove(3))
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		bitmap := 24 + 32*tree.Len()
+
+		// Flagging a live interval or one out of the indexes as removed is rejected
+		for _, word := range []uint64{1<<3 | 1<<4, 1<<3 | 1<<13} {
+			corrupted := append([]byte(nil), data...)
+			binary.LittleEndian.PutUint64(corrupted[bitmap:], word)
+
+			err = (&intree.INTree{}).UnmarshalBinary(corrupted)
+			assert.True(t, errors.Is(err, intree.ErrInvalidEncoding), "word %b", word)
+		}
+
+		err = (&intree.INTree{}).UnmarshalBinary(data[:bitmap])
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+	})
+	t.Run("Case_Border/corrupted_nodes", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		limits := 24 + 8*tree.Len()
+
+		// Raising an upper limit leaves a stale augmented limit above it
+		corrupted := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(corrupted[limits+8:], math.Float64bits(100.0))
+
+		decoded := intree.NewINTree(exampleBounds())
+		err = decoded.UnmarshalBinary(corrupted)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+		assert.True(t, errors.Is(err, intree.ErrCorruptedTree))
+		assert.EqualValues(t, tree.Len(), decoded.Len(), "the tree must be left untouched")
+
+		// Lower limits out of order on a tree stated as sorted
+		corrupted = append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(corrupted[limits:], math.Float64bits(100.0))
+
+		err = (&intree.INTree{}).UnmarshalBinary(corrupted)
+		assert.True(t, errors.Is(err, intree.ErrCorruptedTree))
+	})
+}
+
+// wrappedOrder is a byte order unknown to the binary encoding.
+type wrappedOrder struct {
+	binary.ByteOrder
+}
+
+func Benchmark_UnmarshalBinary(b *testing.B) {
+	data, err := intree.NewINTree(randomBounds(100000, 1000.0, 10.0)).MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := (&intree.INTree{}).UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark_NewINTree is the rebuild baseline for Benchmark_UnmarshalBinary.
+func Benchmark_NewINTree(b *testing.B) {
+	bounds := randomBounds(100000, 1000.0, 10.0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		intree.NewINTree(bounds)
+	}
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "math"
+
+// Endpoints defines whether searches include the interval limits, for all the intervals of a tree.
+type Endpoints uint8
+
+const (
+	// Closed intervals include both limits: [lower, upper]. This is the default.
+	Closed Endpoints = 0
+	// LowerOpen intervals exclude their lower limit: (lower, upper].
+	LowerOpen Endpoints = 1 << 0
+	// UpperOpen intervals exclude their upper limit: [lower, upper). Useful for time slots.
+	UpperOpen Endpoints = 1 << 1
+	// Open intervals exclude both limits: (lower, upper).
+	Open = LowerOpen | UpperOpen
+)
+
+// String returns the interval notation of the endpoints.
+func (e Endpoints) String() string {
+	switch e {
+	case Closed:
+		return "[lower, upper]"
+	case LowerOpen:
+		return "(lower, upper]"
+	case UpperOpen:
+		return "[lower, upper)"
+	case Open:
+		return "(lower, upper)"
+	}
+
+	return "invalid"
+}
+
+// Endpoints returns whether searches include the interval limits.
+func (t *INTree) Endpoints() Endpoints {
+	return t.endpoints
+}
+
+// searchRange is an internal utility function, turning the given closed search range into the one matching
+// closed intervals exactly when the original range matches the tree intervals, as the endpoints searchRange does.
+func (t *INTree) searchRange(lower, upper float64) (float64, float64) {
+	return t.endpoints.searchRange(lower, upper)
+}
+
+// searchRange is an internal utility function, turning the given closed search range into the one matching
+// closed intervals exactly when the original range matches intervals of the endpoints: excluded lower limits must
+// lie strictly below the range upper limit, and excluded upper limits strictly above the range lower limit.
+// Ranges with swapped limits (as used for containment searches) are adjusted the same way.
+func (e Endpoints) searchRange(lower, upper float64) (float64, float64) {
+	if e&UpperOpen != 0 {
+		lower = math.Nextafter(lower, math.Inf(1))
+	}
+
+	if e&LowerOpen != 0 {
+		upper = math.Nextafter(upper, math.Inf(-1))
+	}
+
+	return lower, upper
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+// includes checks whether the given interval includes the value according to the endpoints.
+func includes(e intree.Endpoints, lower, upper, val float64) bool {
+	if e&intree.LowerOpen != 0 && val <= lower || val < lower {
+		return false
+	}
+
+	return !(e&intree.UpperOpen != 0 && val >= upper || val > upper)
+}
+
+func Test_Tree_Endpoints(t *testing.T) {
+	inputBounds := []intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 2.0, Upper: 4.0},
+		&testBounds{Lower: 4.0, Upper: 4.0},
+	}
+
+	t.Run("Case_Example", func(t *testing.T) {
+		for _, tc := range []struct {
+			endpoints intree.Endpoints
+			at2, at4  []int
+		}{
+			{intree.Closed, []int{0, 1}, []int{1, 2}},
+			{intree.UpperOpen, []int{1}, []int{}},
+			{intree.LowerOpen, []int{0}, []int{1}},
+			{intree.Open, []int{}, []int{}},
+		} {
+			tree := intree.NewINTree(inputBounds, intree.WithEndpoints(tc.endpoints))
+
+			assert.EqualValues(t, tc.endpoints, tree.Endpoints())
+			assert.ElementsMatch(t, tc.at2, tree.Including(2.0), tc.endpoints.String())
+			assert.ElementsMatch(t, tc.at4, tree.Including(4.0), tc.endpoints.String())
+			assert.EqualValues(t, len(tc.at2), tree.CountIncluding(2.0))
+
+			for idx, b := range inputBounds {
+				l, u := b.Limits()
+				assert.EqualValues(t, includes(tc.endpoints, l, u, 2.0), tree.CoversIndex(2.0, idx))
+			}
+		}
+	})
+	t.Run("Case_Example/time_slots", func(t *testing.T) {
+		// Consecutive [start, end) slots never overlap at their boundaries
+		tree := intree.NewINTree(inputBounds[:2], intree.WithEndpoints(intree.UpperOpen))
+
+		assert.ElementsMatch(t, []int{0}, tree.Including(0.0))
+		assert.ElementsMatch(t, []int{1}, tree.Including(2.0))
+		assert.ElementsMatch(t, []int{1}, tree.Overlapping(2.0, 3.0))
+		assert.ElementsMatch(t, []int{0}, tree.Covering(1.0, 1.5))
+		assert.Empty(t, tree.Covering(1.0, 2.0))
+		assert.False(t, tree.CoversIndex(2.0, 0))
+		assert.True(t, tree.CoversIndex(2.0, 1))
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		// Integer limits make matches at the boundaries frequent
+		bounds := randomBounds(500, 100.0, 5.0)
+		for i, b := range bounds {
+			l, u := b.Limits()
+			bounds[i] = &testBounds{Lower: float64(int(l)), Upper: float64(int(u))}
+		}
+
+		for _, e := range []intree.Endpoints{intree.Closed, intree.LowerOpen, intree.UpperOpen, intree.Open} {
+			tree := intree.NewINTree(bounds, intree.WithEndpoints(e))
+
+			for val := -1.0; val <= 106.0; val += 0.5 {
+				expected := []int{}
+				for i, b := range bounds {
+					l, u := b.Limits()
+					if includes(e, l, u, val) {
+						expected = append(expected, i)
+					}
+				}
+
+				assert.ElementsMatch(t, expected, tree.Including(val), "%s at %.1f", e, val)
+			}
+		}
+	})
+	t.Run("Case_Coalesce/touching", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds[:1], intree.WithEndpoints(intree.UpperOpen))
+
+		tree.InsertCoalesce(&testBounds{Lower: 2.0, Upper: 3.0}, 0)
+		assert.EqualValues(t, [][2]float64{{0.0, 3.0}}, tree.Intervals())
+	})
+	t.Run("Case_Encoding", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds, intree.WithEndpoints(intree.UpperOpen))
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.EqualValues(t, intree.UpperOpen, decoded.Endpoints())
+		assert.ElementsMatch(t, []int{1}, decoded.Including(2.0))
+
+		path := filepath.Join(t.TempDir(), "tree.bin")
+		assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+		mapped, err := intree.OpenMMap(path)
+		assert.NoError(t, err)
+		defer mapped.Close()
+		assert.EqualValues(t, intree.UpperOpen, mapped.Endpoints())
+
+		data, err = json.Marshal(tree)
+		assert.NoError(t, err)
+
+		decoded = &intree.INTree{}
+		assert.NoError(t, json.Unmarshal(data, decoded))
+		assert.EqualValues(t, intree.UpperOpen, decoded.Endpoints())
+		assert.ElementsMatch(t, []int{1}, decoded.Including(2.0))
+
+		assert.Error(t, (&intree.INTree{}).UnmarshalBinary(append([]byte{2, 0xff}, make([]byte, 22)...)))
+		assert.Error(t, json.Unmarshal([]byte(`{"intervals":[],"order":[],"endpoints":4}`), &intree.INTree{}))
+	})
+	t.Run("Case_Border/string", func(t *testing.T) {
+		assert.EqualValues(t, "[lower, upper)", intree.UpperOpen.String())
+		assert.EqualValues(t, "invalid", intree.Endpoints(8).String())
+	})
+}
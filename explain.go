@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation describes how a search traverses the tree, as returned by Explain.
+type Explanation struct {
+	// Value is the explained value, and Lower and Upper the searched range after applying the tree endpoints
+	Value, Lower, Upper float64
+	// Steps holds the subtrees reached by the traversal, in depth first order
+	Steps []ExplainStep
+	// Matches holds the indices of the matching intervals, in traversal order
+	Matches []int
+}
+
+// ExplainStep describes a subtree reached by a traversal, through the limits of its root node.
+type ExplainStep struct {
+	// Depth is the depth of the subtree root, 0 for the tree root
+	Depth int
+	// Position and Index are the node position in the tree and the original index of its interval
+	Position, Index int
+	// Lower and Upper are the interval limits, and Max the augmented limit: the greatest upper limit in the subtree
+	Lower, Upper, Max float64
+	// Match reports whether the interval overlaps with the searched range
+	Match bool
+	// Pruned holds why the subtree was skipped without visiting its root, or why its root is not a match
+	Pruned string
+}
+
+// Explain is the debugging counterpart of Including, for users suspecting a wrong result; returns the traversal
+// of a search for the given value: every subtree reached, whether its root matches and, if not, why, and which
+// subtrees were pruned and why, against the augmented limit or the lower limits. Trees whose nodes are not sorted
+// by lower limit are explained through a sorted copy, and trees with an Eytzinger layout through the in-order
+// nodes, which match the same intervals. Formatted for human inspection by Explanation.String.
+func (t *INTree) Explain(val float64) Explanation {
+	lower, upper := t.searchRange(val, val)
+	e := Explanation{Value: val, Lower: lower, Upper: upper, Steps: []ExplainStep{}, Matches: []int{}}
+
+	t = t.lowerOrdered()
+	t.explain(0, len(t.indexes)-1, 0, &e)
+
+	return e
+}
+
+// explain is an internal utility function, appending the traversal steps of the subtree in the given bounds.
+func (t *INTree) explain(lBoundIdx, rBoundIdx, depth int, e *Explanation) {
+	if lBoundIdx > rBoundIdx {
+		return
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+	node := (*[3]float64)(t.limits[3*centerIdx:])
+	step := ExplainStep{
+		Depth:    depth,
+		Position: centerIdx,
+		Index:    t.indexes[centerIdx],
+		Lower:    node[0],
+		Upper:    node[1],
+		Max:      node[2],
+	}
+
+	switch {
+	case e.Lower > node[2]:
+		step.Pruned = fmt.Sprintf("subtree pruned: max %v < %v", node[2], e.Lower)
+		e.Steps = append(e.Steps, step)
+
+		return
+	case node[0] > e.Upper:
+		step.Pruned = fmt.Sprintf("lower %v > %v, right subtree pruned", node[0], e.Upper)
+	case e.Lower > node[1]:
+		step.Pruned = fmt.Sprintf("upper %v < %v", node[1], e.Lower)
+	default:
+		step.Match = true
+		e.Matches = append(e.Matches, step.Index)
+	}
+
+	e.Steps = append(e.Steps, step)
+	t.explain(lBoundIdx, centerIdx-1, depth+1, e)
+
+	if node[0] <= e.Upper {
+		t.explain(centerIdx+1, rBoundIdx, depth+1, e)
+	}
+}
+
+// String formats the explanation as an indented traversal outline, one reached subtree per line.
+func (e Explanation) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "including %v, searched as [%v, %v]: %d matches %v\n", e.Value, e.Lower, e.Upper, len(e.Matches), e.Matches)
+
+	for _, s := range e.Steps {
+		outcome := "match"
+		if !s.Match {
+			outcome = s.Pruned
+		}
+
+		fmt.Fprintf(&sb, "%s#%d index %d [%v, %v] max %v: %s\n",
+			strings.Repeat("  ", s.Depth), s.Position, s.Index, s.Lower, s.Upper, s.Max, outcome)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Explain(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 2.0, Upper: 3.0},
+			&testBounds{Lower: 4.0, Upper: 6.0},
+		}, intree.WithDeterministicSort())
+
+		e := tree.Explain(5.0)
+		assert.EqualValues(t, []int{2}, e.Matches)
+		assert.EqualValues(t, []intree.ExplainStep{
+			{Depth: 0, Position: 1, Index: 1, Lower: 2.0, Upper: 3.0, Max: 6.0, Pruned: "upper 3 < 5"},
+			{Depth: 1, Position: 0, Index: 0, Lower: 0.0, Upper: 1.0, Max: 1.0, Pruned: "subtree pruned: max 1 < 5"},
+			{Depth: 1, Position: 2, Index: 2, Lower: 4.0, Upper: 6.0, Max: 6.0, Match: true},
+		}, e.Steps)
+
+		assert.EqualValues(t, "including 5, searched as [5, 5]: 1 matches [2]\n"+
+			"#1 index 1 [2, 3] max 6: upper 3 < 5\n"+
+			"  #0 index 0 [0, 1] max 1: subtree pruned: max 1 < 5\n"+
+			"  #2 index 2 [4, 6] max 6: match\n", e.String())
+
+		e = tree.Explain(1.5)
+		assert.Empty(t, e.Matches)
+		assert.EqualValues(t, "lower 2 > 1.5, right subtree pruned", e.Steps[0].Pruned)
+		assert.Len(t, e.Steps, 2)
+	})
+	t.Run("Case_Matches", func(t *testing.T) {
+		bounds := randomBounds(1000, 100.0, 10.0)
+
+		for _, opts := range [][]intree.Option{nil, {intree.WithEytzingerLayout()}, {intree.WithEndpoints(intree.Open)}} {
+			tree := intree.NewINTree(bounds, opts...)
+
+			for val := 0.0; val <= 100.0; val += 9.7 {
+				e := tree.Explain(val)
+				assert.ElementsMatch(t, tree.Including(val), e.Matches, "at %.1f", val)
+				assert.Less(t, len(e.Steps), tree.Len())
+				assert.EqualValues(t, len(e.Steps)+1, strings.Count(e.String(), "\n"))
+			}
+		}
+	})
+	t.Run("Case_Unordered", func(t *testing.T) {
+		tree, err := intree.NewINTreeSortedBy(exampleBounds(), func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		assert.ElementsMatch(t, tree.Including(4.3), tree.Explain(4.3).Matches)
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		e := intree.NewINTree(nil).Explain(1.0)
+		assert.Empty(t, e.Steps)
+		assert.Empty(t, e.Matches)
+	})
+}
@@ -0,0 +1,229 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// forestMagic opens the binary encoding of forests.
+const forestMagic = "INTF"
+
+// forestVersion is the version byte written after the magic of the forest binary encoding.
+const forestVersion byte = 1
+
+// forestHeaderSize is the size of the forest binary encoding header: magic, version and padding bytes, plus the
+// trees count.
+const forestHeaderSize = 8 + 8
+
+var (
+	_ encoding.BinaryMarshaler   = (*Forest)(nil)
+	_ encoding.BinaryUnmarshaler = (*Forest)(nil)
+)
+
+// Forest is a collection of trees keyed by name, for partitioned datasets such as per tenant, per chromosome or
+// per shard intervals. The zero value is an empty forest building trees with default options.
+// Searches on a missing key match no interval. All methods are safe for concurrent use,
+// although trees obtained through Tree are subject to the INTree concurrency rules.
+type Forest struct {
+	mu    sync.RWMutex
+	trees map[string]*INTree
+	opts  []Option
+}
+
+// NewForest is the forest initialization function;
+// creates an empty forest whose trees are built with the given options.
+func NewForest(opts ...Option) *Forest {
+	return &Forest{trees: map[string]*INTree{}, opts: opts}
+}
+
+// Build creates the tree of the given key from the given Slice of bounds, replacing any previous one.
+// The tree is built before taking the forest lock, so searches on other keys are not blocked meanwhile.
+func (f *Forest) Build(key string, bounds []Bounds) {
+	f.Set(key, NewINTree(bounds, f.opts...))
+}
+
+// Set stores the given tree under the given key, replacing any previous one. A nil tree deletes the key.
+func (f *Forest) Set(key string, t *INTree) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if t == nil {
+		delete(f.trees, key)
+		return
+	}
+
+	if f.trees == nil {
+		f.trees = map[string]*INTree{}
+	}
+
+	f.trees[key] = t
+}
+
+// Delete removes the tree of the given key, if any.
+func (f *Forest) Delete(key string) {
+	f.Set(key, nil)
+}
+
+// Tree returns the tree of the given key, if any.
+func (f *Forest) Tree(key string) (*INTree, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	t, ok := f.trees[key]
+
+	return t, ok
+}
+
+// Keys returns the sorted keys of the forest trees.
+func (f *Forest) Keys() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(f.trees))
+}
+
+// Len returns the number of trees stored in the forest.
+func (f *Forest) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.trees)
+}
+
+// Including collects the intervals of the tree of the given key that contain the given value, boundaries included.
+func (f *Forest) Including(key string, val float64) []int {
+	t, ok := f.Tree(key)
+	if !ok {
+		return []int{}
+	}
+
+	return t.Including(val)
+}
+
+// Overlapping collects the intervals of the tree of the given key that overlap with the given range,
+// boundaries included.
+func (f *Forest) Overlapping(key string, lower, upper float64) []int {
+	t, ok := f.Tree(key)
+	if !ok {
+		return []int{}
+	}
+
+	return t.Overlapping(lower, upper)
+}
+
+// MarshalBinary encodes every tree of the forest into a single binary form: a header holding the magic,
+// the version byte and the trees count, followed by the key and MarshalBinary encoding of each tree, in key
+// order, prefixed by their lengths. The forest options are not encoded, as trees keep their own endpoints.
+func (f *Forest) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	data := make([]byte, forestHeaderSize, forestHeaderSize+32*len(f.trees))
+	copy(data, forestMagic)
+	data[4] = forestVersion
+	binary.LittleEndian.PutUint64(data[8:], uint64(len(f.trees)))
+
+	for _, key := range slices.Sorted(maps.Keys(f.trees)) {
+		encoded, err := f.trees[key].MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("encoding tree %q: %w", key, err)
+		}
+
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(key)))
+		data = append(data, key...)
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(encoded)))
+		data = append(data, encoded...)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a forest previously encoded by MarshalBinary, replacing its trees.
+// Returns an ErrInvalidEncoding wrapped error on an unknown version or truncated data, or if any tree
+// fails to decode; the forest is left untouched on error.
+func (f *Forest) UnmarshalBinary(data []byte) error {
+	if len(data) < forestHeaderSize || string(data[:len(forestMagic)]) != forestMagic {
+		return fmt.Errorf("%w: invalid forest header", ErrInvalidEncoding)
+	}
+
+	if data[4] != forestVersion {
+		return fmt.Errorf("%w: unsupported forest version %d", ErrInvalidEncoding, data[4])
+	}
+
+	count := binary.LittleEndian.Uint64(data[8:])
+	data = data[forestHeaderSize:]
+
+	// Each tree takes at least its two lengths, which bounds the preallocation on corrupted counts
+	trees := make(map[string]*INTree, min(count, uint64(len(data)/16)))
+
+	for i := uint64(0); i < count; i++ {
+		key, rest, err := forestChunk(data)
+		if err != nil {
+			return err
+		}
+
+		encoded, rest, err := forestChunk(rest)
+		if err != nil {
+			return err
+		}
+
+		t := &INTree{}
+		if err := t.UnmarshalBinary(encoded); err != nil {
+			return fmt.Errorf("decoding tree %q: %w", key, err)
+		}
+
+		trees[string(key)] = t
+		data = rest
+	}
+
+	if len(data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes after %d trees", ErrInvalidEncoding, len(data), count)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.trees = trees
+
+	return nil
+}
+
+// forestChunk is an internal utility function, splitting a length prefixed chunk off the given data.
+func forestChunk(data []byte) (chunk, rest []byte, err error) {
+	if len(data) < 8 {
+		return nil, nil, fmt.Errorf("%w: truncated forest (%d bytes)", ErrInvalidEncoding, len(data))
+	}
+
+	size := binary.LittleEndian.Uint64(data)
+	if data = data[8:]; size > uint64(len(data)) {
+		return nil, nil, fmt.Errorf("%w: chunk of %d bytes exceeds the remaining %d", ErrInvalidEncoding, size, len(data))
+	}
+
+	return data[:size], data[size:], nil
+}
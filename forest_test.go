@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Forest(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		forest := intree.NewForest()
+		forest.Build("tenant-a", []intree.Bounds{intree.Interval{Lower: 0, Upper: 2}, intree.Interval{Lower: 1, Upper: 3}})
+		forest.Build("tenant-b", []intree.Bounds{intree.Interval{Lower: 5, Upper: 6}})
+
+		assert.EqualValues(t, 2, forest.Len())
+		assert.EqualValues(t, []string{"tenant-a", "tenant-b"}, forest.Keys())
+		assert.ElementsMatch(t, []int{0, 1}, forest.Including("tenant-a", 1.5))
+		assert.Empty(t, forest.Including("tenant-b", 1.5))
+		assert.Empty(t, forest.Including("tenant-c", 1.5))
+		assert.EqualValues(t, []int{0}, forest.Overlapping("tenant-b", 4, 5))
+		assert.Empty(t, forest.Overlapping("tenant-c", 4, 5))
+
+		forest.Build("tenant-b", []intree.Bounds{intree.Interval{Lower: 7, Upper: 8}})
+		assert.Empty(t, forest.Including("tenant-b", 5.5))
+
+		forest.Delete("tenant-b")
+		_, ok := forest.Tree("tenant-b")
+		assert.False(t, ok)
+		assert.EqualValues(t, []string{"tenant-a"}, forest.Keys())
+	})
+	t.Run("Case_Options", func(t *testing.T) {
+		forest := intree.NewForest(intree.WithEndpoints(intree.UpperOpen))
+		forest.Build("a", []intree.Bounds{intree.Interval{Lower: 0, Upper: 2}})
+
+		assert.Empty(t, forest.Including("a", 2))
+	})
+	t.Run("Case_Zero_value", func(t *testing.T) {
+		var forest intree.Forest
+		assert.Empty(t, forest.Including("a", 1))
+
+		forest.Build("a", []intree.Bounds{intree.Interval{Lower: 0, Upper: 2}})
+		assert.EqualValues(t, []int{0}, forest.Including("a", 1))
+	})
+	t.Run("Case_Binary_encoding", func(t *testing.T) {
+		forest := intree.NewForest()
+		forest.Build("a", []intree.Bounds{intree.Interval{Lower: 0, Upper: 2}, intree.Interval{Lower: 1, Upper: 3}})
+		forest.Set("b", intree.NewINTree([]intree.Bounds{intree.Interval{Lower: 5, Upper: 6}}, intree.WithEndpoints(intree.Open)))
+		forest.Build("empty", nil)
+
+		data, err := forest.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.Forest{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.EqualValues(t, []string{"a", "b", "empty"}, decoded.Keys())
+		assert.ElementsMatch(t, []int{0, 1}, decoded.Including("a", 1.5))
+		assert.EqualValues(t, []int{0}, decoded.Including("b", 5.5))
+		assert.Empty(t, decoded.Including("b", 6))
+		assert.Empty(t, decoded.Including("empty", 0))
+	})
+	t.Run("Case_Border/invalid_encoding", func(t *testing.T) {
+		forest := intree.NewForest()
+		forest.Build("a", []intree.Bounds{intree.Interval{Lower: 0, Upper: 2}})
+
+		data, err := forest.MarshalBinary()
+		assert.NoError(t, err)
+
+		for size := 0; size < len(data); size++ {
+			decoded := intree.NewForest()
+			decoded.Build("kept", nil)
+
+			assert.ErrorIs(t, decoded.UnmarshalBinary(data[:size]), intree.ErrInvalidEncoding, "size %d", size)
+			assert.EqualValues(t, []string{"kept"}, decoded.Keys())
+		}
+
+		corrupted := append([]byte{}, data...)
+		corrupted[4] = 9
+		assert.ErrorIs(t, intree.NewForest().UnmarshalBinary(corrupted), intree.ErrInvalidEncoding)
+		assert.ErrorIs(t, intree.NewForest().UnmarshalBinary(append(data, 0)), intree.ErrInvalidEncoding)
+	})
+}
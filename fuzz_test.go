@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lggomez/intree"
+)
+
+// fuzzOverlaps is the brute force reference of the fuzz target, reporting whether the [lower, upper] interval
+// with the given endpoints overlaps with the closed [lo, hi] range: excluded lower limits must lie strictly
+// below hi, and excluded upper limits strictly above lo.
+func fuzzOverlaps(lower, upper, lo, hi float64, endpoints intree.Endpoints) bool {
+	lowerOk := lower <= hi
+	if endpoints&intree.LowerOpen != 0 {
+		lowerOk = lower < hi
+	}
+
+	upperOk := lo <= upper
+	if endpoints&intree.UpperOpen != 0 {
+		upperOk = lo < upper
+	}
+
+	return lowerOk && upperOk
+}
+
+// fuzzCovers is the brute force reference of the fuzz target, reporting whether the [lower, upper] interval
+// with the given endpoints contains the closed [lo, hi] range.
+func fuzzCovers(lower, upper, lo, hi float64, endpoints intree.Endpoints) bool {
+	lowerOk := lower <= lo
+	if endpoints&intree.LowerOpen != 0 {
+		lowerOk = lower < lo
+	}
+
+	upperOk := hi <= upper
+	if endpoints&intree.UpperOpen != 0 {
+		upperOk = hi < upper
+	}
+
+	return lowerOk && upperOk
+}
+
+// Fuzz_Tree compares the tree searches against a brute force scan, over intervals and queries decoded from
+// the fuzzed data: every pair of bytes is a valid interval with half unit limits, so that limits are often shared.
+// Run it with go test -fuzz Fuzz_Tree.
+func Fuzz_Tree(f *testing.F) {
+	f.Add([]byte{}, uint8(0), int8(0), int8(0))
+	f.Add([]byte{0, 4, 2, 6, 4, 4}, uint8(0), int8(2), int8(4))
+	f.Add([]byte{0, 4, 2, 6, 4, 4, 250, 3}, uint8(1), int8(4), int8(8))
+	f.Add([]byte{1, 2, 2, 3, 3, 4, 4, 5, 5, 6}, uint8(2), int8(2), int8(6))
+	f.Add([]byte{10, 2, 2, 2, 0, 20, 5, 7, 7, 9, 1, 1}, uint8(3), int8(5), int8(2))
+
+	f.Fuzz(func(t *testing.T, data []byte, endpoints uint8, q1, q2 int8) {
+		e := intree.Endpoints(endpoints % 4)
+		limits := make([][2]float64, 0, len(data)/2)
+		bounds := make([]intree.Bounds, 0, len(data)/2)
+
+		for i := 0; i+1 < len(data); i += 2 {
+			l, u := float64(int8(data[i]))/2, float64(int8(data[i+1]))/2
+			if l > u {
+				l, u = u, l
+			}
+
+			limits = append(limits, [2]float64{l, u})
+			bounds = append(bounds, intree.Interval{Lower: l, Upper: u})
+		}
+
+		trees := map[string]*intree.INTree{
+			"default":   intree.NewINTree(bounds, intree.WithEndpoints(e)),
+			"eytzinger": intree.NewINTree(bounds, intree.WithEndpoints(e), intree.WithEytzingerLayout()),
+		}
+
+		lo, hi := float64(q1)/2, float64(q2)/2
+		values := []float64{lo, hi, lo + 0.25}
+
+		for _, l := range limits {
+			values = append(values, l[0], l[1])
+		}
+
+		for name, tree := range trees {
+			if err := tree.CheckInvariants(); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+
+			for _, v := range values {
+				expected := []int{}
+				for i, l := range limits {
+					if fuzzOverlaps(l[0], l[1], v, v, e) {
+						expected = append(expected, i)
+					}
+				}
+
+				if got := tree.IncludingSorted(v); !slices.Equal(expected, got) {
+					t.Fatalf("%s: Including(%v) with %v endpoints = %v, expected %v", name, v, e, got, expected)
+				}
+
+				if got := tree.CountIncluding(v); got != len(expected) {
+					t.Fatalf("%s: CountIncluding(%v) = %d, expected %d", name, v, got, len(expected))
+				}
+
+				if idx, ok := tree.AnyIncluding(v); ok != (len(expected) > 0) || ok && !slices.Contains(expected, idx) {
+					t.Fatalf("%s: AnyIncluding(%v) = %d, %v, expected one of %v", name, v, idx, ok, expected)
+				}
+			}
+
+			overlapping, covering := []int{}, []int{}
+			for i, l := range limits {
+				if lo <= hi && fuzzOverlaps(l[0], l[1], lo, hi, e) {
+					overlapping = append(overlapping, i)
+				}
+
+				if lo <= hi && fuzzCovers(l[0], l[1], lo, hi, e) {
+					covering = append(covering, i)
+				}
+			}
+
+			if got := tree.Overlapping(lo, hi); !slices.Equal(overlapping, slices.Sorted(slices.Values(got))) {
+				t.Fatalf("%s: Overlapping(%v, %v) with %v endpoints = %v, expected %v", name, lo, hi, e, got, overlapping)
+			}
+
+			if got := tree.Covering(lo, hi); !slices.Equal(covering, slices.Sorted(slices.Values(got))) {
+				t.Fatalf("%s: Covering(%v, %v) with %v endpoints = %v, expected %v", name, lo, hi, e, got, covering)
+			}
+		}
+	})
+}
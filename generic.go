@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"math/rand"
+)
+
+// ValuedBoundsG is the main interface expected by NewINTreeVG(), acting as a typed wrapper for Bounds.
+// Expects the Value() method for retrieving a value associated with the given boundaries
+type ValuedBoundsG[V any] interface {
+	Bounds
+	Value() V
+}
+
+// INTreeG is the typed counterpart of INTree;
+// holds the tree and the typed values associated to each interval.
+type INTreeG[V any] struct {
+	tree   INTree
+	values []V
+}
+
+// INTreeV is the typed value storing tree created by NewINTreeVTyped;
+// embeds INTreeG, so its methods and IncludingValues return the typed values.
+type INTreeV[V any] struct {
+	INTreeG[V]
+}
+
+// NewINTreeVTyped is the typed initialization function returning an INTreeV;
+// creates the tree from the given Slice of ValuedBoundsG as NewINTreeVG does.
+func NewINTreeVTyped[V any](bounds []ValuedBoundsG[V]) *INTreeV[V] {
+	return &INTreeV[V]{INTreeG: *NewINTreeVG(bounds)}
+}
+
+// NewINTreeVG is the typed initialization function;
+// creates the tree from the given Slice of ValuedBoundsG, storing values without boxing.
+// Values are stored by original index, so that sorting the nodes leaves them in place.
+func NewINTreeVG[V any](bounds []ValuedBoundsG[V]) *INTreeG[V] {
+	indexes, limits := make([]int, len(bounds)), make([]float64, 3*len(bounds))
+	values := make([]V, len(bounds))
+
+	for i, b := range bounds {
+		indexes[i] = i
+		values[i] = b.Value()
+		limits[3*i], limits[3*i+1] = b.Limits()
+	}
+
+	return &INTreeG[V]{tree: *build(indexes, limits, nil, nil, rand.New(runtimeSource{}), options{}), values: values}
+}
+
+// Including traverses the tree and collects the indices of the intervals that overlap with the given value.
+func (t *INTreeG[V]) Including(val float64) []int {
+	return t.tree.Including(val)
+}
+
+// IncludingValues traverses the tree and collects the typed values of the intervals
+// that overlap with the given value, in the same order as Including.
+func (t *INTreeG[V]) IncludingValues(val float64) []V {
+	result := []V{}
+
+	t.tree.traverse(val, val, func(pos int) bool {
+		result = append(result, t.values[t.tree.indexes[pos]])
+		return true
+	})
+
+	return result
+}
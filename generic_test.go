@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+type record struct {
+	ID   int
+	Name string
+}
+
+type typedTestBounds struct {
+	Lower, Upper float64
+	value        record
+}
+
+func (tb *typedTestBounds) Limits() (float64, float64) {
+	return tb.Lower, tb.Upper
+}
+
+func (tb *typedTestBounds) Value() record {
+	return tb.value
+}
+
+func Test_Tree_Generic(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := []intree.ValuedBoundsG[record]{}
+		for i, b := range exampleBounds() {
+			lowerLimit, upperLimit := b.Limits()
+			inputBounds = append(inputBounds, &typedTestBounds{
+				Lower: lowerLimit,
+				Upper: upperLimit,
+				value: record{ID: i + 1, Name: string(rune('a' + i))},
+			})
+		}
+
+		tree := intree.NewINTreeVG(inputBounds)
+
+		values := tree.IncludingValues(4.3)
+		assert.ElementsMatch(t, []record{{1, "a"}, {3, "c"}, {6, "f"}, {9, "i"}, {11, "k"}}, values)
+
+		// Values are yielded in the same order as the matched indices
+		for i, matchedIndex := range tree.Including(4.3) {
+			assert.EqualValues(t, matchedIndex+1, values[i].ID)
+		}
+	})
+	t.Run("Case_Typed", func(t *testing.T) {
+		var tree *intree.INTreeV[uint32] = intree.NewINTreeVTyped([]intree.ValuedBoundsG[uint32]{
+			scalarTestBounds{lower: 0.0, upper: 2.0, value: 7},
+		})
+
+		assert.EqualValues(t, []uint32{7}, tree.IncludingValues(1.5))
+	})
+	t.Run("Case_Scalar", func(t *testing.T) {
+		tree := intree.NewINTreeVG([]intree.ValuedBoundsG[uint32]{
+			scalarTestBounds{lower: 0.0, upper: 2.0, value: 7},
+			scalarTestBounds{lower: 1.0, upper: 3.0, value: 9},
+		})
+
+		assert.ElementsMatch(t, []uint32{7, 9}, tree.IncludingValues(1.5))
+		assert.EqualValues(t, []uint32{9}, tree.IncludingValues(2.5))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTreeVG[record](nil)
+
+		values := tree.IncludingValues(4.3)
+		assert.NotNil(t, values)
+		assert.EqualValues(t, 0, len(values))
+		assert.EqualValues(t, 0, len(tree.Including(4.3)))
+	})
+}
+
+type scalarTestBounds struct {
+	lower, upper float64
+	value        uint32
+}
+
+func (sb scalarTestBounds) Limits() (float64, float64) {
+	return sb.lower, sb.upper
+}
+
+func (sb scalarTestBounds) Value() uint32 {
+	return sb.value
+}
@@ -0,0 +1,308 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package genomics provides genomic feature loaders over intree, parsing BED and GFF files into features and
+// indexing them with one tree per chromosome. Features use 0-based, half open coordinates, as BED does:
+// GFF features, which are 1-based and closed, are converted on load.
+package genomics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lggomez/intree"
+)
+
+// ErrInvalidRecord is returned by the loaders when a line is not a valid BED or GFF record.
+var ErrInvalidRecord = errors.New("genomics: invalid record")
+
+// Feature is a genomic feature spanning the [Start, End) positions of a chromosome.
+type Feature struct {
+	Chrom      string
+	Start, End int
+	// Name is the BED name column, or the GFF Name attribute (or ID, if it has no name)
+	Name string
+	// Strand is "+", "-" or "." (unknown or unstranded), and empty when not given
+	Strand string
+}
+
+// Limits accesses the feature limits.
+func (f Feature) Limits() (float64, float64) {
+	return float64(f.Start), float64(f.End)
+}
+
+// ParseBED reads the features of a BED file, in line order. Only the chrom, chromStart, chromEnd, name and
+// strand columns are kept; empty, comment, track and browser lines are skipped.
+// Returns an ErrInvalidRecord wrapped error describing the first invalid line, if any.
+func ParseBED(r io.Reader) ([]Feature, error) {
+	return parse(r, func(line string) (Feature, bool, error) {
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "track") ||
+			strings.HasPrefix(line, "browser") {
+			return Feature{}, false, nil
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return Feature{}, false, fmt.Errorf("expected at least 3 fields, got %d", len(fields))
+		}
+
+		f := Feature{Chrom: fields[0]}
+
+		var err error
+		if f.Start, f.End, err = parseSpan(fields[1], fields[2]); err != nil {
+			return Feature{}, false, err
+		}
+
+		if len(fields) > 3 {
+			f.Name = fields[3]
+		}
+
+		if len(fields) > 5 {
+			f.Strand = fields[5]
+		}
+
+		return f, true, nil
+	})
+}
+
+// ParseGFF reads the features of a GFF (version 2 or 3) file, in line order, converting their 1-based closed
+// coordinates to 0-based half open ones. Empty and comment lines are skipped, and reading stops at a
+// ##FASTA directive. Returns an ErrInvalidRecord wrapped error describing the first invalid line, if any.
+func ParseGFF(r io.Reader) ([]Feature, error) {
+	fasta := false
+
+	return parse(r, func(line string) (Feature, bool, error) {
+		if fasta || line == "##FASTA" {
+			fasta = true
+			return Feature{}, false, nil
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			return Feature{}, false, nil
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 8 {
+			return Feature{}, false, fmt.Errorf("expected at least 8 tab separated fields, got %d", len(fields))
+		}
+
+		f := Feature{Chrom: fields[0], Strand: fields[6]}
+
+		var err error
+		if f.Start, f.End, err = parseSpan(fields[3], fields[4]); err != nil {
+			return Feature{}, false, err
+		}
+
+		if f.Start == 0 {
+			return Feature{}, false, errors.New("start position 0 is not 1-based")
+		}
+
+		f.Start--
+
+		if len(fields) > 8 {
+			f.Name = gffName(fields[8])
+		}
+
+		return f, true, nil
+	})
+}
+
+// parse is an internal utility function, reading the features of the given reader line by line with the given
+// line parser, which reports whether the line holds a feature.
+func parse(r io.Reader, parseLine func(line string) (Feature, bool, error)) ([]Feature, error) {
+	features := []Feature{}
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		f, ok, err := parseLine(strings.TrimRight(scanner.Text(), "\r"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidRecord, n, err)
+		}
+
+		if ok {
+			features = append(features, f)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// parseSpan is an internal utility function, parsing the start and end positions of a feature.
+func parseSpan(start, end string) (int, int, error) {
+	s, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	e, err := strconv.Atoi(end)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if s < 0 || s > e {
+		return 0, 0, fmt.Errorf("invalid span %d-%d", s, e)
+	}
+
+	return s, e, nil
+}
+
+// gffName is an internal utility function, extracting the Name (or ID) attribute of a GFF3 attributes column
+// or the quoted gene_id of a GFF2 one.
+func gffName(attributes string) string {
+	id := ""
+
+	for _, attr := range strings.Split(attributes, ";") {
+		attr = strings.TrimSpace(attr)
+
+		if key, value, ok := strings.Cut(attr, "="); ok {
+			switch key {
+			case "Name":
+				return value
+			case "ID":
+				id = value
+			}
+
+			continue
+		}
+
+		if key, value, ok := strings.Cut(attr, " "); ok && key == "gene_id" && id == "" {
+			id = strings.Trim(value, `"`)
+		}
+	}
+
+	return id
+}
+
+// Index is a per chromosome feature index, holding one tree per chromosome.
+// Returns indices to the initial features array.
+type Index struct {
+	features []Feature
+	chroms   map[string]chromTree
+}
+
+// chromTree holds the tree of a chromosome, and the initial indices of its features.
+type chromTree struct {
+	tree    *intree.INTree
+	indices []int
+}
+
+// NewIndex is the main initialization function;
+// creates the index from the given Slice of features, building one tree per chromosome.
+func NewIndex(features []Feature) *Index {
+	byChrom := map[string][]int{}
+
+	for i, f := range features {
+		byChrom[f.Chrom] = append(byChrom[f.Chrom], i)
+	}
+
+	idx := &Index{features: features, chroms: make(map[string]chromTree, len(byChrom))}
+
+	for chrom, indices := range byChrom {
+		bounds := make([]intree.Bounds, len(indices))
+		for i, fi := range indices {
+			bounds[i] = features[fi]
+		}
+
+		idx.chroms[chrom] = chromTree{tree: intree.NewINTree(bounds, intree.WithEndpoints(intree.UpperOpen)), indices: indices}
+	}
+
+	return idx
+}
+
+// LoadBED is the BED initialization function; parses the given BED file and indexes its features.
+func LoadBED(r io.Reader) (*Index, error) {
+	features, err := ParseBED(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIndex(features), nil
+}
+
+// LoadGFF is the GFF initialization function; parses the given GFF file and indexes its features.
+func LoadGFF(r io.Reader) (*Index, error) {
+	features, err := ParseGFF(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIndex(features), nil
+}
+
+// Len returns the number of features stored in the index.
+func (idx *Index) Len() int {
+	return len(idx.features)
+}
+
+// Feature returns the feature at the given index.
+func (idx *Index) Feature(i int) Feature {
+	return idx.features[i]
+}
+
+// Chromosomes returns the sorted names of the indexed chromosomes.
+func (idx *Index) Chromosomes() []string {
+	return slices.Sorted(maps.Keys(idx.chroms))
+}
+
+// Query collects the ascending indices of the features of the given chromosome that include the given
+// 0-based position.
+func (idx *Index) Query(chrom string, pos int) []int {
+	ct, ok := idx.chroms[chrom]
+	if !ok {
+		return []int{}
+	}
+
+	return ct.collect(ct.tree.Including(float64(pos)))
+}
+
+// QueryRange collects the ascending indices of the features of the given chromosome that overlap the given
+// 0-based, half open [start, end) span.
+func (idx *Index) QueryRange(chrom string, start, end int) []int {
+	ct, ok := idx.chroms[chrom]
+	if !ok || start >= end {
+		return []int{}
+	}
+
+	// Features are half open as well, so the span overlaps them up to its last position
+	return ct.collect(ct.tree.Overlapping(float64(start), float64(end-1)))
+}
+
+// collect is an internal utility function, mapping the given chromosome tree matches to sorted feature indices.
+func (ct chromTree) collect(matches []int) []int {
+	for i, m := range matches {
+		matches[i] = ct.indices[m]
+	}
+
+	slices.Sort(matches)
+
+	return matches
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package genomics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lggomez/intree/genomics"
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleBED = `browser position chr1:1-1000
+track name=genes
+# comment
+chr1	100	200	geneA	0	+
+chr1	150	300	geneB	0	-
+chr2	100	200	geneC
+chr1	300	400
+`
+
+const exampleGFF = `##gff-version 3
+chr1	src	gene	101	200	.	+	.	ID=gene1;Name=geneA
+chr1	src	gene	151	300	.	-	.	ID=gene2
+chr2	src	exon	101	200	.	.	.	gene_id "geneC"; transcript_id "t1"
+##FASTA
+>chr1
+ACGT
+`
+
+func Test_Index(t *testing.T) {
+	t.Run("Case_BED", func(t *testing.T) {
+		idx, err := genomics.LoadBED(strings.NewReader(exampleBED))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 4, idx.Len())
+		assert.EqualValues(t, []string{"chr1", "chr2"}, idx.Chromosomes())
+		assert.EqualValues(t, genomics.Feature{Chrom: "chr1", Start: 100, End: 200, Name: "geneA", Strand: "+"}, idx.Feature(0))
+		assert.EqualValues(t, genomics.Feature{Chrom: "chr1", Start: 300, End: 400}, idx.Feature(3))
+
+		assert.EqualValues(t, []int{0}, idx.Query("chr1", 100))
+		assert.EqualValues(t, []int{0, 1}, idx.Query("chr1", 150))
+		assert.EqualValues(t, []int{1}, idx.Query("chr1", 200)) // BED ends are exclusive
+		assert.EqualValues(t, []int{3}, idx.Query("chr1", 300))
+		assert.EqualValues(t, []int{2}, idx.Query("chr2", 199))
+		assert.Empty(t, idx.Query("chr2", 200))
+		assert.Empty(t, idx.Query("chrX", 150))
+
+		assert.EqualValues(t, []int{0, 1}, idx.QueryRange("chr1", 0, 151))
+		assert.EqualValues(t, []int{1}, idx.QueryRange("chr1", 200, 300))
+		assert.Empty(t, idx.QueryRange("chr1", 400, 500))
+		assert.Empty(t, idx.QueryRange("chr1", 150, 150))
+	})
+	t.Run("Case_GFF", func(t *testing.T) {
+		features, err := genomics.ParseGFF(strings.NewReader(exampleGFF))
+		assert.NoError(t, err)
+		assert.EqualValues(t, []genomics.Feature{
+			{Chrom: "chr1", Start: 100, End: 200, Name: "geneA", Strand: "+"},
+			{Chrom: "chr1", Start: 150, End: 300, Name: "gene2", Strand: "-"},
+			{Chrom: "chr2", Start: 100, End: 200, Name: "geneC", Strand: "."},
+		}, features)
+
+		idx := genomics.NewIndex(features)
+		// GFF position 200 is the 0-based position 199
+		assert.EqualValues(t, []int{0, 1}, idx.Query("chr1", 199))
+		assert.EqualValues(t, []int{1}, idx.Query("chr1", 200))
+	})
+	t.Run("Case_Border/invalid_records", func(t *testing.T) {
+		for _, input := range []string{
+			"chr1\t100\n",
+			"chr1\tx\t200\n",
+			"chr1\t300\t200\n",
+			"chr1\t-1\t200\n",
+		} {
+			_, err := genomics.ParseBED(strings.NewReader(input))
+			assert.ErrorIs(t, err, genomics.ErrInvalidRecord, "input %q", input)
+		}
+
+		for _, input := range []string{
+			"chr1\tsrc\tgene\t101\t200\n",
+			"chr1\tsrc\tgene\t0\t200\t.\t+\t.\n",
+			"chr1 src gene 101 200 . + .\n",
+		} {
+			_, err := genomics.LoadGFF(strings.NewReader(input))
+			assert.ErrorIs(t, err, genomics.ErrInvalidRecord, "input %q", input)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+// Index is the search interface shared by the interval index backends, so that callers can pick the structure
+// best suited to each dataset through WithBackend and benchmark them uniformly. Searches return indices
+// to the bounds given to Build.
+type Index interface {
+	// Build replaces the index contents with the given bounds, keeping its endpoints.
+	Build(bounds []Bounds)
+	// Including collects intervals that overlap with the given value.
+	Including(val float64) []int
+	// Intersecting collects intervals that overlap with the given range, boundaries included.
+	Intersecting(lo, hi float64) []int
+}
+
+var (
+	_ Index = (*INTree)(nil)
+	_ Index = (*NCList)(nil)
+	_ Index = (*SortedList)(nil)
+	_ Index = (*LinearScan)(nil)
+)
+
+// Backend defines the structure NewIndex builds.
+type Backend uint8
+
+const (
+	// AugmentedTree builds an INTree. This is the default.
+	AugmentedTree Backend = iota
+	// NestedContainment builds an NCList, suited to datasets of deeply nested intervals.
+	NestedContainment
+	// SortedEndpoints builds a SortedList, suited to datasets of short intervals.
+	SortedEndpoints
+	// BruteForce builds a LinearScan, suited to small datasets.
+	BruteForce
+)
+
+// String returns the name of the backend.
+func (b Backend) String() string {
+	switch b {
+	case AugmentedTree:
+		return "AugmentedTree"
+	case NestedContainment:
+		return "NestedContainment"
+	case SortedEndpoints:
+		return "SortedEndpoints"
+	case BruteForce:
+		return "BruteForce"
+	}
+
+	return "invalid"
+}
+
+// NewIndex is the backend agnostic initialization function;
+// creates the Index of the backend set by WithBackend, an INTree by default, from the given Slice of Bounds.
+// Options other than the endpoints only apply to the INTree backend.
+func NewIndex(bounds []Bounds, opts ...Option) Index {
+	switch newOptions(opts).backend {
+	case NestedContainment:
+		return NewNCList(bounds, opts...)
+	case SortedEndpoints:
+		return NewSortedList(bounds, opts...)
+	case BruteForce:
+		return NewLinearScan(bounds, opts...)
+	}
+
+	return NewINTree(bounds, opts...)
+}
+
+// Build replaces the tree contents with a tree created from the given bounds, discarding any value, staged interval,
+// removed interval, retained bounds, metadata and priorities. The endpoints, node layout, compaction threshold and
+// trace hook are kept.
+func (t *INTree) Build(bounds []Bounds) {
+	opts := []Option{
+		WithEndpoints(t.endpoints),
+		WithCompactionThreshold(t.compactionThreshold),
+		WithTraceHook(t.traceHook),
+	}
+
+	if t.layout != nil {
+		opts = append(opts, WithEytzingerLayout())
+	}
+
+	*t = *NewINTree(bounds, opts...)
+}
+
+// Build replaces the list contents with a list created from the given bounds, keeping its endpoints.
+func (l *NCList) Build(bounds []Bounds) {
+	*l = *NewNCList(bounds, WithEndpoints(l.endpoints))
+}
@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+var backends = []intree.Backend{
+	intree.AugmentedTree,
+	intree.NestedContainment,
+	intree.SortedEndpoints,
+	intree.BruteForce,
+}
+
+func Test_Index(t *testing.T) {
+	t.Run("Case_Backends", func(t *testing.T) {
+		assert.IsType(t, &intree.INTree{}, intree.NewIndex(nil))
+		assert.IsType(t, &intree.NCList{}, intree.NewIndex(nil, intree.WithBackend(intree.NestedContainment)))
+		assert.IsType(t, &intree.SortedList{}, intree.NewIndex(nil, intree.WithBackend(intree.SortedEndpoints)))
+		assert.IsType(t, &intree.LinearScan{}, intree.NewIndex(nil, intree.WithBackend(intree.BruteForce)))
+		assert.EqualValues(t, "SortedEndpoints", intree.SortedEndpoints.String())
+		assert.EqualValues(t, "invalid", intree.Backend(42).String())
+	})
+	t.Run("Case_Reference", func(t *testing.T) {
+		for _, bounds := range [][]intree.Bounds{randomBounds(2000, 500.0, 30.0), nestedBounds(2000, 20, 500.0)} {
+			reference := intree.NewINTree(bounds)
+
+			for _, b := range backends {
+				index := intree.NewIndex(bounds, intree.WithBackend(b))
+
+				for val := -10.0; val <= 510.0; val += 1.25 {
+					assert.ElementsMatch(t, reference.Including(val), index.Including(val), "%v at %.2f", b, val)
+				}
+
+				for lower := -10.0; lower <= 510.0; lower += 17.0 {
+					assert.ElementsMatch(t, reference.Intersecting(lower, lower+9.0), index.Intersecting(lower, lower+9.0), "%v at %.1f", b, lower)
+				}
+
+				assert.Empty(t, index.Intersecting(5.0, 4.0), b)
+			}
+		}
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		bounds := []intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 4.0},
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 3.0},
+		}
+
+		for _, e := range []intree.Endpoints{intree.Closed, intree.LowerOpen, intree.UpperOpen, intree.Open} {
+			reference := intree.NewINTree(bounds, intree.WithEndpoints(e))
+
+			for _, b := range backends {
+				index := intree.NewIndex(bounds, intree.WithBackend(b), intree.WithEndpoints(e))
+
+				for _, val := range []float64{0.0, 1.0, 2.0, 3.0, 4.0} {
+					assert.ElementsMatch(t, reference.Including(val), index.Including(val), "%v %v at %.1f", b, e, val)
+				}
+			}
+		}
+	})
+	t.Run("Case_Build", func(t *testing.T) {
+		first := []intree.Bounds{intree.Interval{Lower: 0.0, Upper: 1.0}}
+		second := []intree.Bounds{
+			intree.Interval{Lower: 5.0, Upper: 6.0},
+			intree.Interval{Lower: 4.0, Upper: 5.0},
+		}
+
+		for _, b := range backends {
+			index := intree.NewIndex(first, intree.WithBackend(b), intree.WithEndpoints(intree.UpperOpen))
+			index.Build(second)
+
+			assert.Empty(t, index.Including(0.5), b)
+			assert.ElementsMatch(t, []int{0}, index.Including(5.0), b)
+			assert.ElementsMatch(t, []int{0, 1}, index.Intersecting(4.5, 5.5), b)
+		}
+	})
+}
+
+func Benchmark_Index(b *testing.B) {
+	for _, n := range []int{64, 100000} {
+		bounds := randomBounds(n, 10000.0, 50.0)
+
+		for _, backend := range backends {
+			index := intree.NewIndex(bounds, intree.WithBackend(backend))
+
+			b.Run(fmt.Sprintf("%v/%d", backend, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					index.Including(float64(i % 10000))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "time"
+
+// Metrics receives the observations of an Instrumented tree, as implemented by adapters to metrics libraries:
+// query latency and match count distributions (as histograms), build duration and tree size (as a gauge).
+// Implementations must be safe for concurrent use if the tree is searched concurrently.
+type Metrics interface {
+	// ObserveQuery is called after every search with its name (such as "Including"), latency and match count
+	ObserveQuery(query string, latency time.Duration, matches int)
+	// ObserveBuild is called once the tree is built by BuildInstrumented, with the build duration
+	ObserveBuild(duration time.Duration)
+	// ObserveSize is called with the number of intervals not removed every time it is set or changes
+	ObserveSize(size int)
+}
+
+// Instrumented is an INTree wrapper reporting the latency and match count of every search, build duration and
+// tree size to the given Metrics, so that services embedding the tree get observability without wrapping
+// every call themselves.
+type Instrumented struct {
+	tree    *INTree
+	metrics Metrics
+}
+
+// NewInstrumented wraps the given tree, which must not be updated directly afterwards so that its reported size
+// stays current. A nil tree is replaced by an empty one.
+func NewInstrumented(t *INTree, metrics Metrics) *Instrumented {
+	if t == nil {
+		t = NewINTree(nil)
+	}
+
+	metrics.ObserveSize(t.Len() - t.Removed())
+
+	return &Instrumented{tree: t, metrics: metrics}
+}
+
+// BuildInstrumented creates the tree from the given Slice of Bounds as NewINTree does, reporting its build duration.
+func BuildInstrumented(bounds []Bounds, metrics Metrics, opts ...Option) *Instrumented {
+	start := time.Now()
+	t := NewINTree(bounds, opts...)
+	metrics.ObserveBuild(time.Since(start))
+
+	return NewInstrumented(t, metrics)
+}
+
+// Tree returns the wrapped tree, for searches that are not instrumented.
+func (i *Instrumented) Tree() *INTree {
+	return i.tree
+}
+
+// Len returns the number of intervals stored in the tree.
+func (i *Instrumented) Len() int {
+	return i.tree.Len()
+}
+
+// Including collects the intervals that contain the given value, as INTree.Including does.
+func (i *Instrumented) Including(val float64) []int {
+	start := time.Now()
+	result := i.tree.Including(val)
+	i.metrics.ObserveQuery("Including", time.Since(start), len(result))
+
+	return result
+}
+
+// Overlapping collects the intervals that overlap with the given range, as INTree.Overlapping does.
+func (i *Instrumented) Overlapping(lower, upper float64) []int {
+	start := time.Now()
+	result := i.tree.Overlapping(lower, upper)
+	i.metrics.ObserveQuery("Overlapping", time.Since(start), len(result))
+
+	return result
+}
+
+// Covering collects the intervals that fully contain the given range, as INTree.Covering does.
+func (i *Instrumented) Covering(lo, hi float64) []int {
+	start := time.Now()
+	result := i.tree.Covering(lo, hi)
+	i.metrics.ObserveQuery("Covering", time.Since(start), len(result))
+
+	return result
+}
+
+// CountIncluding counts the intervals that contain the given value, as INTree.CountIncluding does.
+func (i *Instrumented) CountIncluding(val float64) int {
+	start := time.Now()
+	count := i.tree.CountIncluding(val)
+	i.metrics.ObserveQuery("CountIncluding", time.Since(start), count)
+
+	return count
+}
+
+// Insert adds the given interval to the tree as INTree.Insert does, reporting the new tree size.
+// Returns the original index assigned to it.
+func (i *Instrumented) Insert(b Bounds) int {
+	index := i.tree.Insert(b)
+	i.metrics.ObserveSize(i.tree.Len() - i.tree.Removed())
+
+	return index
+}
+
+// Delete removes the interval at the given original index as INTree.Delete does, reporting the new tree size.
+func (i *Instrumented) Delete(index int) error {
+	if err := i.tree.Delete(index); err != nil {
+		return err
+	}
+
+	i.metrics.ObserveSize(i.tree.Len() - i.tree.Removed())
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedQuery is a query observation of testMetrics.
+type recordedQuery struct {
+	query   string
+	matches int
+}
+
+// testMetrics is an intree.Metrics implementation recording every observation.
+type testMetrics struct {
+	queries []recordedQuery
+	builds  int
+	sizes   []int
+}
+
+func (m *testMetrics) ObserveQuery(query string, latency time.Duration, matches int) {
+	m.queries = append(m.queries, recordedQuery{query: query, matches: matches})
+}
+
+func (m *testMetrics) ObserveBuild(duration time.Duration) {
+	m.builds++
+}
+
+func (m *testMetrics) ObserveSize(size int) {
+	m.sizes = append(m.sizes, size)
+}
+
+func Test_Instrumented(t *testing.T) {
+	t.Run("Case_Observations", func(t *testing.T) {
+		metrics := &testMetrics{}
+		tree := intree.BuildInstrumented(exampleBounds(), metrics)
+		reference := intree.NewINTree(exampleBounds())
+
+		assert.EqualValues(t, 1, metrics.builds)
+		assert.EqualValues(t, []int{len(exampleBounds())}, metrics.sizes)
+
+		assert.ElementsMatch(t, reference.Including(4.3), tree.Including(4.3))
+		assert.ElementsMatch(t, reference.Overlapping(4.0, 6.0), tree.Overlapping(4.0, 6.0))
+		assert.ElementsMatch(t, reference.Covering(4.0, 6.0), tree.Covering(4.0, 6.0))
+		assert.EqualValues(t, reference.CountIncluding(-1.0), tree.CountIncluding(-1.0))
+
+		assert.EqualValues(t, []recordedQuery{
+			{query: "Including", matches: len(reference.Including(4.3))},
+			{query: "Overlapping", matches: len(reference.Overlapping(4.0, 6.0))},
+			{query: "Covering", matches: len(reference.Covering(4.0, 6.0))},
+			{query: "CountIncluding", matches: 0},
+		}, metrics.queries)
+	})
+	t.Run("Case_Updates", func(t *testing.T) {
+		metrics := &testMetrics{}
+		tree := intree.NewInstrumented(intree.NewINTree(exampleBounds()), metrics)
+		n := tree.Len()
+
+		assert.EqualValues(t, n, tree.Insert(intree.Interval{Lower: 1.0, Upper: 2.0}))
+		assert.NoError(t, tree.Delete(0))
+		assert.ErrorIs(t, tree.Delete(-1), intree.ErrIndexOutOfRange)
+
+		assert.EqualValues(t, 0, metrics.builds)
+		assert.EqualValues(t, []int{n, n + 1, n}, metrics.sizes)
+		assert.EqualValues(t, n+1, tree.Tree().Len())
+		assert.EqualValues(t, 1, tree.Tree().Removed())
+	})
+	t.Run("Case_Border/nil_tree", func(t *testing.T) {
+		metrics := &testMetrics{}
+		tree := intree.NewInstrumented(nil, metrics)
+
+		assert.Empty(t, tree.Including(1.0))
+		assert.EqualValues(t, []int{0}, metrics.sizes)
+	})
+}
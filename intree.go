@@ -22,13 +22,25 @@
 //
 // Changelog: 	* Improve package code readability, add comments
 //				* Add ValuedBounds interface and compatibility builder
+//				* Store ValuedBounds values, add IncludingValues
+//				* Add injectable pivot Source for deterministic builds
+//				* Fix augmented limits of subtrees with negative upper limits
+//				* Augment nodes bottom-up in linear time
+//				* Replace Random Pivot QuickSort with an introspective sort
+//				* Reduce branches and bounds checks in the search hot path
+//				* Add magic, byte order flag and validation to the binary encoding
+//				* Delete through tombstones: indices stay stable and Len counts deleted intervals until Compact
 
 // Package intree provides a very fast, static, flat, augmented interval tree for reverse range searches.
 package intree
 
 import (
+	"cmp"
 	"math"
+	"math/bits"
 	"math/rand"
+	fastrand "math/rand/v2"
+	"slices"
 )
 
 // Bounds is the main interface expected by NewINTree(); requires Limits method to access interval limits.
@@ -44,161 +56,561 @@ type ValuedBounds interface {
 }
 
 // INTree is the main package object;
-// holds Slice of reference indices and the respective interval limits.
+// holds Slice of reference indices and the respective interval limits,
+// plus the associated values when built from ValuedBounds and the bounds themselves when retained.
 type INTree struct {
-	indexes []int
-	limits  []float64
+	indexes   []int
+	limits    []float64
+	positions []int
+	values    []interface{}
+	bounds    []Bounds
+	staged    []Bounds
+	// buffered counts the trailing nodes appended by Insert and not merged into the sorted ones yet
+	buffered  int
+	unordered bool
+	readOnly  bool
+	endpoints Endpoints
+	layout    *eytzinger
+	// tombstones flags the intervals removed by Remove, by original index, until compacted
+	tombstones          []bool
+	removed             int
+	compactionThreshold float64
+	// metadata holds the annotations of the intervals, by original index
+	metadata map[int]any
+	// priorities holds the priorities of the intervals by original index, and priorityMax the greatest
+	// priority of each node subtree by position
+	priorities  []int
+	priorityMax []int
+	traceHook   func(ev QueryEvent)
+	// view holds the nodes of unordered trees sorted by lower limit, for the operations relying on that order
+	view *orderedView
 }
 
 // NewINTree is the main initialization function;
 // creates the tree from the given Slice of Bounds.
-func NewINTree(bounds []Bounds) *INTree {
-	tree := INTree{}
-	tree.buildTree(bounds)
-
-	return &tree
+func NewINTree(bounds []Bounds, opts ...Option) *INTree {
+	return NewINTreeWithSource(bounds, runtimeSource{}, opts...)
 }
 
 // NewINTreeV is the main initialization function;
 // creates the tree from the given Slice of ValuedBounds.
-func NewINTreeV(bounds []ValuedBounds) *INTree {
-	tree := INTree{}
-	tree.buildTreeV(bounds)
+func NewINTreeV(bounds []ValuedBounds, opts ...Option) *INTree {
+	return NewINTreeVWithSource(bounds, runtimeSource{}, opts...)
+}
 
-	return &tree
+// NewINTreeWithSource is the deterministic initialization function;
+// creates the tree from the given Slice of Bounds, picking sort pivots from the given Source.
+// Given the same seeded Source and input, the resulting tree layout is always the same.
+// A nil Source picks pivots by median of three, as WithDeterministicSort.
+func NewINTreeWithSource(bounds []Bounds, src rand.Source, opts ...Option) *INTree {
+	o := newOptions(opts)
+	indexes, limits := make([]int, len(bounds)), make([]float64, 3*len(bounds))
+
+	for i, b := range bounds {
+		indexes[i] = i
+		limits[3*i], limits[3*i+1] = o.limits(b)
+	}
+
+	var retained []Bounds
+	if o.retainBounds {
+		retained = append(make([]Bounds, 0, len(bounds)), bounds...)
+	}
+
+	return build(indexes, limits, nil, retained, o.pivots(src), o)
 }
 
-// buildTree is the internal tree construction function;
-// creates, sorts and augments nodes into Slices.
-func (t *INTree) buildTree(bounds []Bounds) {
-	t.indexes = make([]int, len(bounds))
-	t.limits = make([]float64, 3*len(bounds))
+// NewINTreeVWithSource is the deterministic initialization function;
+// creates the tree from the given Slice of ValuedBounds, picking sort pivots from the given Source.
+// Given the same seeded Source and input, the resulting tree layout is always the same.
+// A nil Source picks pivots by median of three, as WithDeterministicSort.
+func NewINTreeVWithSource(bounds []ValuedBounds, src rand.Source, opts ...Option) *INTree {
+	o := newOptions(opts)
+	indexes, limits := make([]int, len(bounds)), make([]float64, 3*len(bounds))
+	values := make([]interface{}, len(bounds))
+
+	var retained []Bounds
+	if o.retainBounds {
+		retained = make([]Bounds, len(bounds))
+	}
 
-	for i, v := range bounds {
-		t.indexes[i] = i
-		l, u := v.Limits()
+	for i, b := range bounds {
+		indexes[i] = i
+		limits[3*i], limits[3*i+1] = o.limits(b)
+
+		if b != nil {
+			values[i] = b.Value()
+		}
 
-		t.limits[3*i] = l
-		t.limits[3*i+1] = u
-		t.limits[3*i+2] = 0
+		if retained != nil {
+			retained[i] = b
+		}
 	}
 
-	sort(t.limits, t.indexes)
-	augment(t.limits, t.indexes)
+	return build(indexes, limits, values, retained, o.pivots(src), o)
 }
 
-// buildTreeV is the internal tree construction function for ValuedBounds;
-// creates, sorts and augments nodes into Slices.
-func (t *INTree) buildTreeV(bounds []ValuedBounds) {
-	t.indexes = make([]int, len(bounds))
-	t.limits = make([]float64, 3*len(bounds))
+// build is the internal tree construction function shared by the initialization functions;
+// sorts and augments the given nodes, with their limits laid out by original index, and applies the options.
+// Values and retained bounds are indexed by original index, and are nil if unused.
+func build(indexes []int, limits []float64, values []interface{}, bounds []Bounds, rnd *rand.Rand, o options) *INTree {
+	var tombstones []bool
+
+	removed := 0
+
+	if o.validation {
+		for i := range indexes {
+			if checkLimits(i, limits[3*i], limits[3*i+1]) == nil {
+				continue
+			}
+
+			if tombstones == nil {
+				tombstones = make([]bool, len(indexes))
+			}
 
-	for i, v := range bounds {
-		t.indexes[i] = i
-		l, u := v.Limits()
+			// Invalid intervals sort last and match no search, as removed ones
+			tombstones[i] = true
+			removed++
+			limits[3*i], limits[3*i+1] = math.Inf(1), math.Inf(-1)
+		}
+	}
 
-		t.limits[3*i] = l
-		t.limits[3*i+1] = u
-		t.limits[3*i+2] = 0
+	sort(limits, indexes, rnd)
+	augment(limits, indexes)
+
+	tree := INTree{
+		indexes:             indexes,
+		limits:              limits,
+		positions:           mapPositions(indexes),
+		values:              values,
+		bounds:              bounds,
+		endpoints:           o.endpoints,
+		tombstones:          tombstones,
+		removed:             removed,
+		compactionThreshold: o.compactionThreshold,
+		metadata:            newMetadata(o.metadata, len(indexes)),
+		traceHook:           o.traceHook,
 	}
 
-	sort(t.limits, t.indexes)
-	augment(t.limits, t.indexes)
+	tree.setPriorities(o.priorities)
+	tree.reserve(o.capacityHint)
+
+	if o.eytzingerLayout {
+		tree.layout = newEytzinger(tree.limits)
+	}
+
+	return &tree
+}
+
+// reserve is an internal utility function, growing the capacity of the node Slices (and values and
+// retained bounds) to fit the given number of intervals, so that updates append without reallocating.
+func (t *INTree) reserve(n int) {
+	extra := n - len(t.indexes)
+	if extra <= 0 {
+		return
+	}
+
+	t.indexes = slices.Grow(t.indexes, extra)
+	t.limits = slices.Grow(t.limits, 3*extra)
+
+	if t.values != nil {
+		t.values = slices.Grow(t.values, extra)
+	}
+
+	if t.bounds != nil {
+		t.bounds = slices.Grow(t.bounds, extra)
+	}
+}
+
+// runtimeSource is the default pivot Source, drawing from the runtime generator of math/rand/v2:
+// it holds no state, so builds share it without allocating a Source or contending on a lock.
+type runtimeSource struct{}
+
+func (runtimeSource) Int63() int64 {
+	return int64(fastrand.Uint64() >> 1)
+}
+
+func (runtimeSource) Uint64() uint64 {
+	return fastrand.Uint64()
 }
 
+func (runtimeSource) Seed(int64) {}
+
 // Including is the main entry point for bounds searches;
 // traverses the tree and collects intervals that overlap with the given value.
 func (t *INTree) Including(val float64) []int {
-	idxStock := []int{0, len(t.indexes) - 1}
 	result := []int{}
 
-	for len(idxStock) > 0 {
-		// Retrieve right and left boundaries from index stock
-		rBoundIdx := idxStock[len(idxStock)-1]
-		idxStock = idxStock[:len(idxStock)-1]
-		lBoundIdx := idxStock[len(idxStock)-1]
-		idxStock = idxStock[:len(idxStock)-1]
+	t.traverse(val, val, func(pos int) bool {
+		result = append(result, t.indexes[pos])
+		return true
+	})
 
-		if lBoundIdx == rBoundIdx+1 {
-			continue
-		}
+	return result
+}
 
-		centerIdx := int(math.Ceil(float64(lBoundIdx+rBoundIdx) / 2.0))
-		lowerLimit := t.limits[3*centerIdx+2]
+// stockSize is the capacity of the fixed size traversal index stock, kept on the stack so that searches do not
+// allocate; as the tree is balanced, each of its at most 64 levels leaves one pending subtree, so it never grows.
+const stockSize = 2 * 66
 
-		if val <= lowerLimit {
-			idxStock = append(idxStock, lBoundIdx, centerIdx-1)
+// traverse is the internal tree search function;
+// calls fn with the position of every node overlapping with the given range according to the tree endpoints,
+// stopping as soon as fn returns false.
+func (t *INTree) traverse(lower, upper float64, fn func(pos int) bool) {
+	lower, upper = t.searchRange(lower, upper)
+	t.traverseClosed(lower, upper, fn)
+}
+
+// traverseClosed is the internal closed intervals search function;
+// calls fn with the position of every node overlapping with the given range, limits included,
+// stopping as soon as fn returns false. Falls back to a full traversal if nodes are not sorted by lower limit,
+// and searches the Eytzinger layout instead of the in-order nodes if the tree has one.
+// Nodes buffered by Insert are scanned first.
+func (t *INTree) traverseClosed(lower, upper float64, fn func(pos int) bool) {
+	if t.traceHook != nil {
+		t.traceClosed(lower, upper, fn)
+		return
+	}
+
+	if t.buffered > 0 && !t.scanBuffered(0, lower, upper, fn) {
+		return
+	}
+
+	if t.unordered {
+		for pos := range t.indexes {
+			if t.limits[3*pos] <= upper && lower <= t.limits[3*pos+1] && !fn(pos) {
+				return
+			}
 		}
 
-		l := t.limits[3*centerIdx]
+		return
+	}
+
+	if t.layout != nil {
+		t.layout.traverse(lower, upper, fn)
+		return
+	}
+
+	n := t.treeLen()
+	if n == 0 {
+		return
+	}
+
+	var stock [stockSize]int
+	idxStock := append(stock[:0], 0, n-1)
 
-		if l <= val {
-			idxStock = append(idxStock, centerIdx+1, rBoundIdx)
+	for len(idxStock) > 0 {
+		// Retrieve right and left boundaries from index stock; only non empty ones are pushed
+		n := len(idxStock)
+		lBoundIdx, rBoundIdx := idxStock[n-2], idxStock[n-1]
+		idxStock = idxStock[:n-2]
+
+		centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+		// A single bounds check loads the three limits of the node, which share a cache line
+		node := (*[3]float64)(t.limits[3*centerIdx:])
+
+		if lower <= node[2] && lBoundIdx < centerIdx {
+			idxStock = append(idxStock, lBoundIdx, centerIdx-1)
+		}
 
-			upperLimit := t.limits[3*centerIdx+1]
+		if node[0] <= upper {
+			if centerIdx < rBoundIdx {
+				idxStock = append(idxStock, centerIdx+1, rBoundIdx)
+			}
 
-			if val <= upperLimit {
-				result = append(result, t.indexes[centerIdx])
+			if lower <= node[1] && !fn(centerIdx) {
+				return
 			}
 		}
 	}
+}
+
+// IncludingValues is the valued counterpart of Including;
+// collects the values associated with the intervals that overlap with the given value, in the same order.
+// Returns nil if the tree was not built from ValuedBounds.
+func (t *INTree) IncludingValues(val float64) []interface{} {
+	if t.values == nil {
+		return nil
+	}
+
+	result := []interface{}{}
+
+	t.traverse(val, val, func(pos int) bool {
+		result = append(result, t.values[t.indexes[pos]])
+		return true
+	})
 
 	return result
 }
 
-// augment is an internal utility function, adding maximum value of all child nodes to the current node.
-func augment(limits []float64, indexes []int) {
-	if len(indexes) < 1 {
-		return
+// IncludingBounds is the retained bounds counterpart of Including;
+// collects the bounds of the intervals that overlap with the given value, in the same order.
+// Returns nil if the tree was not built WithRetainedBounds.
+func (t *INTree) IncludingBounds(val float64) []Bounds {
+	if t.bounds == nil {
+		return nil
 	}
 
-	max := 0.0
+	result := []Bounds{}
+
+	t.traverse(val, val, func(pos int) bool {
+		result = append(result, t.bounds[t.indexes[pos]])
+		return true
+	})
+
+	return result
+}
+
+// IncludingValuedBounds is the retained ValuedBounds counterpart of Including;
+// collects the bounds of the intervals that overlap with the given value, in the same order.
+// Returns nil if the tree was not built from ValuedBounds WithRetainedBounds.
+func (t *INTree) IncludingValuedBounds(val float64) []ValuedBounds {
+	if t.bounds == nil || t.values == nil {
+		return nil
+	}
 
-	for idx := range indexes {
-		if limits[3*idx+1] > max {
-			max = limits[3*idx+1]
+	result := []ValuedBounds{}
+
+	t.traverse(val, val, func(pos int) bool {
+		result = append(result, t.bounds[t.indexes[pos]].(ValuedBounds))
+		return true
+	})
+
+	return result
+}
+
+// Len returns the number of intervals stored in the tree.
+func (t *INTree) Len() int {
+	return len(t.indexes)
+}
+
+// Intervals returns the [lower, upper] limits of every interval, indexed by original input order.
+// Intervals removed by Remove are skipped, so that the following ones shift down as they do once compacted.
+func (t *INTree) Intervals() [][2]float64 {
+	result := make([][2]float64, 0, len(t.positions)-t.removed)
+
+	t.liveIntervals(func(_ int, lower, upper float64) {
+		result = append(result, [2]float64{lower, upper})
+	})
+
+	return result
+}
+
+// position is an internal utility function, mapping an original index to its node position in the tree.
+// Returns -1 if the index is not stored in the tree.
+func (t *INTree) position(index int) int {
+	if index < 0 || index >= len(t.positions) {
+		return -1
+	}
+
+	return t.positions[index]
+}
+
+// treeLen is an internal utility function, returning the number of nodes laid out as the implicit tree:
+// every node but the ones buffered by Insert.
+func (t *INTree) treeLen() int {
+	return len(t.indexes) - t.buffered
+}
+
+// searchLower is an internal utility function, returning the first node position whose lower limit
+// is greater than the given value, or the tree length if there is none.
+func (t *INTree) searchLower(val float64) int {
+	l, r := 0, len(t.indexes)
+
+	for l < r {
+		m := int(uint(l+r) >> 1)
+		if t.limits[3*m] <= val {
+			l = m + 1
+		} else {
+			r = m
 		}
 	}
 
+	return l
+}
+
+// mapPositions is an internal utility function, building the reverse mapping from original indices to node positions.
+func mapPositions(indexes []int) []int {
+	positions := make([]int, len(indexes))
+
+	for pos, idx := range indexes {
+		positions[idx] = pos
+	}
+
+	return positions
+}
+
+// augment is an internal utility function, adding maximum value of all child nodes to the current node;
+// works bottom-up in a single pass and returns the maximum value of the given nodes, or false if there is none.
+// NaN upper limits are ignored by maxLimit, leaving NaN as the maximum of subtrees holding nothing else.
+func augment[T cmp.Ordered](limits []T, indexes []int) (T, bool) {
+	if len(indexes) < 1 {
+		var none T
+		return none, false
+	}
+
 	r := len(indexes) >> 1
 
+	max := limits[3*r+1]
+
+	for _, child := range [2][]T{limits[:3*r], limits[3*r+3:]} {
+		if v, ok := augment(child, indexes[:len(child)/3]); ok {
+			max = maxLimit(max, v)
+		}
+	}
+
 	limits[3*r+2] = max
 
-	augment(limits[:3*r], indexes[:r])
-	augment(limits[3*r+3:], indexes[r+1:])
+	return max, true
 }
 
-// sort is an internal utility function, sorting the tree by lowest limits using Random Pivot QuickSearch
-func sort(limits []float64, indexes []int) {
-	if len(indexes) < 2 {
-		return
+// maxLimit is an internal utility function, returning the maximum of the given limits; NaN limits are ignored,
+// so the result is NaN only if every limit is NaN. Shared by every augmented limit computation and check.
+func maxLimit[T cmp.Ordered](limit T, limits ...T) T {
+	for _, v := range limits {
+		if v == v && (limit != limit || v > limit) {
+			limit = v
+		}
+	}
+
+	return limit
+}
+
+// sameLimit is an internal utility function, comparing augmented limits so that NaN matches NaN.
+func sameLimit[T cmp.Ordered](a, b T) bool {
+	return a == b || a != a && b != b
+}
+
+// insertionSortSize is the node count below which sort switches to insertion sort.
+const insertionSortSize = 12
+
+// sort is an internal utility function, sorting the tree by lowest limits using an introspective sort:
+// Random Pivot QuickSort with Hoare partitioning, falling back to HeapSort past a depth limit.
+// Takes O(n log n) time on any input, including many equal limits, and O(log n) stack space.
+// A nil rnd picks pivots by median of three, for deterministic layouts. Shared by INTree and INTreeOf.
+func sort[T cmp.Ordered](limits []T, indexes []int, rnd *rand.Rand) {
+	introSort(limits, indexes, rnd, 2*bits.Len(uint(len(indexes))))
+}
+
+// introSort is an internal utility function, recursing into the smaller partition and looping over the larger one.
+func introSort[T cmp.Ordered](limits []T, indexes []int, rnd *rand.Rand, depth int) {
+	for len(indexes) > insertionSortSize {
+		if depth == 0 {
+			heapSort(limits, indexes)
+			return
+		}
+
+		depth--
+
+		// Pick pivot, moving it to the first node
+		swapNodes(limits, indexes, 0, pivot(limits, len(indexes), rnd))
+		pivotLimit := limits[0]
+
+		// Hoare partition into [0, p) and (p, n); stopping on limits equal to the pivot
+		// splits runs of equal limits evenly
+		i, p := 1, len(indexes)-1
+		for {
+			for i <= p && limits[3*i] < pivotLimit {
+				i++
+			}
+
+			for i <= p && limits[3*p] > pivotLimit {
+				p--
+			}
+
+			if i >= p {
+				break
+			}
+
+			swapNodes(limits, indexes, i, p)
+			i++
+			p--
+		}
+
+		swapNodes(limits, indexes, 0, p)
+
+		if p < len(indexes)-p {
+			introSort(limits[:3*p], indexes[:p], rnd, depth)
+			limits, indexes = limits[3*p+3:], indexes[p+1:]
+		} else {
+			introSort(limits[3*p+3:], indexes[p+1:], rnd, depth)
+			limits, indexes = limits[:3*p], indexes[:p]
+		}
+	}
+
+	insertionSort(limits, indexes)
+}
+
+// pivot is an internal utility function, picking the pivot position among n nodes: at random,
+// or by median of three of the first, middle and last nodes if rnd is nil.
+func pivot[T cmp.Ordered](limits []T, n int, rnd *rand.Rand) int {
+	if rnd != nil {
+		return rnd.Int() % n
+	}
+
+	a, b, c := 0, n>>1, n-1
+	if limits[3*b] < limits[3*a] {
+		a, b = b, a
+	}
+
+	if limits[3*c] < limits[3*b] {
+		b = c
+		if limits[3*b] < limits[3*a] {
+			b = a
+		}
+	}
+
+	return b
+}
+
+// insertionSort is an internal utility function, sorting short runs of nodes by lowest limits.
+func insertionSort[T cmp.Ordered](limits []T, indexes []int) {
+	for i := 1; i < len(indexes); i++ {
+		for j := i; j > 0 && limits[3*j] < limits[3*j-3]; j-- {
+			swapNodes(limits, indexes, j, j-1)
+		}
 	}
+}
 
-	// Pick index bounds
-	l, r := 0, len(indexes)-1
+// heapSort is an internal utility function, sorting nodes by lowest limits in O(n log n) worst case time.
+func heapSort[T cmp.Ordered](limits []T, indexes []int) {
+	n := len(indexes)
 
-	// Pick pivot
-	p := rand.Int() % len(indexes)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(limits, indexes, i, n)
+	}
+
+	for end := n - 1; end > 0; end-- {
+		swapNodes(limits, indexes, 0, end)
+		siftDown(limits, indexes, 0, end)
+	}
+}
 
-	// Perform in-place assignment of limits and indexes
-	indexes[p], indexes[r] = indexes[r], indexes[p]
-	limits[3*p], limits[3*p+1], limits[3*p+2], limits[3*r], limits[3*r+1], limits[3*r+2] = limits[3*r], limits[3*r+1], limits[3*r+2], limits[3*p], limits[3*p+1], limits[3*p+2]
+// siftDown is an internal utility function, restoring the max-heap property of the first n nodes from the given root.
+func siftDown[T cmp.Ordered](limits []T, indexes []int, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
 
-	for i := range indexes {
-		if limits[3*i] < limits[3*r] {
-			// Perform in-place assignment of limits and indexes
-			indexes[l], indexes[i] = indexes[i], indexes[l]
-			limits[3*l], limits[3*l+1], limits[3*l+2], limits[3*i], limits[3*i+1], limits[3*i+2] = limits[3*i], limits[3*i+1], limits[3*i+2], limits[3*l], limits[3*l+1], limits[3*l+2]
+		if child+1 < n && limits[3*child] < limits[3*child+3] {
+			child++
+		}
 
-			l++
+		if !(limits[3*root] < limits[3*child]) {
+			return
 		}
+
+		swapNodes(limits, indexes, root, child)
+		root = child
 	}
+}
 
-	// Perform in-place assignment of limits and indexes
-	indexes[l], indexes[r] = indexes[r], indexes[l]
-	limits[3*l], limits[3*l+1], limits[3*l+2], limits[3*r], limits[3*r+1], limits[3*r+2] = limits[3*r], limits[3*r+1], limits[3*r+2], limits[3*l], limits[3*l+1], limits[3*l+2]
+// swapNodes is an internal utility function, performing in-place assignment of limits and indexes of two nodes.
+func swapNodes[T cmp.Ordered](limits []T, indexes []int, i, j int) {
+	a, b := (*[3]T)(limits[3*i:]), (*[3]T)(limits[3*j:])
 
-	// Tail recursive calls on branches
-	sort(limits[:3*l], indexes[:l])
-	sort(limits[3*l+3:], indexes[l+1:])
+	indexes[i], indexes[j] = indexes[j], indexes[i]
+	*a, *b = *b, *a
 }
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package intree2d provides a two dimensional counterpart of intree for box stabbing searches:
+// reverse lookups of the axis-aligned boxes including a point, such as geospatial bounding boxes.
+// Boxes are stored in the same flat, implicit tree layout, sorted by lowest x limit and augmented
+// with the reach of each subtree on both axes, so that subtrees out of reach of a point are pruned.
+package intree2d
+
+import "slices"
+
+// stride is the number of limits stored per node: minX, maxX and the greatest maxX of its subtree,
+// then minY, maxY and the lowest minY and greatest maxY of its subtree.
+const stride = 7
+
+// stockSize is the initial capacity of the traversal index stock; as the tree is balanced, it fits
+// the boundaries pending on any root to leaf path without growing.
+const stockSize = 2 * 66
+
+// Bounds is the main interface expected by NewINTree2D(); requires Limits method to access box limits.
+type Bounds interface {
+	Limits() (minX, minY, maxX, maxY float64)
+}
+
+// Box is a plain Bounds implementation, including its limits on both axes.
+type Box struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Limits accesses the box limits.
+func (b Box) Limits() (float64, float64, float64, float64) {
+	return b.MinX, b.MinY, b.MaxX, b.MaxY
+}
+
+// INTree2D is the main package object;
+// holds Slice of reference indices and the respective box limits.
+type INTree2D struct {
+	indexes []int
+	limits  []float64
+}
+
+// NewINTree2D is the main initialization function;
+// creates the tree from the given Slice of Bounds.
+func NewINTree2D(bounds []Bounds) *INTree2D {
+	t := INTree2D{
+		indexes: make([]int, len(bounds)),
+		limits:  make([]float64, stride*len(bounds)),
+	}
+
+	minX := make([]float64, len(bounds))
+	for i, b := range bounds {
+		t.indexes[i] = i
+		minX[i], _, _, _ = b.Limits()
+	}
+
+	slices.SortStableFunc(t.indexes, func(i, j int) int {
+		switch {
+		case minX[i] < minX[j]:
+			return -1
+		case minX[i] > minX[j]:
+			return 1
+		}
+
+		return 0
+	})
+
+	for pos, idx := range t.indexes {
+		l := t.limits[stride*pos : stride*pos+stride]
+		l[0], l[3], l[1], l[4] = bounds[idx].Limits()
+		l[2], l[5], l[6] = l[1], l[3], l[4]
+	}
+
+	t.augment(0, len(t.indexes)-1)
+
+	return &t
+}
+
+// augment is an internal utility function, storing the reach of every subtree within the given node bounds
+// on its center node, bottom-up. Returns the subtree center position, or -1 for an empty subtree.
+func (t *INTree2D) augment(lBoundIdx, rBoundIdx int) int {
+	if lBoundIdx > rBoundIdx {
+		return -1
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+	c := t.limits[stride*centerIdx : stride*centerIdx+stride]
+
+	for _, child := range []int{t.augment(lBoundIdx, centerIdx-1), t.augment(centerIdx+1, rBoundIdx)} {
+		if child < 0 {
+			continue
+		}
+
+		s := t.limits[stride*child : stride*child+stride]
+
+		if s[2] > c[2] {
+			c[2] = s[2]
+		}
+
+		if s[5] < c[5] {
+			c[5] = s[5]
+		}
+
+		if s[6] > c[6] {
+			c[6] = s[6]
+		}
+	}
+
+	return centerIdx
+}
+
+// Len returns the number of boxes stored in the tree.
+func (t *INTree2D) Len() int {
+	return len(t.indexes)
+}
+
+// Including traverses the tree and collects the boxes that include the given point, limits included.
+func (t *INTree2D) Including(x, y float64) []int {
+	result := []int{}
+
+	t.IncludingFunc(x, y, func(idx int) bool {
+		result = append(result, idx)
+		return true
+	})
+
+	return result
+}
+
+// IncludingFunc is the allocation free counterpart of Including;
+// calls fn with the index of every box that includes the given point,
+// and stops the traversal as soon as fn returns false.
+func (t *INTree2D) IncludingFunc(x, y float64, fn func(idx int) bool) {
+	var stock [stockSize]int
+	idxStock := append(stock[:0], 0, len(t.indexes)-1)
+
+	for len(idxStock) > 0 {
+		// Retrieve right and left boundaries from index stock
+		rBoundIdx := idxStock[len(idxStock)-1]
+		lBoundIdx := idxStock[len(idxStock)-2]
+		idxStock = idxStock[:len(idxStock)-2]
+
+		if lBoundIdx > rBoundIdx {
+			continue
+		}
+
+		centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+		c := t.limits[stride*centerIdx : stride*centerIdx+stride]
+
+		// Prune subtrees out of reach of the point on either axis
+		if !(x <= c[2] && c[5] <= y && y <= c[6]) {
+			continue
+		}
+
+		idxStock = append(idxStock, lBoundIdx, centerIdx-1)
+
+		if c[0] <= x {
+			idxStock = append(idxStock, centerIdx+1, rBoundIdx)
+
+			if x <= c[1] && c[3] <= y && y <= c[4] && !fn(t.indexes[centerIdx]) {
+				return
+			}
+		}
+	}
+}
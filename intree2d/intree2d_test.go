@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree2d_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree/intree2d"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree2D(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree2d.NewINTree2D([]intree2d.Bounds{
+			intree2d.Box{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10},
+			intree2d.Box{MinX: 5, MinY: 5, MaxX: 15, MaxY: 15},
+			intree2d.Box{MinX: -5, MinY: 8, MaxX: 2, MaxY: 20},
+		})
+
+		assert.EqualValues(t, 3, tree.Len())
+		assert.ElementsMatch(t, []int{0}, tree.Including(1, 1))
+		assert.ElementsMatch(t, []int{0, 1}, tree.Including(7, 7))
+		assert.ElementsMatch(t, []int{0, 2}, tree.Including(1, 9))
+		assert.ElementsMatch(t, []int{1}, tree.Including(15, 15)) // at the corner
+		assert.Empty(t, tree.Including(12, 2))
+		assert.Empty(t, tree.Including(-3, 0))
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(42))
+
+		bounds := make([]intree2d.Bounds, 2000)
+		for i := range bounds {
+			x, y := rnd.Float64()*100.0, rnd.Float64()*100.0
+			bounds[i] = intree2d.Box{MinX: x, MinY: y, MaxX: x + rnd.Float64()*10.0, MaxY: y + rnd.Float64()*10.0}
+		}
+
+		tree := intree2d.NewINTree2D(bounds)
+
+		for i := 0; i < 500; i++ {
+			x, y := rnd.Float64()*110.0-5.0, rnd.Float64()*110.0-5.0
+
+			expected := []int{}
+			for idx, b := range bounds {
+				minX, minY, maxX, maxY := b.Limits()
+				if minX <= x && x <= maxX && minY <= y && y <= maxY {
+					expected = append(expected, idx)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.Including(x, y), "at (%.2f, %.2f)", x, y)
+		}
+	})
+	t.Run("Case_Early_exit", func(t *testing.T) {
+		tree := intree2d.NewINTree2D([]intree2d.Bounds{
+			intree2d.Box{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10},
+			intree2d.Box{MinX: 5, MinY: 5, MaxX: 15, MaxY: 15},
+		})
+
+		calls := 0
+		tree.IncludingFunc(7, 7, func(idx int) bool {
+			calls++
+			return false
+		})
+
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree2d.NewINTree2D(nil)
+		assert.EqualValues(t, 0, tree.Len())
+		assert.Empty(t, tree.Including(0, 0))
+	})
+}
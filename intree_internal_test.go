@@ -0,0 +1,337 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type internalBounds struct {
+	lower, upper float64
+}
+
+func (ib internalBounds) Limits() (float64, float64) {
+	return ib.lower, ib.upper
+}
+
+func (ib internalBounds) Value() interface{} {
+	return ib.lower
+}
+
+// internalRandomBounds returns n intervals drawn from a fixed seed.
+func internalRandomBounds(n int) []Bounds {
+	rnd := rand.New(rand.NewSource(7))
+	bounds := make([]Bounds, n)
+
+	for i := range bounds {
+		lower := rnd.Float64() * 100.0
+		bounds[i] = internalBounds{lower: lower, upper: lower + rnd.Float64()*10.0}
+	}
+
+	return bounds
+}
+
+func Test_Tree_WithSource(t *testing.T) {
+	t.Run("Case_Deterministic", func(t *testing.T) {
+		bounds := internalRandomBounds(1000)
+
+		tree1 := NewINTreeWithSource(bounds, rand.NewSource(1))
+		tree2 := NewINTreeWithSource(bounds, rand.NewSource(1))
+
+		assert.EqualValues(t, tree1.indexes, tree2.indexes)
+		assert.EqualValues(t, tree1.limits, tree2.limits)
+	})
+	t.Run("Case_Deterministic/valued", func(t *testing.T) {
+		bounds := make([]ValuedBounds, 0, 1000)
+		for _, b := range internalRandomBounds(1000) {
+			bounds = append(bounds, b.(internalBounds))
+		}
+
+		tree1 := NewINTreeVWithSource(bounds, rand.NewSource(1))
+		tree2 := NewINTreeVWithSource(bounds, rand.NewSource(1))
+
+		assert.EqualValues(t, tree1.indexes, tree2.indexes)
+		assert.EqualValues(t, tree1.limits, tree2.limits)
+		assert.EqualValues(t, tree1.values, tree2.values)
+	})
+	t.Run("Case_Concurrent", func(t *testing.T) {
+		bounds := internalRandomBounds(1000)
+		expected := NewINTreeWithSource(bounds, rand.NewSource(1))
+
+		var wg sync.WaitGroup
+		trees := make([]*INTree, 8)
+
+		for i := range trees {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+				trees[i] = NewINTreeWithSource(bounds, rand.NewSource(1))
+			}(i)
+		}
+
+		wg.Wait()
+
+		for _, tree := range trees {
+			assert.EqualValues(t, expected.indexes, tree.indexes)
+			assert.EqualValues(t, expected.limits, tree.limits)
+		}
+	})
+	t.Run("Case_Deterministic_sort", func(t *testing.T) {
+		bounds := internalRandomBounds(1000)
+
+		// Median of three pivots do not depend on the Source
+		tree1 := NewINTreeWithSource(bounds, rand.NewSource(1), WithDeterministicSort())
+		tree2 := NewINTreeWithSource(bounds, rand.NewSource(2), WithDeterministicSort())
+		tree3 := NewINTree(bounds, WithDeterministicSort())
+
+		assert.EqualValues(t, tree1.indexes, tree2.indexes)
+		assert.EqualValues(t, tree1.limits, tree2.limits)
+		assert.EqualValues(t, tree1.indexes, tree3.indexes)
+		assert.EqualValues(t, tree1.limits, tree3.limits)
+
+		for _, val := range []float64{0.0, 12.5, 50.0, 99.9, 105.0} {
+			assert.ElementsMatch(t, NewINTree(bounds).Including(val), tree1.Including(val))
+		}
+	})
+	t.Run("Case_Default_source", func(t *testing.T) {
+		bounds := internalRandomBounds(1000)
+
+		tree := NewINTree(bounds)
+		reference := NewINTreeWithSource(bounds, rand.NewSource(1))
+
+		for _, val := range []float64{0.0, 12.5, 50.0, 99.9, 105.0} {
+			assert.ElementsMatch(t, reference.Including(val), tree.Including(val))
+		}
+	})
+	t.Run("Case_Nil_source", func(t *testing.T) {
+		bounds := internalRandomBounds(1000)
+
+		// A nil Source falls back to median of three pivots instead of panicking
+		tree := NewINTreeWithSource(bounds, nil)
+		expected := NewINTree(bounds, WithDeterministicSort())
+
+		assert.EqualValues(t, expected.indexes, tree.indexes)
+		assert.EqualValues(t, expected.limits, tree.limits)
+		assert.NotPanics(t, func() { NewINTreeVWithSource(nil, nil) })
+	})
+}
+
+// subtreeMax returns the greatest upper limit within the given node bounds, checking the augmented
+// limit of every node on the way.
+func subtreeMax(t *testing.T, tree *INTree, lBoundIdx, rBoundIdx int) float64 {
+	if lBoundIdx > rBoundIdx {
+		return math.Inf(-1)
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+	max := math.Max(tree.limits[3*centerIdx+1], math.Max(
+		subtreeMax(t, tree, lBoundIdx, centerIdx-1),
+		subtreeMax(t, tree, centerIdx+1, rBoundIdx),
+	))
+
+	assert.EqualValues(t, max, tree.limits[3*centerIdx+2], "augmented limit at position %d", centerIdx)
+
+	return max
+}
+
+func Test_Tree_Augment(t *testing.T) {
+	t.Run("Case_Negative_limits", func(t *testing.T) {
+		bounds := make([]Bounds, 0, 500)
+		for _, b := range internalRandomBounds(500) {
+			l, u := b.Limits()
+			bounds = append(bounds, internalBounds{lower: l - 200.0, upper: u - 200.0})
+		}
+
+		tree := NewINTreeWithSource(bounds, rand.NewSource(1))
+		subtreeMax(t, tree, 0, tree.Len()-1)
+
+		// The root augmented limit lies below zero and prunes every subtree for values above it
+		assert.Less(t, tree.limits[3*(tree.Len()>>1)+2], 0.0)
+		assert.EqualValues(t, 0, tree.CountIncluding(-50.0))
+
+		for _, val := range []float64{-200.0, -150.5, -120.0, -100.01, -95.0} {
+			expected := []int{}
+			for i, b := range bounds {
+				l, u := b.Limits()
+				if l <= val && val <= u {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.Including(val))
+		}
+	})
+	t.Run("Case_Mixed_limits", func(t *testing.T) {
+		tree := NewINTreeWithSource(internalRandomBounds(500), rand.NewSource(1))
+		subtreeMax(t, tree, 0, tree.Len()-1)
+	})
+}
+
+// assertSorted checks the nodes are sorted by lowest limits, keep every original index once,
+// and keep the limits of each original index.
+func assertSorted(t *testing.T, limits []float64, indexes []int, original []float64) {
+	seen := make([]bool, len(indexes))
+
+	for pos, idx := range indexes {
+		assert.False(t, seen[idx])
+		seen[idx] = true
+
+		assert.EqualValues(t, original[3*idx:3*idx+2], limits[3*pos:3*pos+2])
+
+		if pos > 0 {
+			assert.LessOrEqual(t, limits[3*pos-3], limits[3*pos])
+		}
+	}
+}
+
+func Test_Tree_Sort(t *testing.T) {
+	n := 20000
+	inputs := map[string]func(i int) float64{
+		"equal":    func(i int) float64 { return 1.0 },
+		"sorted":   func(i int) float64 { return float64(i) },
+		"reversed": func(i int) float64 { return float64(n - i) },
+		"few":      func(i int) float64 { return float64(i % 3) },
+		"organ":    func(i int) float64 { return float64(n/2 - abs(n/2-i)) },
+		"random":   func(i int) float64 { return float64((i * 7919) % 1009) },
+	}
+
+	for name, lower := range inputs {
+		t.Run("Case_"+name, func(t *testing.T) {
+			limits := make([]float64, 3*n)
+			indexes := make([]int, n)
+
+			for i := range indexes {
+				indexes[i] = i
+				limits[3*i], limits[3*i+1] = lower(i), lower(i)+1.0
+			}
+
+			original := append([]float64(nil), limits...)
+			sort(limits, indexes, rand.New(rand.NewSource(1)))
+
+			assertSorted(t, limits, indexes, original)
+		})
+		t.Run("Case_"+name+"/median_of_three", func(t *testing.T) {
+			limits := make([]float64, 3*n)
+			indexes := make([]int, n)
+
+			for i := range indexes {
+				indexes[i] = i
+				limits[3*i], limits[3*i+1] = lower(i), lower(i)+1.0
+			}
+
+			original := append([]float64(nil), limits...)
+			sort(limits, indexes, nil)
+
+			assertSorted(t, limits, indexes, original)
+		})
+	}
+	t.Run("Case_HeapSort", func(t *testing.T) {
+		limits := make([]float64, 3*1000)
+		indexes := make([]int, 1000)
+
+		for i := range indexes {
+			indexes[i] = i
+			limits[3*i], limits[3*i+1] = float64((i*31)%97), float64(i)
+		}
+
+		original := append([]float64(nil), limits...)
+
+		// A zero depth limit sorts through the HeapSort fallback only
+		introSort(limits, indexes, rand.New(rand.NewSource(1)), 0)
+
+		assertSorted(t, limits, indexes, original)
+	})
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func Test_Tree_CapacityHint(t *testing.T) {
+	bounds := []Bounds{internalBounds{0.0, 1.0}, internalBounds{2.0, 3.0}}
+
+	t.Run("Case_Reserved", func(t *testing.T) {
+		tree := NewINTree(bounds, WithCapacityHint(100), WithRetainedBounds())
+		assert.EqualValues(t, 2, len(tree.indexes))
+		assert.True(t, cap(tree.indexes) >= 100)
+		assert.True(t, cap(tree.limits) >= 300)
+		assert.True(t, cap(tree.bounds) >= 100)
+
+		limits := tree.limits
+		tree.Insert(internalBounds{4.0, 5.0})
+		assert.True(t, &limits[0] == &tree.limits[0], "limits must not be reallocated")
+	})
+	t.Run("Case_Reserved/stream", func(t *testing.T) {
+		tree := NewINTreeFrom(func(yield func(Bounds) bool) {
+			for _, b := range bounds {
+				yield(b)
+			}
+		}, WithCapacityHint(100))
+		assert.EqualValues(t, 2, len(tree.indexes))
+		assert.True(t, cap(tree.indexes) >= 100)
+		assert.True(t, cap(tree.limits) >= 300)
+	})
+	t.Run("Case_Border/hint_below_length", func(t *testing.T) {
+		tree := NewINTree(bounds, WithCapacityHint(1))
+		assert.EqualValues(t, 2, len(tree.indexes))
+		assert.EqualValues(t, 2, cap(tree.indexes))
+	})
+}
+
+func Test_Tree_CheckInvariants_Corrupted(t *testing.T) {
+	build := func() *INTree {
+		return NewINTreeV([]ValuedBounds{internalBounds{0.0, 1.0}, internalBounds{2.0, 3.0}, internalBounds{1.0, 5.0}},
+			WithEytzingerLayout())
+	}
+
+	t.Run("Case_Values", func(t *testing.T) {
+		tree := build()
+		tree.values = tree.values[:1]
+		assert.ErrorIs(t, tree.CheckInvariants(), ErrCorruptedTree)
+	})
+	t.Run("Case_Layout/slots", func(t *testing.T) {
+		tree := build()
+		tree.layout.positions[0], tree.layout.positions[1] = tree.layout.positions[1], tree.layout.positions[0]
+		assert.ErrorIs(t, tree.CheckInvariants(), ErrCorruptedTree)
+	})
+	t.Run("Case_Layout/augmented_limit", func(t *testing.T) {
+		tree := build()
+		tree.layout.limits[2] = 4.0
+		assert.ErrorIs(t, tree.CheckInvariants(), ErrCorruptedTree)
+	})
+	t.Run("Case_Layout/stale", func(t *testing.T) {
+		tree := build()
+		tree.layout = newEytzinger(tree.limits[:3])
+		assert.ErrorIs(t, tree.CheckInvariants(), ErrCorruptedTree)
+	})
+}
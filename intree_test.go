@@ -54,6 +54,25 @@ func (wb *valuedTestBounds) Value() interface{} {
 	return wb.value
 }
 
+// exampleBounds returns the intervals used by the Case_Example tests.
+func exampleBounds() []intree.Bounds {
+	return []intree.Bounds{
+		&testBounds{Lower: 4.0, Upper: 6.0},
+		&testBounds{Lower: 5.0, Upper: 7.0},
+		&testBounds{Lower: 4.0, Upper: 8.0},
+		&testBounds{Lower: 1.0, Upper: 3.0},
+		&testBounds{Lower: 7.0, Upper: 9.0},
+		&testBounds{Lower: 3.0, Upper: 6.0},
+		&testBounds{Lower: 2.0, Upper: 3.0},
+		&testBounds{Lower: 5.3, Upper: 7.9},
+		&testBounds{Lower: 3.2, Upper: 7.5},
+		&testBounds{Lower: 4.4, Upper: 5.1},
+		&testBounds{Lower: 4.1, Upper: 4.9},
+		&testBounds{Lower: 1.3, Upper: 3.1},
+		&testBounds{Lower: 7.9, Upper: 8.9},
+	}
+}
+
 func Test_Tree(t *testing.T) {
 	t.Run("Case_Example", func(t *testing.T) {
 		inputBounds := []intree.Bounds{
@@ -298,3 +317,123 @@ func Test_Tree_Valued(t *testing.T) {
 		assert.EqualValues(t, matches[0]+1, inputBounds[matches[0]].(intree.ValuedBounds).Value())
 	})
 }
+
+func Test_Tree_IncludingValues(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := []intree.ValuedBounds{
+			&valuedTestBounds{Lower: 4.0, Upper: 6.0, value: 1},
+			&valuedTestBounds{Lower: 5.0, Upper: 7.0, value: 2},
+			&valuedTestBounds{Lower: 4.0, Upper: 8.0, value: 3},
+			&valuedTestBounds{Lower: 1.0, Upper: 3.0, value: 4},
+			&valuedTestBounds{Lower: 7.0, Upper: 9.0, value: 5},
+			&valuedTestBounds{Lower: 3.0, Upper: 6.0, value: 6},
+			&valuedTestBounds{Lower: 2.0, Upper: 3.0, value: 7},
+			&valuedTestBounds{Lower: 5.3, Upper: 7.9, value: 8},
+			&valuedTestBounds{Lower: 3.2, Upper: 7.5, value: 9},
+			&valuedTestBounds{Lower: 4.4, Upper: 5.1, value: 10},
+			&valuedTestBounds{Lower: 4.1, Upper: 4.9, value: 11},
+			&valuedTestBounds{Lower: 1.3, Upper: 3.1, value: 12},
+			&valuedTestBounds{Lower: 7.9, Upper: 8.9, value: 13},
+		}
+
+		tree := intree.NewINTreeV(inputBounds)
+		values := tree.IncludingValues(4.3)
+
+		assert.ElementsMatch(t, []interface{}{1, 3, 6, 9, 11}, values)
+
+		// Values are yielded in the same order as the matched indices
+		for i, matchedIndex := range tree.Including(4.3) {
+			assert.EqualValues(t, matchedIndex+1, values[i])
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTreeV(nil)
+		values := tree.IncludingValues(4.3)
+		assert.EqualValues(t, 0, len(values))
+	})
+	t.Run("Case_Border/no_match", func(t *testing.T) {
+		tree := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 4.0, Upper: 6.0, value: 1},
+		})
+		values := tree.IncludingValues(7)
+		assert.NotNil(t, values)
+		assert.EqualValues(t, 0, len(values))
+	})
+	t.Run("Case_Border/unvalued_tree", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.Nil(t, tree.IncludingValues(4.3))
+	})
+}
+
+func Test_Tree_Intervals(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree := intree.NewINTree(inputBounds)
+
+		intervals := tree.Intervals()
+
+		assert.EqualValues(t, len(inputBounds), len(intervals))
+		for i, b := range inputBounds {
+			lowerLimit, upperLimit := b.Limits()
+			assert.EqualValues(t, [2]float64{lowerLimit, upperLimit}, intervals[i])
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+		assert.EqualValues(t, 0, len(tree.Intervals()))
+	})
+}
+
+func Test_Tree_Len(t *testing.T) {
+	assert.EqualValues(t, 13, intree.NewINTree(exampleBounds()).Len())
+	assert.EqualValues(t, 0, intree.NewINTree(nil).Len())
+}
+
+func Test_Tree_IncludingBounds(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree := intree.NewINTree(inputBounds, intree.WithRetainedBounds())
+
+		matches := tree.Including(4.3)
+		bounds := tree.IncludingBounds(4.3)
+
+		assert.EqualValues(t, len(matches), len(bounds))
+		for i, matchedIndex := range matches {
+			assert.Same(t, inputBounds[matchedIndex], bounds[i])
+		}
+	})
+	t.Run("Case_Example/valued", func(t *testing.T) {
+		inputBounds := []intree.ValuedBounds{
+			&valuedTestBounds{Lower: 4.0, Upper: 6.0, value: 1},
+			&valuedTestBounds{Lower: 5.0, Upper: 7.0, value: 2},
+			&valuedTestBounds{Lower: 1.0, Upper: 3.0, value: 3},
+		}
+		tree := intree.NewINTreeV(inputBounds, intree.WithRetainedBounds())
+
+		bounds := tree.IncludingValuedBounds(5.5)
+		assert.EqualValues(t, 2, len(bounds))
+		for _, b := range bounds {
+			lowerLimit, _ := b.Limits()
+			assert.True(t, lowerLimit == 4.0 || lowerLimit == 5.0)
+			assert.Contains(t, []interface{}{1, 2}, b.Value())
+		}
+
+		assert.EqualValues(t, 2, len(tree.IncludingBounds(5.5)))
+	})
+	t.Run("Case_Border/not_retained", func(t *testing.T) {
+		assert.Nil(t, intree.NewINTree(exampleBounds()).IncludingBounds(4.3))
+		assert.Nil(t, intree.NewINTreeV(nil).IncludingValuedBounds(4.3))
+
+		// Plain Bounds do not yield ValuedBounds
+		assert.Nil(t, intree.NewINTree(exampleBounds(), intree.WithRetainedBounds()).IncludingValuedBounds(4.3))
+	})
+	t.Run("Case_Border/copied_input", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree := intree.NewINTree(inputBounds, intree.WithRetainedBounds())
+
+		original := inputBounds[0]
+		inputBounds[0] = &testBounds{Lower: 100.0, Upper: 101.0}
+
+		assert.Contains(t, tree.IncludingBounds(4.3), original)
+	})
+}
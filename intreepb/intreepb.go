@@ -0,0 +1,196 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package intreepb holds the DTOs of the intree.proto schema, along with their conversions from and to
+// intree types, so that services can exchange interval sets and prebuilt trees over gRPC.
+// Messages are encoded in the protobuf wire format, interoperable with code generated from intree.proto
+// for any language, without depending on the protobuf runtime.
+package intreepb
+
+import (
+	"errors"
+
+	"github.com/lggomez/intree"
+)
+
+// ErrInvalidMessage is returned when unmarshaling data that is not a valid encoding of the message.
+var ErrInvalidMessage = errors.New("intreepb: invalid message")
+
+// Field numbers of the intree.proto messages.
+const (
+	intervalLowerField        = 1
+	intervalUpperField        = 2
+	intervalSetIntervalsField = 1
+	treeEncodingField         = 1
+)
+
+// Interval is the DTO of the Interval message.
+type Interval struct {
+	Lower, Upper float64
+}
+
+// IntervalSet is the DTO of the IntervalSet message.
+type IntervalSet struct {
+	Intervals []Interval
+}
+
+// Tree is the DTO of the Tree message.
+type Tree struct {
+	Encoding []byte
+}
+
+// IntervalSetToProto converts the given interval set into its DTO.
+func IntervalSetToProto(s intree.IntervalSet) *IntervalSet {
+	m := &IntervalSet{Intervals: make([]Interval, len(s))}
+
+	for i, interval := range s {
+		m.Intervals[i] = Interval{Lower: interval.Lower, Upper: interval.Upper}
+	}
+
+	return m
+}
+
+// IntervalSetFromProto converts the given DTO into an interval set.
+func IntervalSetFromProto(m *IntervalSet) intree.IntervalSet {
+	s := make(intree.IntervalSet, len(m.Intervals))
+
+	for i, interval := range m.Intervals {
+		s[i] = intree.Interval{Lower: interval.Lower, Upper: interval.Upper}
+	}
+
+	return s
+}
+
+// ToProto converts the given tree into its DTO, holding its binary encoding so that it is loaded as built.
+// Values associated to ValuedBounds, retained bounds and staged intervals are not converted.
+func ToProto(t *intree.INTree) (*Tree, error) {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tree{Encoding: data}, nil
+}
+
+// FromProto converts the given DTO into a tree, decoding its binary encoding.
+// Returns an intree.ErrInvalidEncoding wrapped error if the encoding is not valid.
+func FromProto(m *Tree) (*intree.INTree, error) {
+	t := &intree.INTree{}
+	if err := t.UnmarshalBinary(m.Encoding); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Marshal encodes the message in the protobuf wire format.
+func (m *Interval) Marshal() ([]byte, error) {
+	return m.appendTo(nil), nil
+}
+
+// Unmarshal decodes the message from the protobuf wire format, replacing its contents.
+// Unknown fields are skipped. Returns an ErrInvalidMessage wrapped error on malformed data.
+func (m *Interval) Unmarshal(data []byte) error {
+	*m = Interval{}
+
+	return consumeFields(data, func(num int, typ wireType, value []byte) error {
+		switch {
+		case num == intervalLowerField && typ == fixed64Type:
+			m.Lower = decodeDouble(value)
+		case num == intervalUpperField && typ == fixed64Type:
+			m.Upper = decodeDouble(value)
+		}
+
+		return nil
+	})
+}
+
+// appendTo is an internal utility function, appending the encoded message to the given buffer.
+// Zero limits are omitted, as proto3 does with default values.
+func (m *Interval) appendTo(buf []byte) []byte {
+	if m.Lower != 0 {
+		buf = appendDouble(buf, intervalLowerField, m.Lower)
+	}
+
+	if m.Upper != 0 {
+		buf = appendDouble(buf, intervalUpperField, m.Upper)
+	}
+
+	return buf
+}
+
+// Marshal encodes the message in the protobuf wire format.
+func (m *IntervalSet) Marshal() ([]byte, error) {
+	buf := []byte{}
+
+	for i := range m.Intervals {
+		buf = appendBytes(buf, intervalSetIntervalsField, m.Intervals[i].appendTo(nil))
+	}
+
+	return buf, nil
+}
+
+// Unmarshal decodes the message from the protobuf wire format, replacing its contents.
+// Unknown fields are skipped. Returns an ErrInvalidMessage wrapped error on malformed data.
+func (m *IntervalSet) Unmarshal(data []byte) error {
+	*m = IntervalSet{Intervals: []Interval{}}
+
+	return consumeFields(data, func(num int, typ wireType, value []byte) error {
+		if num != intervalSetIntervalsField || typ != bytesType {
+			return nil
+		}
+
+		var interval Interval
+		if err := interval.Unmarshal(value); err != nil {
+			return err
+		}
+
+		m.Intervals = append(m.Intervals, interval)
+
+		return nil
+	})
+}
+
+// Marshal encodes the message in the protobuf wire format.
+func (m *Tree) Marshal() ([]byte, error) {
+	buf := []byte{}
+
+	if len(m.Encoding) > 0 {
+		buf = appendBytes(buf, treeEncodingField, m.Encoding)
+	}
+
+	return buf, nil
+}
+
+// Unmarshal decodes the message from the protobuf wire format, replacing its contents.
+// Unknown fields are skipped. Returns an ErrInvalidMessage wrapped error on malformed data.
+func (m *Tree) Unmarshal(data []byte) error {
+	*m = Tree{}
+
+	return consumeFields(data, func(num int, typ wireType, value []byte) error {
+		if num == treeEncodingField && typ == bytesType {
+			m.Encoding = append([]byte(nil), value...)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intreepb_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/lggomez/intree/intreepb"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBounds struct {
+	Lower, Upper float64
+}
+
+func (tb testBounds) Limits() (float64, float64) {
+	return tb.Lower, tb.Upper
+}
+
+func Test_Interval(t *testing.T) {
+	t.Run("Case_Wire_format", func(t *testing.T) {
+		data, err := (&intreepb.Interval{Lower: 1.5, Upper: -2.0}).Marshal()
+		assert.NoError(t, err)
+
+		expected := []byte{0x09}
+		expected = binary.LittleEndian.AppendUint64(expected, math.Float64bits(1.5))
+		expected = append(expected, 0x11)
+		expected = binary.LittleEndian.AppendUint64(expected, math.Float64bits(-2.0))
+		assert.EqualValues(t, expected, data)
+
+		// Default values are omitted
+		data, err = (&intreepb.Interval{Upper: 3.0}).Marshal()
+		assert.NoError(t, err)
+		assert.EqualValues(t, 9, len(data))
+
+		var decoded intreepb.Interval
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.EqualValues(t, intreepb.Interval{Upper: 3.0}, decoded)
+	})
+	t.Run("Case_Unknown_fields", func(t *testing.T) {
+		data, err := (&intreepb.Interval{Lower: 1.0, Upper: 2.0}).Marshal()
+		assert.NoError(t, err)
+
+		// A varint field 3, a fixed32 field 4 and a length delimited field 5
+		data = append(data, 0x18, 0x96, 0x01, 0x25, 1, 2, 3, 4, 0x2a, 2, 'o', 'k')
+
+		var decoded intreepb.Interval
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.EqualValues(t, intreepb.Interval{Lower: 1.0, Upper: 2.0}, decoded)
+	})
+	t.Run("Case_Border/malformed", func(t *testing.T) {
+		data, err := (&intreepb.Interval{Lower: 1.0, Upper: 2.0}).Marshal()
+		assert.NoError(t, err)
+
+		// Every prefix but the one ending after the first field is truncated
+		for size := 1; size < len(data); size++ {
+			if size == 9 {
+				continue
+			}
+
+			err := (&intreepb.Interval{}).Unmarshal(data[:size])
+			assert.True(t, errors.Is(err, intreepb.ErrInvalidMessage), "size %d", size)
+		}
+
+		for _, malformed := range [][]byte{{0x00}, {0x0b}, {0x0a, 0x05, 1}, {0x08, 0xff}} {
+			err := (&intreepb.Interval{}).Unmarshal(malformed)
+			assert.True(t, errors.Is(err, intreepb.ErrInvalidMessage), "data %v", malformed)
+		}
+	})
+}
+
+func Test_IntervalSet(t *testing.T) {
+	t.Run("Case_Roundtrip", func(t *testing.T) {
+		set := intree.IntervalSet{{Lower: 0.0, Upper: 2.0}, {Lower: 1.0, Upper: 3.0}, {Lower: -5.0, Upper: 0.0}}
+
+		data, err := intreepb.IntervalSetToProto(set).Marshal()
+		assert.NoError(t, err)
+
+		var decoded intreepb.IntervalSet
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.EqualValues(t, set, intreepb.IntervalSetFromProto(&decoded))
+	})
+	t.Run("Case_Border/empty", func(t *testing.T) {
+		data, err := intreepb.IntervalSetToProto(nil).Marshal()
+		assert.NoError(t, err)
+		assert.Empty(t, data)
+
+		var decoded intreepb.IntervalSet
+		assert.NoError(t, decoded.Unmarshal(data))
+		assert.Empty(t, intreepb.IntervalSetFromProto(&decoded))
+	})
+	t.Run("Case_Border/malformed_interval", func(t *testing.T) {
+		err := (&intreepb.IntervalSet{}).Unmarshal([]byte{0x0a, 0x02, 0x09, 0x00})
+		assert.True(t, errors.Is(err, intreepb.ErrInvalidMessage))
+	})
+}
+
+func Test_Tree(t *testing.T) {
+	t.Run("Case_Roundtrip", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			testBounds{Lower: 0.0, Upper: 2.0},
+			testBounds{Lower: 5.0, Upper: 6.0},
+			testBounds{Lower: 1.0, Upper: 3.0},
+		}, intree.WithEndpoints(intree.UpperOpen))
+
+		m, err := intreepb.ToProto(tree)
+		assert.NoError(t, err)
+
+		data, err := m.Marshal()
+		assert.NoError(t, err)
+
+		var decoded intreepb.Tree
+		assert.NoError(t, decoded.Unmarshal(data))
+
+		loaded, err := intreepb.FromProto(&decoded)
+		assert.NoError(t, err)
+		assert.EqualValues(t, tree.Intervals(), loaded.Intervals())
+		assert.EqualValues(t, intree.UpperOpen, loaded.Endpoints())
+		assert.ElementsMatch(t, []int{0, 2}, loaded.Including(1.5))
+		assert.EqualValues(t, []int{2}, loaded.Including(2.0))
+	})
+	t.Run("Case_Border/invalid_encoding", func(t *testing.T) {
+		_, err := intreepb.FromProto(&intreepb.Tree{Encoding: []byte{0xff}})
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+	})
+}
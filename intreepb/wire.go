@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intreepb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// wireType is a protobuf wire type, telling how the value of a field is encoded.
+type wireType byte
+
+// Wire types of the protobuf wire format; groups are deprecated and not supported.
+const (
+	varintType  wireType = 0
+	fixed64Type wireType = 1
+	bytesType   wireType = 2
+	fixed32Type wireType = 5
+)
+
+// appendTag is an internal utility function, appending the tag of the given field.
+func appendTag(buf []byte, num int, typ wireType) []byte {
+	return binary.AppendUvarint(buf, uint64(num)<<3|uint64(typ))
+}
+
+// appendDouble is an internal utility function, appending a double field.
+func appendDouble(buf []byte, num int, v float64) []byte {
+	buf = appendTag(buf, num, fixed64Type)
+
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+// appendBytes is an internal utility function, appending a length delimited field.
+func appendBytes(buf []byte, num int, v []byte) []byte {
+	buf = appendTag(buf, num, bytesType)
+	buf = binary.AppendUvarint(buf, uint64(len(v)))
+
+	return append(buf, v...)
+}
+
+// decodeDouble is an internal utility function, decoding the value of a double field.
+func decodeDouble(value []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(value))
+}
+
+// consumeFields is an internal utility function, calling fn with the number, wire type and raw value of every
+// field of the given message: the varint bytes, the fixed size bytes or the length delimited contents.
+func consumeFields(data []byte, fn func(num int, typ wireType, value []byte) error) error {
+	for offset := 0; offset < len(data); {
+		tag, n := binary.Uvarint(data[offset:])
+		if n <= 0 || tag>>3 == 0 || tag>>3 > math.MaxInt32 {
+			return fmt.Errorf("%w: invalid tag at offset %d", ErrInvalidMessage, offset)
+		}
+
+		offset += n
+		num, typ := int(tag>>3), wireType(tag&7)
+
+		var size int
+
+		switch typ {
+		case varintType:
+			if _, n := binary.Uvarint(data[offset:]); n > 0 {
+				size = n
+			} else {
+				return fmt.Errorf("%w: invalid varint of field %d", ErrInvalidMessage, num)
+			}
+		case fixed64Type:
+			size = 8
+		case fixed32Type:
+			size = 4
+		case bytesType:
+			length, n := binary.Uvarint(data[offset:])
+			if n <= 0 || length > uint64(len(data)-offset-n) {
+				return fmt.Errorf("%w: invalid length of field %d", ErrInvalidMessage, num)
+			}
+
+			offset += n
+			size = int(length)
+		default:
+			return fmt.Errorf("%w: unsupported wire type %d of field %d", ErrInvalidMessage, typ, num)
+		}
+
+		if size > len(data)-offset {
+			return fmt.Errorf("%w: truncated field %d", ErrInvalidMessage, num)
+		}
+
+		if err := fn(num, typ, data[offset:offset+size]); err != nil {
+			return err
+		}
+
+		offset += size
+	}
+
+	return nil
+}
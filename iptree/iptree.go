@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package iptree provides an IP range layer over intree, answering which ranges include a netip.Addr.
+// Addresses are compared as 16 byte big endian keys, with IPv4 addresses mapped into IPv6 (::ffff:a.b.c.d),
+// so that IPv4 ranges also include the IPv4-mapped IPv6 form of their addresses and vice versa.
+package iptree
+
+import (
+	"net/netip"
+
+	"github.com/lggomez/intree"
+)
+
+// Range is an IP address range, including both its From and To addresses.
+type Range struct {
+	From, To netip.Addr
+}
+
+// PrefixRange returns the range of addresses covered by the given prefix.
+// Returns the zero Range, which includes no address, for an invalid prefix.
+func PrefixRange(p netip.Prefix) Range {
+	if !p.IsValid() {
+		return Range{}
+	}
+
+	p = p.Masked()
+	from := p.Addr()
+
+	bits := p.Bits()
+	if from.Is4() {
+		bits += 96
+	}
+
+	last := from.As16()
+	for i := bits; i < 128; i++ {
+		last[i/8] |= 1 << (7 - i%8)
+	}
+
+	to := netip.AddrFrom16(last)
+	if from.Is4() {
+		to = to.Unmap()
+	}
+
+	return Range{From: from, To: to}
+}
+
+// IPTree is the IP range counterpart of intree.INTree; returns indices to the initial ranges array.
+type IPTree struct {
+	tree *intree.INTreeOf[string]
+}
+
+// NewIPTree is the main initialization function;
+// creates the tree from the given Slice of ranges. Ranges with an invalid address include no address.
+func NewIPTree(ranges []Range) *IPTree {
+	keyBounds := make([]intree.BoundsOf[string], len(ranges))
+
+	for i, r := range ranges {
+		keyBounds[i] = keyRange(r)
+	}
+
+	return &IPTree{tree: intree.NewINTreeOf(keyBounds)}
+}
+
+// NewIPTreeFromPrefixes is the prefix initialization function;
+// creates the tree from the ranges covered by the given Slice of prefixes.
+func NewIPTreeFromPrefixes(prefixes []netip.Prefix) *IPTree {
+	ranges := make([]Range, len(prefixes))
+
+	for i, p := range prefixes {
+		ranges[i] = PrefixRange(p)
+	}
+
+	return NewIPTree(ranges)
+}
+
+// Len returns the number of ranges stored in the tree.
+func (t *IPTree) Len() int {
+	return t.tree.Len()
+}
+
+// Lookup traverses the tree and collects the ranges that include the given address.
+// Address zones are ignored; an invalid address is included in no range.
+func (t *IPTree) Lookup(addr netip.Addr) []int {
+	if !addr.IsValid() {
+		return []int{}
+	}
+
+	return t.tree.Including(key(addr))
+}
+
+// key is an internal utility function, mapping an address to its 16 byte big endian comparison key.
+func key(addr netip.Addr) string {
+	b := addr.As16()
+
+	return string(b[:])
+}
+
+// keyRange is an internal utility function, mapping a range to its comparison key limits.
+// Invalid ranges get a 17 byte lower limit, above every 16 byte key, and an empty upper limit,
+// so that they include no key.
+func keyRange(r Range) keyBounds {
+	if !r.From.IsValid() || !r.To.IsValid() {
+		return keyBounds{lower: "\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff", upper: ""}
+	}
+
+	return keyBounds{lower: key(r.From), upper: key(r.To)}
+}
+
+// keyBounds is an internal intree.BoundsOf implementation, holding range limits as comparison keys.
+type keyBounds struct {
+	lower, upper string
+}
+
+// Limits accesses the range limits.
+func (kb keyBounds) Limits() (string, string) {
+	return kb.lower, kb.upper
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package iptree_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/lggomez/intree/iptree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IPTree(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := iptree.NewIPTreeFromPrefixes([]netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("10.1.0.0/16"),
+			netip.MustParsePrefix("192.168.1.0/24"),
+			netip.MustParsePrefix("2001:db8::/32"),
+		})
+
+		assert.EqualValues(t, 4, tree.Len())
+		assert.ElementsMatch(t, []int{0, 1}, tree.Lookup(netip.MustParseAddr("10.1.2.3")))
+		assert.ElementsMatch(t, []int{0}, tree.Lookup(netip.MustParseAddr("10.255.255.255")))
+		assert.ElementsMatch(t, []int{2}, tree.Lookup(netip.MustParseAddr("192.168.1.0")))
+		assert.Empty(t, tree.Lookup(netip.MustParseAddr("192.168.2.0")))
+		assert.ElementsMatch(t, []int{3}, tree.Lookup(netip.MustParseAddr("2001:db8:ffff::1")))
+		assert.Empty(t, tree.Lookup(netip.MustParseAddr("2001:db9::")))
+	})
+	t.Run("Case_Ranges", func(t *testing.T) {
+		tree := iptree.NewIPTree([]iptree.Range{
+			{From: netip.MustParseAddr("1.2.3.4"), To: netip.MustParseAddr("1.2.4.10")},
+			{From: netip.MustParseAddr("::1"), To: netip.MustParseAddr("::1")},
+		})
+
+		assert.ElementsMatch(t, []int{0}, tree.Lookup(netip.MustParseAddr("1.2.3.255")))
+		assert.Empty(t, tree.Lookup(netip.MustParseAddr("1.2.4.11")))
+		assert.ElementsMatch(t, []int{1}, tree.Lookup(netip.MustParseAddr("::1")))
+	})
+	t.Run("Case_Mapped", func(t *testing.T) {
+		tree := iptree.NewIPTreeFromPrefixes([]netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("::ffff:172.16.0.0/108"),
+		})
+
+		assert.ElementsMatch(t, []int{0}, tree.Lookup(netip.MustParseAddr("::ffff:10.0.0.1")))
+		assert.ElementsMatch(t, []int{1}, tree.Lookup(netip.MustParseAddr("172.16.5.5")))
+	})
+	t.Run("Case_PrefixRange", func(t *testing.T) {
+		r := iptree.PrefixRange(netip.MustParsePrefix("10.1.2.3/16"))
+		assert.EqualValues(t, netip.MustParseAddr("10.1.0.0"), r.From)
+		assert.EqualValues(t, netip.MustParseAddr("10.1.255.255"), r.To)
+
+		r = iptree.PrefixRange(netip.MustParsePrefix("2001:db8::/127"))
+		assert.EqualValues(t, netip.MustParseAddr("2001:db8::1"), r.To)
+
+		r = iptree.PrefixRange(netip.MustParsePrefix("0.0.0.0/0"))
+		assert.EqualValues(t, netip.MustParseAddr("255.255.255.255"), r.To)
+	})
+	t.Run("Case_Border/invalid", func(t *testing.T) {
+		tree := iptree.NewIPTree([]iptree.Range{{}, iptree.PrefixRange(netip.Prefix{})})
+
+		assert.Empty(t, tree.Lookup(netip.MustParseAddr("0.0.0.0")))
+		assert.Empty(t, tree.Lookup(netip.MustParseAddr("::")))
+		assert.Empty(t, tree.Lookup(netip.Addr{}))
+	})
+	t.Run("Case_Border/zone", func(t *testing.T) {
+		tree := iptree.NewIPTreeFromPrefixes([]netip.Prefix{netip.MustParsePrefix("fe80::/10")})
+		assert.ElementsMatch(t, []int{0}, tree.Lookup(netip.MustParseAddr("fe80::1%eth0")))
+	})
+}
+
+func Benchmark_NewIPTree(b *testing.B) {
+	// Duplicate prefixes, as in routing tables announcing the same prefix from several peers
+	prefixes := make([]netip.Prefix, 100000)
+	for i := range prefixes {
+		prefixes[i] = netip.PrefixFrom(netip.AddrFrom4([4]byte{10, byte(i % 4), 0, 0}), 16)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		iptree.NewIPTreeFromPrefixes(prefixes)
+	}
+}
@@ -0,0 +1,159 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	_ json.Marshaler   = (*INTree)(nil)
+	_ json.Unmarshaler = (*INTree)(nil)
+)
+
+// Interval is a plain Bounds implementation, encoded in JSON as a [lower, upper] pair.
+type Interval struct {
+	Lower, Upper float64
+}
+
+// Limits accesses the interval limits.
+func (i Interval) Limits() (float64, float64) {
+	return i.Lower, i.Upper
+}
+
+// MarshalJSON encodes the interval as a [lower, upper] pair.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{i.Lower, i.Upper})
+}
+
+// UnmarshalJSON decodes the interval from a [lower, upper] pair.
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var limits [2]float64
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return err
+	}
+
+	i.Lower, i.Upper = limits[0], limits[1]
+
+	return nil
+}
+
+// IntervalSet is a plain list of intervals, useful for keeping interval definitions in configuration files.
+type IntervalSet []Interval
+
+// Bounds returns the intervals as a Bounds Slice, in the same order.
+func (s IntervalSet) Bounds() []Bounds {
+	bounds := make([]Bounds, len(s))
+
+	for i := range s {
+		bounds[i] = s[i]
+	}
+
+	return bounds
+}
+
+// Tree builds a new tree over the intervals, with the given options.
+func (s IntervalSet) Tree(opts ...Option) *INTree {
+	return NewINTree(s.Bounds(), opts...)
+}
+
+// jsonTree is the JSON form of a tree: the intervals by original index, the original indices
+// in node order, so that decoding does not need to sort them again, and the tree endpoints.
+type jsonTree struct {
+	Intervals IntervalSet `json:"intervals"`
+	Order     []int       `json:"order"`
+	Endpoints Endpoints   `json:"endpoints,omitempty"`
+}
+
+// MarshalJSON encodes the tree intervals along with the prebuilt node order and endpoints. As with MarshalBinary,
+// values associated to ValuedBounds, retained bounds and staged intervals are not encoded.
+// Intervals removed by Remove are not encoded, the following indices shifting down as with Compact.
+// Trees holding infinite or NaN limits cannot be encoded, as JSON has no representation for them.
+func (t *INTree) MarshalJSON() ([]byte, error) {
+	t = t.merged()
+	intervals := make(IntervalSet, 0, len(t.positions)-t.removed)
+	shifted := make([]int, len(t.positions))
+
+	t.liveIntervals(func(idx int, lower, upper float64) {
+		shifted[idx] = len(intervals)
+		intervals = append(intervals, Interval{Lower: lower, Upper: upper})
+	})
+
+	order := t.indexes
+
+	if t.removed > 0 {
+		order = make([]int, 0, len(intervals))
+
+		for pos, idx := range t.indexes {
+			if !t.isRemoved(pos) {
+				order = append(order, shifted[idx])
+			}
+		}
+	}
+
+	return json.Marshal(jsonTree{Intervals: intervals, Order: order, Endpoints: t.endpoints})
+}
+
+// UnmarshalJSON decodes a tree previously encoded by MarshalJSON, replacing the tree contents
+// and re-augmenting the nodes in linear time. Returns an ErrInvalidEncoding wrapped error
+// if the node order is not a permutation of the interval indices.
+func (t *INTree) UnmarshalJSON(data []byte) error {
+	var jt jsonTree
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+
+	if jt.Endpoints&^Open != 0 {
+		return fmt.Errorf("%w: invalid endpoints %d", ErrInvalidEncoding, jt.Endpoints)
+	}
+
+	if len(jt.Order) != len(jt.Intervals) {
+		return fmt.Errorf("%w: %d intervals do not match an order of %d indexes", ErrInvalidEncoding, len(jt.Intervals), len(jt.Order))
+	}
+
+	indexes := jt.Order
+	if indexes == nil {
+		indexes = []int{}
+	}
+
+	limits := make([]float64, 3*len(indexes))
+
+	for pos, idx := range indexes {
+		if idx < 0 || idx >= len(jt.Intervals) {
+			return fmt.Errorf("%w: invalid index %d at position %d", ErrInvalidEncoding, idx, pos)
+		}
+
+		limits[3*pos], limits[3*pos+1] = jt.Intervals[idx].Lower, jt.Intervals[idx].Upper
+	}
+
+	if err := t.setNodes(indexes, limits); err != nil {
+		return err
+	}
+
+	augment(t.limits, t.indexes)
+	t.endpoints = jt.Endpoints
+	t.sortView()
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_JSON(t *testing.T) {
+	t.Run("Case_Roundtrip", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+
+		data, err := json.Marshal(tree)
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, json.Unmarshal(data, decoded))
+
+		assert.EqualValues(t, tree.Intervals(), decoded.Intervals())
+		for val := -1.0; val <= 106.0; val += 0.25 {
+			assert.EqualValues(t, tree.Including(val), decoded.Including(val))
+		}
+	})
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 2.0, Upper: 3.0},
+			&testBounds{Lower: 0.0, Upper: 1.5},
+		})
+
+		data, err := json.Marshal(tree)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"intervals":[[2,3],[0,1.5]],"order":[1,0]}`, string(data))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		data, err := json.Marshal(intree.NewINTree(nil))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"intervals":[],"order":[]}`, string(data))
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, json.Unmarshal([]byte(`{}`), decoded))
+		assert.EqualValues(t, 0, decoded.Len())
+		assert.Empty(t, decoded.Including(4.3))
+	})
+	t.Run("Case_Border/invalid_order", func(t *testing.T) {
+		for _, data := range []string{
+			`{"intervals":[[0,1],[2,3]],"order":[0]}`,
+			`{"intervals":[[0,1],[2,3]],"order":[0,2]}`,
+			`{"intervals":[[0,1],[2,3]],"order":[1,1]}`,
+			`{"intervals":[[0,1],[2,3]],"order":[-1,0]}`,
+		} {
+			err := json.Unmarshal([]byte(data), &intree.INTree{})
+			assert.True(t, errors.Is(err, intree.ErrInvalidEncoding), data)
+		}
+	})
+}
+
+func Test_IntervalSet_JSON(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		var set intree.IntervalSet
+		assert.NoError(t, json.Unmarshal([]byte(`[[0,2],[1,3.5],[5,6]]`), &set))
+		assert.EqualValues(t, intree.IntervalSet{{Lower: 0, Upper: 2}, {Lower: 1, Upper: 3.5}, {Lower: 5, Upper: 6}}, set)
+
+		assert.ElementsMatch(t, []int{0, 1}, set.Tree().Including(1.5))
+
+		data, err := json.Marshal(set)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[[0,2],[1,3.5],[5,6]]`, string(data))
+	})
+	t.Run("Case_Border/invalid_interval", func(t *testing.T) {
+		var set intree.IntervalSet
+		assert.Error(t, json.Unmarshal([]byte(`[[0,"a"]]`), &set))
+	})
+}
@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+// eytzinger is a breadth first (Eytzinger) copy of the tree nodes, laid out as a complete binary tree where
+// the children of the node at slot k sit at slots 2k+1 and 2k+2. Descents touch the top levels of the tree,
+// packed at the start of the Slice, far more often than in the in-order layout, improving cache locality on
+// large trees at the cost of storing the nodes twice.
+type eytzinger struct {
+	// limits holds the lower, upper and augmented limits of the node at every slot
+	limits []float64
+	// positions maps every slot to its in-order node position
+	positions []int
+}
+
+// newEytzinger is an internal utility function, laying out the given lower ordered node limits in Eytzinger
+// order and augmenting them bottom-up.
+func newEytzinger(limits []float64) *eytzinger {
+	n := len(limits) / 3
+	e := &eytzinger{limits: make([]float64, 3*n), positions: make([]int, n)}
+
+	pos := 0
+	e.fill(0, limits, &pos)
+
+	for k := n - 1; k >= 0; k-- {
+		e.limits[3*k+2] = e.limits[3*k+1]
+
+		for _, child := range [2]int{2*k + 1, 2*k + 2} {
+			if child < n {
+				e.limits[3*k+2] = maxLimit(e.limits[3*k+2], e.limits[3*child+2])
+			}
+		}
+	}
+
+	return e
+}
+
+// fill is an internal utility function, assigning in-order nodes to the slots of the subtree rooted at
+// slot k, so that the complete tree keeps the nodes sorted by lower limit.
+func (e *eytzinger) fill(k int, limits []float64, pos *int) {
+	if k >= len(e.positions) {
+		return
+	}
+
+	e.fill(2*k+1, limits, pos)
+
+	e.positions[k] = *pos
+	e.limits[3*k], e.limits[3*k+1] = limits[3**pos], limits[3**pos+1]
+	*pos++
+
+	e.fill(2*k+2, limits, pos)
+}
+
+// traverse is the Eytzinger counterpart of traverseClosed; calls fn with the in-order position of every
+// node overlapping with the given range, limits included, stopping as soon as fn returns false.
+func (e *eytzinger) traverse(lower, upper float64, fn func(pos int) bool) {
+	n := len(e.positions)
+	if n == 0 {
+		return
+	}
+
+	var stock [stockSize]int
+	slotStock := append(stock[:0], 0)
+
+	for len(slotStock) > 0 {
+		k := slotStock[len(slotStock)-1]
+		slotStock = slotStock[:len(slotStock)-1]
+
+		node := (*[3]float64)(e.limits[3*k:])
+
+		// Prune the subtree if no upper limit in it reaches the range
+		if lower > node[2] {
+			continue
+		}
+
+		if left := 2*k + 1; left < n {
+			slotStock = append(slotStock, left)
+		}
+
+		if node[0] <= upper {
+			if right := 2*k + 2; right < n {
+				slotStock = append(slotStock, right)
+			}
+
+			if lower <= node[1] && !fn(e.positions[k]) {
+				return
+			}
+		}
+	}
+}
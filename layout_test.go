@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_EytzingerLayout(t *testing.T) {
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		for _, n := range []int{1, 2, 3, 7, 100, 1023, 1024, 5000} {
+			bounds := randomBounds(n, 1000.0, 20.0)
+			tree := intree.NewINTree(bounds, intree.WithEytzingerLayout())
+			reference := intree.NewINTree(bounds)
+
+			for val := -1.0; val <= 1001.0; val += 3.7 {
+				assert.ElementsMatch(t, reference.Including(val), tree.Including(val), "n = %d at %.1f", n, val)
+				assert.ElementsMatch(t, reference.Overlapping(val, val+5.0), tree.Overlapping(val, val+5.0))
+			}
+		}
+	})
+	t.Run("Case_Updates", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithEytzingerLayout())
+		reference := intree.NewINTree(exampleBounds())
+
+		for _, tr := range []*intree.INTree{tree, reference} {
+			tr.Insert(&testBounds{Lower: 4.0, Upper: 4.5})
+			assert.NoError(t, tr.Delete(0))
+			tr.Add(&testBounds{Lower: 10.0, Upper: 12.0})
+			tr.Rebuild()
+		}
+
+		for val := 0.0; val <= 12.0; val += 0.1 {
+			assert.ElementsMatch(t, reference.Including(val), tree.Including(val), "at %.1f", val)
+		}
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 1.0, Upper: 2.0},
+		}, intree.WithEytzingerLayout(), intree.WithClosedOpenSemantics())
+
+		assert.EqualValues(t, []int{1}, tree.Including(1.0))
+	})
+	t.Run("Case_Zero_allocs", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0), intree.WithEytzingerLayout())
+
+		count := 0
+		allocs := testing.AllocsPerRun(100, func() {
+			count += tree.CountIncluding(500.0)
+		})
+
+		assert.EqualValues(t, 0, allocs)
+		assert.NotZero(t, count)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil, intree.WithEytzingerLayout())
+		assert.Empty(t, tree.Including(1.0))
+	})
+}
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRecord is returned by LoadCSV when a record cannot be mapped to an interval.
+var ErrInvalidRecord = errors.New("intree: invalid record")
+
+// LoadOptions configures how LoadCSV maps records to intervals. The zero value reads comma separated
+// lower,upper pairs from the first two columns of every record.
+type LoadOptions struct {
+	// Comma is the field delimiter, defaulting to ','. Use '\t' for TSV input.
+	Comma rune
+	// Comment, if set, is the leading character of records to ignore.
+	Comment rune
+	// Header skips the first record, which holds the column names.
+	Header bool
+	// LowerColumn and UpperColumn are the zero based columns of the limits. When both are zero, the first two
+	// columns are used.
+	LowerColumn, UpperColumn int
+	// LowerName and UpperName, if set, map the limit columns by header name instead, overriding their
+	// column counterparts. Require Header.
+	LowerName, UpperName string
+}
+
+// LoadCSV reads intervals from the CSV (or TSV) records of the given reader, in record order, mapping the limit
+// columns as configured by opts. Limits are parsed as floating point numbers; extra columns are ignored.
+// Returns an ErrInvalidRecord wrapped error describing the first record that cannot be mapped, if any.
+// The loaded intervals are not validated; see NewINTreeChecked.
+func LoadCSV(r io.Reader, opts LoadOptions) ([]Bounds, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+	cr.Comment = opts.Comment
+
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+
+	lowerColumn, upperColumn := opts.LowerColumn, opts.UpperColumn
+	if lowerColumn == 0 && upperColumn == 0 {
+		upperColumn = 1
+	}
+
+	if lowerColumn < 0 || upperColumn < 0 {
+		return nil, fmt.Errorf("%w: negative column %d:%d", ErrInvalidRecord, lowerColumn, upperColumn)
+	}
+
+	if (opts.LowerName != "" || opts.UpperName != "") && !opts.Header {
+		return nil, fmt.Errorf("%w: column names require a header", ErrInvalidRecord)
+	}
+
+	bounds := []Bounds{}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return bounds, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		if opts.Header {
+			opts.Header = false
+
+			if lowerColumn, err = headerColumn(record, opts.LowerName, lowerColumn, line); err != nil {
+				return nil, err
+			}
+
+			if upperColumn, err = headerColumn(record, opts.UpperName, upperColumn, line); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if len(record) <= max(lowerColumn, upperColumn) {
+			return nil, fmt.Errorf("%w: line %d has %d fields, lower and upper columns are %d and %d",
+				ErrInvalidRecord, line, len(record), lowerColumn, upperColumn)
+		}
+
+		lower, err := strconv.ParseFloat(strings.TrimSpace(record[lowerColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d lower limit: %w", ErrInvalidRecord, line, err)
+		}
+
+		upper, err := strconv.ParseFloat(strings.TrimSpace(record[upperColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d upper limit: %w", ErrInvalidRecord, line, err)
+		}
+
+		bounds = append(bounds, Interval{Lower: lower, Upper: upper})
+	}
+}
+
+// headerColumn is an internal utility function, resolving the column of the given name in the header record,
+// or the given column if the name is empty.
+func headerColumn(record []string, name string, column, line int) (int, error) {
+	if name == "" {
+		return column, nil
+	}
+
+	idx := slices.IndexFunc(record, func(field string) bool {
+		return strings.TrimSpace(field) == name
+	})
+	if idx < 0 {
+		return 0, fmt.Errorf("%w: line %d header has no %q column", ErrInvalidRecord, line, name)
+	}
+
+	return idx, nil
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadCSV(t *testing.T) {
+	t.Run("Case_Default", func(t *testing.T) {
+		bounds, err := intree.LoadCSV(strings.NewReader("0,2\n5, 6,extra\n1.5,3\n"), intree.LoadOptions{})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []intree.Bounds{
+			intree.Interval{Lower: 0, Upper: 2},
+			intree.Interval{Lower: 5, Upper: 6},
+			intree.Interval{Lower: 1.5, Upper: 3},
+		}, bounds)
+	})
+	t.Run("Case_TSV_columns", func(t *testing.T) {
+		input := "# exported slots\nname\tstart\tend\nfirst\t1\t4\nsecond\t2\t3\n"
+		bounds, err := intree.LoadCSV(strings.NewReader(input), intree.LoadOptions{
+			Comma:       '\t',
+			Comment:     '#',
+			Header:      true,
+			LowerColumn: 1,
+			UpperColumn: 2,
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []intree.Bounds{intree.Interval{Lower: 1, Upper: 4}, intree.Interval{Lower: 2, Upper: 3}}, bounds)
+	})
+	t.Run("Case_Column_names", func(t *testing.T) {
+		input := "end,name,start\n4,first,1\n3,second,2\n"
+		bounds, err := intree.LoadCSV(strings.NewReader(input), intree.LoadOptions{
+			Header:    true,
+			LowerName: "start",
+			UpperName: "end",
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, []intree.Bounds{intree.Interval{Lower: 1, Upper: 4}, intree.Interval{Lower: 2, Upper: 3}}, bounds)
+
+		tree := intree.NewINTree(bounds)
+		assert.EqualValues(t, []int{0}, tree.Including(3.5))
+	})
+	t.Run("Case_Border/empty", func(t *testing.T) {
+		bounds, err := intree.LoadCSV(strings.NewReader(""), intree.LoadOptions{Header: true})
+		assert.NoError(t, err)
+		assert.Empty(t, bounds)
+	})
+	t.Run("Case_Border/invalid_records", func(t *testing.T) {
+		for _, tc := range []struct {
+			input string
+			opts  intree.LoadOptions
+		}{
+			{"0,2\n5\n", intree.LoadOptions{}},
+			{"0,x\n", intree.LoadOptions{}},
+			{"x,0\n", intree.LoadOptions{}},
+			{"0,\"2\n", intree.LoadOptions{}},
+			{"0,2\n", intree.LoadOptions{LowerColumn: -1}},
+			{"0,2\n", intree.LoadOptions{LowerName: "lower"}},
+			{"lower,high\n0,2\n", intree.LoadOptions{Header: true, LowerName: "lower", UpperName: "upper"}},
+		} {
+			_, err := intree.LoadCSV(strings.NewReader(tc.input), tc.opts)
+			assert.ErrorIs(t, err, intree.ErrInvalidRecord, "input %q", tc.input)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Merge coalesces overlapping or adjacent (touching) intervals of the given bounds, returning the resulting
+// intervals sorted by lower limit. Useful as a preprocessing step for coverage indexes.
+func Merge(bounds []Bounds) []Bounds {
+	merged, _ := MergeWithSources(bounds)
+
+	return merged
+}
+
+// MergeWithSources is the source mapping counterpart of Merge;
+// also returns, for each merged interval, the ascending indices of the given bounds it was merged from.
+func MergeWithSources(bounds []Bounds) (merged []Bounds, sources [][]int) {
+	order := make([]int, len(bounds))
+	limits := make([][2]float64, len(bounds))
+
+	for i, b := range bounds {
+		order[i] = i
+		limits[i][0], limits[i][1] = b.Limits()
+	}
+
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(limits[i][0], limits[j][0])
+	})
+
+	merged, sources = []Bounds{}, [][]int{}
+
+	var current Interval
+
+	for n, idx := range order {
+		lower, upper := limits[idx][0], limits[idx][1]
+
+		if n > 0 && lower <= current.Upper {
+			current.Upper = max(current.Upper, upper)
+			sources[len(sources)-1] = append(sources[len(sources)-1], idx)
+
+			continue
+		}
+
+		if n > 0 {
+			merged = append(merged, current)
+		}
+
+		current = Interval{Lower: lower, Upper: upper}
+		sources = append(sources, []int{idx})
+	}
+
+	if len(order) > 0 {
+		merged = append(merged, current)
+	}
+
+	for _, s := range sources {
+		slices.Sort(s)
+	}
+
+	return merged, sources
+}
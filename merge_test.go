@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Merge(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		merged, sources := intree.MergeWithSources([]intree.Bounds{
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 1.0, Upper: 3.0},
+			&testBounds{Lower: 3.0, Upper: 4.0}, // adjacent to [1, 3]
+			&testBounds{Lower: 8.0, Upper: 9.0},
+			&testBounds{Lower: 5.5, Upper: 5.7}, // contained in [5, 6]
+		})
+
+		assert.EqualValues(t, []intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 4.0},
+			intree.Interval{Lower: 5.0, Upper: 6.0},
+			intree.Interval{Lower: 8.0, Upper: 9.0},
+		}, merged)
+		assert.EqualValues(t, [][]int{{1, 2, 3}, {0, 5}, {4}}, sources)
+	})
+	t.Run("Case_Example/tree", func(t *testing.T) {
+		merged := intree.Merge(exampleBounds())
+		tree := intree.NewINTree(merged)
+		reference := intree.NewINTree(exampleBounds())
+
+		// Merged intervals do not overlap, so each value matches at most one of them
+		for val := 0.0; val <= 10.0; val += 0.1 {
+			matches := tree.Including(val)
+			assert.LessOrEqual(t, len(matches), 1)
+			assert.EqualValues(t, len(reference.Including(val)) > 0, len(matches) == 1, "at %.1f", val)
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		merged, sources := intree.MergeWithSources(nil)
+		assert.Empty(t, merged)
+		assert.Empty(t, sources)
+	})
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+// MetadataFor returns the annotation of the interval at the given original index, as given to WithMetadata
+// or SetMetadata. Returns false if the interval has no annotation or is not stored in the tree.
+// Annotations follow the index shifts of Compact.
+func (t *INTree) MetadataFor(index int) (any, bool) {
+	if pos := t.position(index); pos < 0 || t.isRemoved(pos) {
+		return nil, false
+	}
+
+	md, ok := t.metadata[index]
+
+	return md, ok
+}
+
+// SetMetadata sets the annotation of the interval at the given original index, replacing any previous one.
+// A nil annotation clears it. Returns ErrIndexOutOfRange if the index is not stored in the tree or was removed.
+func (t *INTree) SetMetadata(index int, md any) error {
+	if pos := t.position(index); pos < 0 || t.isRemoved(pos) {
+		return ErrIndexOutOfRange
+	}
+
+	if md == nil {
+		delete(t.metadata, index)
+		return nil
+	}
+
+	if t.metadata == nil {
+		t.metadata = map[int]any{}
+	}
+
+	t.metadata[index] = md
+
+	return nil
+}
+
+// newMetadata is an internal utility function, copying the given annotations of the intervals of a tree
+// of n intervals, ignoring indices out of range. Returns nil if no annotation is given.
+func newMetadata(metadata map[int]any, n int) map[int]any {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	result := make(map[int]any, len(metadata))
+
+	for idx, md := range metadata {
+		if idx >= 0 && idx < n {
+			result[idx] = md
+		}
+	}
+
+	return result
+}
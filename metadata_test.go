@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_MetadataFor(t *testing.T) {
+	t.Run("Case_Construction", func(t *testing.T) {
+		metadata := map[int]any{0: "first", 2: 42, 100: "out of range"}
+		tree := intree.NewINTree(exampleBounds(), intree.WithMetadata(metadata))
+
+		md, ok := tree.MetadataFor(0)
+		assert.True(t, ok)
+		assert.EqualValues(t, "first", md)
+
+		md, ok = tree.MetadataFor(2)
+		assert.True(t, ok)
+		assert.EqualValues(t, 42, md)
+
+		_, ok = tree.MetadataFor(1)
+		assert.False(t, ok)
+		_, ok = tree.MetadataFor(100)
+		assert.False(t, ok)
+
+		// The given map is copied
+		metadata[1] = "late"
+		_, ok = tree.MetadataFor(1)
+		assert.False(t, ok)
+	})
+	t.Run("Case_Streamed", func(t *testing.T) {
+		tree := intree.NewINTreeFrom(slices.Values(exampleBounds()), intree.WithMetadata(map[int]any{3: "streamed"}))
+
+		md, ok := tree.MetadataFor(3)
+		assert.True(t, ok)
+		assert.EqualValues(t, "streamed", md)
+	})
+	t.Run("Case_SetMetadata", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		idx := tree.Insert(intree.Interval{Lower: 1.0, Upper: 2.0})
+
+		assert.NoError(t, tree.SetMetadata(idx, "inserted"))
+		md, ok := tree.MetadataFor(idx)
+		assert.True(t, ok)
+		assert.EqualValues(t, "inserted", md)
+
+		assert.NoError(t, tree.SetMetadata(idx, nil))
+		_, ok = tree.MetadataFor(idx)
+		assert.False(t, ok)
+
+		assert.ErrorIs(t, tree.SetMetadata(-1, "invalid"), intree.ErrIndexOutOfRange)
+		assert.ErrorIs(t, tree.SetMetadata(tree.Len(), "invalid"), intree.ErrIndexOutOfRange)
+	})
+	t.Run("Case_IndexShifts", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithMetadata(map[int]any{0: "a", 1: "b", 2: "c", 3: "d"}))
+
+		// Deleted indices stay taken until compacted
+		assert.NoError(t, tree.Delete(1))
+		_, ok := tree.MetadataFor(1)
+		assert.False(t, ok)
+		md, _ := tree.MetadataFor(2)
+		assert.EqualValues(t, "c", md)
+
+		assert.NoError(t, tree.Remove(0))
+		_, ok = tree.MetadataFor(0)
+		assert.False(t, ok)
+		assert.ErrorIs(t, tree.SetMetadata(0, "removed"), intree.ErrIndexOutOfRange)
+
+		assert.True(t, tree.Compact())
+		md, _ = tree.MetadataFor(0)
+		assert.EqualValues(t, "c", md)
+		md, _ = tree.MetadataFor(1)
+		assert.EqualValues(t, "d", md)
+	})
+	t.Run("Case_Clone", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithMetadata(map[int]any{0: "a"}))
+		clone := tree.Clone()
+
+		assert.NoError(t, tree.SetMetadata(0, "changed"))
+		md, _ := clone.MetadataFor(0)
+		assert.EqualValues(t, "a", md)
+	})
+}
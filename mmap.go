@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"fmt"
+	"strconv"
+	"unsafe"
+)
+
+// nativeLayout reports whether the in-memory representation of int Slices matches the 8 bytes encoded indexes,
+// so that nodes encoded in the platform byte order can be used in place.
+var nativeLayout = strconv.IntSize == 64
+
+// MappedINTree is a read-only tree whose nodes are memory mapped from a file written with MarshalBinary,
+// letting several processes share a single copy of a large index. The file must not be modified while mapped,
+// and updates copy the nodes to the heap first.
+// The tree must not be used after Close.
+type MappedINTree struct {
+	*INTree
+	data []byte
+}
+
+// Close unmaps the tree file. Closing an already closed tree is a no-op.
+func (m *MappedINTree) Close() error {
+	if m.data == nil {
+		return nil
+	}
+
+	err := unmapFile(m.data)
+	m.data = nil
+
+	return err
+}
+
+// OpenMMap maps the given file, previously written with MarshalBinary or MarshalBinaryOrder, and uses it
+// as the tree nodes without copying them. Only the reverse index mapping is built in memory, and nodes are
+// validated as on UnmarshalBinary. Platforms without memory mapping support or whose layout differs from the
+// encoding byte order fall back to decoding a copy of the file. Returns an ErrInvalidEncoding wrapped error if the file is not a valid tree encoding.
+func OpenMMap(path string) (*MappedINTree, error) {
+	data, mapped, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("intree: mapping %s: %w", path, err)
+	}
+
+	m := &MappedINTree{INTree: &INTree{}, data: data}
+	if !mapped {
+		m.data = nil
+	}
+
+	h, err := decodeHeader(data)
+
+	switch {
+	case err != nil:
+	case mapped && nativeLayout && h.orderFlag == nativeOrderFlag:
+		indexes, limits := viewNodes(data[headerSize:], h.nodes)
+		err = m.setDecodedNodes(h, indexes, limits)
+		m.readOnly = true
+	default:
+		err = m.UnmarshalBinary(data)
+
+		if cerr := m.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	if err != nil {
+		_ = m.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// viewNodes is an internal utility function, reinterpreting encoded nodes as the indexes and limits Slices.
+// The data must be 8 bytes aligned and hold the given number of nodes in the native layout.
+func viewNodes(data []byte, nodes int) ([]int, []float64) {
+	if nodes == 0 {
+		return []int{}, []float64{}
+	}
+
+	indexes := unsafe.Slice((*int)(unsafe.Pointer(&data[0])), nodes)
+	limits := unsafe.Slice((*float64)(unsafe.Pointer(&data[8*nodes])), 3*nodes)
+
+	return indexes, limits
+}
@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTree(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "tree.bin")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func Test_Tree_OpenMMap(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		mapped, err := intree.OpenMMap(writeTree(t, data))
+		assert.NoError(t, err)
+		defer mapped.Close()
+
+		assert.EqualValues(t, tree.Len(), mapped.Len())
+		assert.EqualValues(t, tree.Intervals(), mapped.Intervals())
+		for val := -1.0; val <= 106.0; val += 0.25 {
+			assert.EqualValues(t, tree.Including(val), mapped.Including(val))
+		}
+	})
+	t.Run("Case_Example/updates", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		mapped, err := intree.OpenMMap(writeTree(t, data))
+		assert.NoError(t, err)
+		defer mapped.Close()
+
+		// Updates must not write into the read-only mapping
+		assert.NoError(t, mapped.Delete(0))
+		assert.ElementsMatch(t, []int{2, 5, 8, 10}, mapped.Including(4.3))
+
+		index := mapped.Insert(&testBounds{Lower: 4.0, Upper: 5.0})
+		assert.EqualValues(t, 13, index)
+		assert.ElementsMatch(t, []int{2, 5, 8, 10, 13}, mapped.Including(4.3))
+	})
+	t.Run("Case_Example/removed", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.NoError(t, tree.Remove(2))
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		mapped, err := intree.OpenMMap(writeTree(t, data))
+		assert.NoError(t, err)
+		defer mapped.Close()
+
+		assert.EqualValues(t, 1, mapped.Removed())
+		assert.ElementsMatch(t, []int{0, 5, 8, 10}, mapped.Including(4.3))
+		assert.ErrorIs(t, mapped.Remove(2), intree.ErrIndexOutOfRange)
+		assert.NoError(t, mapped.CheckInvariants())
+	})
+	t.Run("Case_Example/big_endian", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+		data, err := tree.MarshalBinaryOrder(binary.BigEndian)
+		assert.NoError(t, err)
+
+		mapped, err := intree.OpenMMap(writeTree(t, data))
+		assert.NoError(t, err)
+		defer mapped.Close()
+
+		assert.EqualValues(t, tree.Intervals(), mapped.Intervals())
+		for val := -1.0; val <= 106.0; val += 0.25 {
+			assert.EqualValues(t, tree.Including(val), mapped.Including(val))
+		}
+	})
+	t.Run("Case_Border/close_twice", func(t *testing.T) {
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+
+		mapped, err := intree.OpenMMap(writeTree(t, data))
+		assert.NoError(t, err)
+		assert.NoError(t, mapped.Close())
+		assert.NoError(t, mapped.Close())
+	})
+	t.Run("Case_Border/invalid_file", func(t *testing.T) {
+		_, err := intree.OpenMMap(writeTree(t, []byte{}))
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+
+		_, err = intree.OpenMMap(writeTree(t, []byte{0xff, 0, 0}))
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+
+		data, err := intree.NewINTree(exampleBounds()).MarshalBinary()
+		assert.NoError(t, err)
+		binary.LittleEndian.PutUint64(data[24+8*13+8:], math.Float64bits(100.0))
+
+		_, err = intree.OpenMMap(writeTree(t, data))
+		assert.True(t, errors.Is(err, intree.ErrCorruptedTree))
+
+		_, err = intree.OpenMMap(filepath.Join(t.TempDir(), "missing.bin"))
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+}
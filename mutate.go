@@ -0,0 +1,512 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrIndexOutOfRange is returned by updates given an original index not stored in the tree.
+var ErrIndexOutOfRange = errors.New("intree: index out of range")
+
+// minBuffered is the number of intervals Insert buffers before merging them into the sorted nodes,
+// whatever the tree length.
+const minBuffered = 32
+
+// Insert adds the given interval to the tree, returning its original index: the next available one.
+// On valued trees its value is stored as well (nil if it is not a ValuedBounds). The interval is appended to a
+// buffer scanned by searches, which is merged into the sorted nodes in O(n) time once it outgrows the square root
+// of the tree length, so insertions take O(sqrt(n)) amortized time. Until then, operations relying on the node
+// order (coverage, nearest searches, sweeps and diagnostics) work on a merged copy; Rebuild merges it right away.
+// Trees of a custom node order place the interval past it instead, and are re-augmented in O(n) time.
+// Updates must not run concurrently with queries.
+func (t *INTree) Insert(b Bounds) int {
+	t.ensureWritable()
+
+	index := len(t.indexes)
+	lower, upper := t.store(b)
+
+	if t.unordered {
+		t.insertNode(index, lower, upper)
+		t.reaugment()
+
+		return index
+	}
+
+	t.indexes = append(t.indexes, index)
+	t.limits = append(t.limits, lower, upper, 0)
+	t.positions = append(t.positions, index)
+	t.buffered++
+
+	if t.priorityMax != nil {
+		t.priorityMax = append(t.priorityMax, 0)
+	}
+
+	if t.buffered > max(minBuffered, int(math.Sqrt(float64(t.treeLen())))) {
+		t.flush()
+	}
+
+	return index
+}
+
+// Add stages the given intervals, without making them visible to searches until Rebuild is called.
+// Staged intervals are not encoded by MarshalBinary.
+func (t *INTree) Add(bounds ...Bounds) {
+	t.staged = append(t.staged, bounds...)
+}
+
+// Rebuild adds every staged interval to the tree at once, assigning them the next available original
+// indices in staging order, and merges the intervals buffered by Insert along with them. Only the staged and
+// buffered nodes are sorted before merging them into the stored ones, so a rebuild takes O(n + k log k) time
+// for k staged and buffered intervals. Must not run concurrently with queries.
+func (t *INTree) Rebuild() {
+	if len(t.staged) == 0 && t.buffered == 0 {
+		return
+	}
+
+	t.ensureWritable()
+
+	n, k := len(t.indexes), len(t.staged)
+	indexes := make([]int, k, k+t.buffered)
+	limits := make([]float64, 3*k, 3*(k+t.buffered))
+
+	for i, b := range t.staged {
+		indexes[i] = n + i
+		limits[3*i], limits[3*i+1] = t.store(b)
+	}
+
+	if t.unordered {
+		// Staged nodes follow the custom order
+		t.indexes, t.limits = append(t.indexes, indexes...), append(t.limits, limits...)
+	} else {
+		m := t.treeLen()
+		indexes, limits = append(indexes, t.indexes[m:]...), append(limits, t.limits[3*m:]...)
+
+		sort(limits, indexes, nil)
+		t.indexes, t.limits = mergeNodes(t.indexes[:m], t.limits[:3*m], indexes, limits)
+		t.buffered = 0
+	}
+
+	t.staged = nil
+	t.reaugment()
+}
+
+// Delete removes the interval at the given original index from the tree as Remove does, leaving a tombstone
+// so that the following indices stay stable, in O(log n) time (O(n) on trees with an Eytzinger layout).
+// The index counts towards Len until Compact drops it. Returns ErrIndexOutOfRange if the index is not stored
+// in the tree or was already removed. Updates must not run concurrently with queries.
+func (t *INTree) Delete(index int) error {
+	return t.Remove(index)
+}
+
+// InsertCoalesce inserts the given interval, merging it with every stored interval separated from it
+// by a gap no larger than gapTolerance (overlapping intervals always merge; merging is transitive).
+// A merged interval keeps the lowest original index among the intervals it absorbed, along with its value;
+// the other absorbed indices are removed and the following ones shift down to stay contiguous. An interval
+// that merges with nothing is appended with the next available index, along with its value on valued trees
+// (nil if it is not a ValuedBounds). Retained bounds of merged intervals are replaced by their new limits.
+// A negative gapTolerance is treated as zero. Updates take O(n) time and must not run concurrently with queries.
+func (t *INTree) InsertCoalesce(b Bounds, gapTolerance float64) {
+	t.ensureWritable()
+	t.flush()
+
+	lower, upper := b.Limits()
+	gapTolerance = math.Max(gapTolerance, 0)
+
+	merged := make([]bool, len(t.indexes))
+	mergedIdx := []int{}
+
+	for found := true; found; {
+		found = false
+
+		// Touching intervals always merge, whatever the tree endpoints
+		t.traverseClosed(lower-gapTolerance, upper+gapTolerance, func(pos int) bool {
+			if merged[pos] {
+				return true
+			}
+
+			merged[pos] = true
+			mergedIdx = append(mergedIdx, t.indexes[pos])
+			lower = math.Min(lower, t.limits[3*pos])
+			upper = math.Max(upper, t.limits[3*pos+1])
+			found = true
+
+			return true
+		})
+	}
+
+	if len(mergedIdx) == 0 {
+		t.Insert(b)
+		return
+	}
+
+	keep := mergedIdx[0]
+	for _, idx := range mergedIdx {
+		if idx < keep {
+			keep = idx
+		}
+	}
+
+	removed := make([]bool, len(t.indexes))
+	for _, idx := range mergedIdx {
+		removed[idx] = idx != keep
+	}
+
+	if t.bounds != nil {
+		mergedBounds := limitBounds{lower: lower, upper: upper}
+		if t.values != nil {
+			mergedBounds.value = t.values[keep]
+		}
+
+		t.bounds[keep] = mergedBounds
+	}
+
+	t.removeNodes(merged)
+	shift := t.compactIndexes(removed)
+	t.insertNode(keep-shift[keep], lower, upper)
+	t.reaugment()
+}
+
+// UpdateBounds sets the limits of the interval at the given original index, keeping its index, value and
+// retained bounds (replaced with the new limits), so that growing intervals such as ongoing sessions do not
+// require rebuilding the tree. While the new lower limit keeps the node order, the limits are changed in place
+// and the augmented limits along the node path repaired in O(log n) time; otherwise the node moves to its
+// sorted position in O(n) time. Trees with an Eytzinger layout rebuild it in O(n) time, and trees of a custom
+// order their lower ordered view in O(n log n) time.
+// Returns ErrIndexOutOfRange if the index is not stored in the tree or was removed, and an ErrInvalidBounds
+// wrapped error if the limits are NaN or infinite, or lo is greater than hi.
+// Updates must not run concurrently with queries.
+func (t *INTree) UpdateBounds(index int, lo, hi float64) error {
+	pos := t.position(index)
+	if pos < 0 || t.isRemoved(pos) {
+		return ErrIndexOutOfRange
+	}
+
+	if err := checkBounds(index, Interval{Lower: lo, Upper: hi}); err != nil {
+		return err
+	}
+
+	t.ensureWritable()
+
+	if t.bounds != nil {
+		updated := limitBounds{lower: lo, upper: hi}
+		if t.values != nil {
+			updated.value = t.values[index]
+		}
+
+		t.bounds[index] = updated
+	}
+
+	// Nodes buffered by Insert are not sorted yet
+	n := t.treeLen()
+	inOrder := t.unordered || pos >= n ||
+		(pos == 0 || t.limits[3*pos-3] <= lo) && (pos == n-1 || lo <= t.limits[3*pos+3])
+
+	if inOrder {
+		t.limits[3*pos], t.limits[3*pos+1] = lo, hi
+		t.repairPath(pos, t.repairLimit)
+
+		if t.layout != nil {
+			t.layout = newEytzinger(t.limits[:3*n])
+		}
+
+		t.sortView()
+
+		return nil
+	}
+
+	t.flush()
+	pos = t.positions[index]
+
+	flagged := make([]bool, len(t.indexes))
+	flagged[pos] = true
+
+	t.removeNodes(flagged)
+	t.insertNode(index, lo, hi)
+	t.reaugment()
+
+	return nil
+}
+
+// ensureWritable is an internal utility function, copying the nodes of read-only trees (such as memory mapped
+// ones) to the heap before updating them.
+func (t *INTree) ensureWritable() {
+	if !t.readOnly {
+		return
+	}
+
+	t.indexes = append([]int(nil), t.indexes...)
+	t.limits = append([]float64(nil), t.limits...)
+	t.readOnly = false
+}
+
+// store is an internal utility function, appending the value and retained bounds of a new interval
+// according to the tree settings. Returns the interval limits.
+func (t *INTree) store(b Bounds) (lower, upper float64) {
+	lower, upper = b.Limits()
+
+	if t.values != nil {
+		vb, ok := b.(ValuedBounds)
+		if !ok {
+			vb = limitBounds{lower: lower, upper: upper}
+		}
+
+		t.values = append(t.values, vb.Value())
+		b = vb
+	}
+
+	if t.bounds != nil {
+		t.bounds = append(t.bounds, b)
+	}
+
+	if t.tombstones != nil {
+		t.tombstones = append(t.tombstones, false)
+	}
+
+	if t.priorities != nil {
+		t.priorities = append(t.priorities, 0)
+	}
+
+	return lower, upper
+}
+
+// flush is an internal utility function, merging the nodes buffered by Insert into the sorted ones
+// in O(n + k log k) time for k buffered nodes, and re-augmenting the tree.
+func (t *INTree) flush() {
+	if t.buffered == 0 {
+		return
+	}
+
+	m := t.treeLen()
+	indexes, limits := slices.Clone(t.indexes[m:]), slices.Clone(t.limits[3*m:])
+
+	sort(limits, indexes, nil)
+	t.indexes, t.limits = mergeNodes(t.indexes[:m], t.limits[:3*m], indexes, limits)
+	t.buffered = 0
+	t.reaugment()
+}
+
+// merged is an internal utility function, returning the tree itself if no node is buffered by Insert, or
+// a copy of it with the buffered nodes merged into the sorted ones otherwise. Every other field is shared.
+func (t *INTree) merged() *INTree {
+	if t.buffered == 0 {
+		return t
+	}
+
+	tree := *t
+	tree.layout = nil
+	tree.flush()
+
+	return &tree
+}
+
+// scanBuffered is an internal utility function, calling fn with the position of every node buffered by Insert
+// from the given position on that overlaps with the given closed range, in ascending position order.
+// Returns false as soon as fn does.
+func (t *INTree) scanBuffered(from int, lower, upper float64, fn func(pos int) bool) bool {
+	for pos := max(from, t.treeLen()); pos < len(t.indexes); pos++ {
+		if t.limits[3*pos] <= upper && lower <= t.limits[3*pos+1] && !fn(pos) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeNodes is an internal utility function, merging two runs of nodes sorted by lower limit into a new one.
+// The resulting nodes must be re-augmented afterwards.
+func mergeNodes(indexesA []int, limitsA []float64, indexesB []int, limitsB []float64) ([]int, []float64) {
+	indexes := make([]int, 0, len(indexesA)+len(indexesB))
+	limits := make([]float64, 0, len(limitsA)+len(limitsB))
+
+	a, b := 0, 0
+	for a < len(indexesA) || b < len(indexesB) {
+		if b == len(indexesB) || (a < len(indexesA) && limitsA[3*a] <= limitsB[3*b]) {
+			indexes = append(indexes, indexesA[a])
+			limits = append(limits, limitsA[3*a:3*a+3]...)
+			a++
+		} else {
+			indexes = append(indexes, indexesB[b])
+			limits = append(limits, limitsB[3*b:3*b+3]...)
+			b++
+		}
+	}
+
+	return indexes, limits
+}
+
+// insertNode is an internal utility function, placing a new node at its sorted position, or past the custom order
+// of unordered trees. The tree must be re-augmented afterwards.
+func (t *INTree) insertNode(index int, lower, upper float64) {
+	l := len(t.indexes)
+	if !t.unordered {
+		l = t.searchLower(lower)
+	}
+
+	t.indexes = append(t.indexes, 0)
+	copy(t.indexes[l+1:], t.indexes[l:])
+	t.indexes[l] = index
+
+	t.limits = append(t.limits, 0, 0, 0)
+	copy(t.limits[3*l+3:], t.limits[3*l:])
+	t.limits[3*l], t.limits[3*l+1], t.limits[3*l+2] = lower, upper, 0
+}
+
+// removeNodes is an internal utility function, dropping the nodes at the flagged positions while keeping
+// the remaining ones sorted. The tree must be re-augmented afterwards.
+func (t *INTree) removeNodes(flagged []bool) {
+	n := 0
+
+	for pos, idx := range t.indexes {
+		if flagged[pos] {
+			continue
+		}
+
+		t.indexes[n] = idx
+		t.limits[3*n], t.limits[3*n+1], t.limits[3*n+2] = t.limits[3*pos], t.limits[3*pos+1], t.limits[3*pos+2]
+		n++
+	}
+
+	t.indexes = t.indexes[:n]
+	t.limits = t.limits[:3*n]
+}
+
+// compactIndexes is an internal utility function, shifting down the original indices (and values, retained
+// bounds and tombstones) of the remaining nodes so that they stay contiguous once the flagged indices are removed.
+// Returns the shift applied to each original index.
+func (t *INTree) compactIndexes(removed []bool) []int {
+	shift := make([]int, len(removed))
+	count := 0
+
+	for idx, r := range removed {
+		shift[idx] = count
+		if r {
+			count++
+		}
+	}
+
+	for pos, idx := range t.indexes {
+		t.indexes[pos] = idx - shift[idx]
+	}
+
+	if t.values != nil {
+		n := 0
+
+		for idx, v := range t.values {
+			if !removed[idx] {
+				t.values[n] = v
+				n++
+			}
+		}
+
+		t.values = t.values[:n]
+	}
+
+	if t.bounds != nil {
+		n := 0
+
+		for idx, b := range t.bounds {
+			if !removed[idx] {
+				t.bounds[n] = b
+				n++
+			}
+		}
+
+		t.bounds = t.bounds[:n]
+	}
+
+	if t.tombstones != nil {
+		n := 0
+
+		for idx, dead := range t.tombstones {
+			if !removed[idx] {
+				t.tombstones[n] = dead
+				n++
+			} else if dead {
+				t.removed--
+			}
+		}
+
+		t.tombstones = t.tombstones[:n]
+	}
+
+	if t.metadata != nil {
+		metadata := make(map[int]any, len(t.metadata))
+
+		for idx, md := range t.metadata {
+			if !removed[idx] {
+				metadata[idx-shift[idx]] = md
+			}
+		}
+
+		t.metadata = metadata
+	}
+
+	if t.priorities != nil {
+		n := 0
+
+		for idx, p := range t.priorities {
+			if !removed[idx] {
+				t.priorities[n] = p
+				n++
+			}
+		}
+
+		t.priorities = t.priorities[:n]
+	}
+
+	return shift
+}
+
+// reaugment is an internal utility function, restoring the augmented limits, the reverse index
+// mapping, the subtree priorities, the Eytzinger layout and the lower ordered view, if any,
+// after the nodes were modified.
+func (t *INTree) reaugment() {
+	augment(t.limits, t.indexes)
+	t.positions = mapPositions(t.indexes)
+	t.augmentPriorities()
+
+	if t.layout != nil {
+		t.layout = newEytzinger(t.limits)
+	}
+
+	t.sortView()
+}
+
+// limitBounds is an internal ValuedBounds implementation, holding the limits of intervals resulting from updates.
+type limitBounds struct {
+	lower, upper float64
+	value        interface{}
+}
+
+// Limits accesses the interval limits.
+func (lb limitBounds) Limits() (float64, float64) {
+	return lb.lower, lb.upper
+}
+
+// Value accesses the interval value.
+func (lb limitBounds) Value() interface{} {
+	return lb.value
+}
@@ -0,0 +1,473 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_InsertCoalesce(t *testing.T) {
+	t.Run("Case_Small_gap", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+		})
+
+		tree.InsertCoalesce(&testBounds{Lower: 1.05, Upper: 2.0}, 0.1)
+
+		assert.EqualValues(t, [][2]float64{{0.0, 2.0}, {5.0, 6.0}}, tree.Intervals())
+		assert.EqualValues(t, []int{0}, tree.Including(1.5))
+	})
+	t.Run("Case_Large_gap", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+		})
+
+		tree.InsertCoalesce(&testBounds{Lower: 3.0, Upper: 4.0}, 0.5)
+
+		assert.EqualValues(t, [][2]float64{{0.0, 2.0}, {5.0, 6.0}, {3.0, 4.0}}, tree.Intervals())
+		assert.EqualValues(t, []int{2}, tree.Including(3.5))
+		assert.EqualValues(t, 0, len(tree.Including(2.5)))
+	})
+	t.Run("Case_Transitive", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 10.0, Upper: 11.0},
+			&testBounds{Lower: 3.0, Upper: 4.0},
+		})
+
+		// Bridges [0,2] and [5,6] through [3,4]; [10,11] stays apart
+		tree.InsertCoalesce(&testBounds{Lower: 2.2, Upper: 4.8}, 0.3)
+
+		assert.EqualValues(t, [][2]float64{{0.0, 6.0}, {10.0, 11.0}}, tree.Intervals())
+		assert.EqualValues(t, []int{0}, tree.Including(2.1))
+		assert.EqualValues(t, []int{1}, tree.Including(10.5))
+	})
+	t.Run("Case_Valued", func(t *testing.T) {
+		tree := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 5.0, Upper: 6.0, value: 1},
+			&valuedTestBounds{Lower: 0.0, Upper: 1.0, value: 2},
+			&valuedTestBounds{Lower: 1.2, Upper: 2.0, value: 3},
+		})
+
+		tree.InsertCoalesce(&testBounds{Lower: 0.9, Upper: 1.3}, 0.0)
+		tree.InsertCoalesce(&testBounds{Lower: 8.0, Upper: 9.0}, 0.0)
+
+		assert.EqualValues(t, [][2]float64{{5.0, 6.0}, {0.0, 2.0}, {8.0, 9.0}}, tree.Intervals())
+		assert.EqualValues(t, []interface{}{2}, tree.IncludingValues(1.5))
+		assert.EqualValues(t, []interface{}{1}, tree.IncludingValues(5.5))
+		assert.EqualValues(t, []interface{}{nil}, tree.IncludingValues(8.5))
+	})
+	t.Run("Case_Valued/inserted_value", func(t *testing.T) {
+		tree := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 0.0, Upper: 1.0, value: 1},
+		})
+
+		tree.InsertCoalesce(&valuedTestBounds{Lower: 5.0, Upper: 6.0, value: 2}, 0.0)
+		tree.InsertCoalesce(&valuedTestBounds{Lower: 0.5, Upper: 2.0, value: 3}, 0.0)
+
+		assert.EqualValues(t, []interface{}{2}, tree.IncludingValues(5.5))
+		assert.EqualValues(t, []interface{}{1}, tree.IncludingValues(1.5))
+	})
+	t.Run("Case_Retained_bounds", func(t *testing.T) {
+		inputBounds := []intree.ValuedBounds{
+			&valuedTestBounds{Lower: 0.0, Upper: 1.0, value: 1},
+			&valuedTestBounds{Lower: 1.5, Upper: 2.0, value: 2},
+			&valuedTestBounds{Lower: 5.0, Upper: 6.0, value: 3},
+		}
+		tree := intree.NewINTreeV(inputBounds, intree.WithRetainedBounds())
+
+		appended := &testBounds{Lower: 8.0, Upper: 9.0}
+		tree.InsertCoalesce(appended, 0.0)
+		tree.InsertCoalesce(&testBounds{Lower: 0.9, Upper: 1.6}, 0.0)
+
+		// [0,1] and [1.5,2] merge into index 0, [5,6] shifts down to index 1
+		merged := tree.IncludingValuedBounds(1.2)
+		assert.EqualValues(t, 1, len(merged))
+		lowerLimit, upperLimit := merged[0].Limits()
+		assert.EqualValues(t, 0.0, lowerLimit)
+		assert.EqualValues(t, 2.0, upperLimit)
+		assert.EqualValues(t, 1, merged[0].Value())
+
+		assert.EqualValues(t, []intree.Bounds{inputBounds[2]}, tree.IncludingBounds(5.5))
+
+		inserted := tree.IncludingValuedBounds(8.5)
+		assert.EqualValues(t, 1, len(inserted))
+		assert.Nil(t, inserted[0].Value())
+		assert.EqualValues(t, []int{2}, tree.Including(8.5))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+
+		tree.InsertCoalesce(&testBounds{Lower: 1.0, Upper: 2.0}, 1.0)
+		tree.InsertCoalesce(&testBounds{Lower: 2.5, Upper: 3.0}, 1.0)
+
+		assert.EqualValues(t, [][2]float64{{1.0, 3.0}}, tree.Intervals())
+		assert.Nil(t, tree.IncludingValues(1.5))
+	})
+	t.Run("Case_Border/randomized", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(200, 1000.0, 3.0))
+
+		for _, b := range randomBounds(50, 1000.0, 3.0) {
+			tree.InsertCoalesce(b, 0.5)
+		}
+
+		intervals := tree.Intervals()
+		for _, val := range []float64{0.0, 101.3, 250.0, 499.9, 777.7, 999.0} {
+			expected := []int{}
+			for i, interval := range intervals {
+				if interval[0] <= val && val <= interval[1] {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.Including(val))
+		}
+	})
+}
+
+func Test_Tree_Insert(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		index := tree.Insert(&testBounds{Lower: 4.2, Upper: 4.4})
+
+		assert.EqualValues(t, 13, index)
+		assert.EqualValues(t, 14, tree.Len())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10, 13}, tree.Including(4.3))
+	})
+	t.Run("Case_Valued", func(t *testing.T) {
+		tree := intree.NewINTreeV(nil, intree.WithRetainedBounds())
+
+		tree.Insert(&valuedTestBounds{Lower: 1.0, Upper: 2.0, value: 1})
+		tree.Insert(&testBounds{Lower: 1.5, Upper: 3.0})
+
+		assert.ElementsMatch(t, []interface{}{1, nil}, tree.IncludingValues(1.7))
+		assert.EqualValues(t, 2, len(tree.IncludingValuedBounds(1.7)))
+	})
+	t.Run("Case_Buffered", func(t *testing.T) {
+		bounds := randomBounds(1020, 100.0, 5.0)
+		priorities := make([]int, 1000)
+		for i := range priorities {
+			priorities[i] = i % 7
+		}
+
+		for _, opt := range []intree.Option{
+			intree.WithPriorities(priorities),
+			intree.WithEytzingerLayout(),
+			intree.WithTraceHook(func(intree.QueryEvent) {}),
+		} {
+			// Intervals inserted below the buffer limit are scanned apart from the sorted ones
+			tree := intree.NewINTree(bounds[:1000], opt)
+			for _, b := range bounds[1000:] {
+				tree.Insert(b)
+			}
+			reference := intree.NewINTree(bounds, opt)
+
+			assert.NoError(t, tree.CheckInvariants())
+			assert.EqualValues(t, reference.Intervals(), tree.Intervals())
+
+			for val := -1.0; val <= 106.0; val += 0.75 {
+				assert.ElementsMatch(t, reference.Including(val), tree.Including(val))
+				assert.EqualValues(t, reference.CountIncluding(val), tree.CountIncluding(val))
+				assert.EqualValues(t, reference.CoverageIn(val-2.0, val), tree.CoverageIn(val-2.0, val))
+
+				index, ok := tree.Nearest(val)
+				expected, found := reference.Nearest(val)
+				assert.EqualValues(t, [2]interface{}{expected, found}, [2]interface{}{index, ok})
+
+				index, ok = tree.FirstByPriority(val)
+				expected, found = reference.FirstByPriority(val)
+				assert.EqualValues(t, [2]interface{}{expected, found}, [2]interface{}{index, ok})
+
+				paged := []int{}
+				for cursor := (intree.Cursor{}); !cursor.Done(); {
+					var page []int
+					page, cursor = tree.IncludingPaged(val, cursor, 7)
+					paged = append(paged, page...)
+				}
+
+				assert.ElementsMatch(t, reference.Including(val), paged)
+			}
+
+			// Encodings merge the buffered nodes
+			data, err := tree.MarshalBinary()
+			assert.NoError(t, err)
+
+			decoded := &intree.INTree{}
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.EqualValues(t, reference.Intervals(), decoded.Intervals())
+
+			tree.Rebuild()
+			assert.NoError(t, tree.CheckInvariants())
+			assert.ElementsMatch(t, reference.Including(50.0), tree.Including(50.0))
+		}
+	})
+}
+
+func Test_Tree_Delete(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.NoError(t, tree.Delete(2))
+
+		// Indices past the deleted one stay stable until compacted
+		assert.EqualValues(t, 13, tree.Len())
+		assert.EqualValues(t, 1, tree.Removed())
+		assert.ElementsMatch(t, []int{0, 5, 8, 10}, tree.Including(4.3))
+		assert.True(t, errors.Is(tree.Delete(2), intree.ErrIndexOutOfRange))
+
+		assert.True(t, tree.Compact())
+		assert.ElementsMatch(t, []int{0, 4, 7, 9}, tree.Including(4.3))
+	})
+	t.Run("Case_Valued", func(t *testing.T) {
+		tree := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 0.0, Upper: 2.0, value: 1},
+			&valuedTestBounds{Lower: 1.0, Upper: 3.0, value: 2},
+			&valuedTestBounds{Lower: 2.0, Upper: 4.0, value: 3},
+		})
+
+		assert.NoError(t, tree.Delete(1))
+		assert.ElementsMatch(t, []interface{}{1, 3}, tree.IncludingValues(2.0))
+		assert.ElementsMatch(t, []int{0, 2}, tree.Including(2.0))
+	})
+	t.Run("Case_Border/out_of_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.True(t, errors.Is(tree.Delete(-1), intree.ErrIndexOutOfRange))
+		assert.True(t, errors.Is(tree.Delete(13), intree.ErrIndexOutOfRange))
+		assert.True(t, errors.Is(intree.NewINTree(nil).Delete(0), intree.ErrIndexOutOfRange))
+		assert.EqualValues(t, 13, tree.Len())
+	})
+}
+
+func Test_Tree_UpdateBounds(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		// Extending the upper limit keeps the node in place
+		assert.NoError(t, tree.UpdateBounds(3, 1.0, 4.5))
+		assert.ElementsMatch(t, []int{0, 2, 3, 5, 8, 10}, tree.Including(4.3))
+
+		// Moving the lower limit past the neighbors moves the node
+		assert.NoError(t, tree.UpdateBounds(12, 0.0, 0.5))
+		assert.ElementsMatch(t, []int{12}, tree.Including(0.25))
+		assert.EqualValues(t, []int{4}, tree.Including(8.5))
+
+		assert.EqualValues(t, [2]float64{1.0, 4.5}, tree.Intervals()[3])
+		assert.EqualValues(t, [2]float64{0.0, 0.5}, tree.Intervals()[12])
+		assert.NoError(t, tree.CheckInvariants())
+	})
+	t.Run("Case_Valued/retained_bounds", func(t *testing.T) {
+		tree := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 0.0, Upper: 2.0, value: 1},
+			&valuedTestBounds{Lower: 1.0, Upper: 3.0, value: 2},
+		}, intree.WithRetainedBounds(), intree.WithEytzingerLayout())
+
+		assert.NoError(t, tree.UpdateBounds(0, 4.0, 5.0))
+		assert.ElementsMatch(t, []interface{}{1}, tree.IncludingValues(4.5))
+
+		bounds := tree.IncludingValuedBounds(4.5)
+		assert.EqualValues(t, 1, len(bounds))
+		lower, upper := bounds[0].Limits()
+		assert.EqualValues(t, [3]interface{}{4.0, 5.0, 1}, [3]interface{}{lower, upper, bounds[0].Value()})
+		assert.NoError(t, tree.CheckInvariants())
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(5))
+		bounds := randomBounds(300, 100.0, 5.0)
+		model := make([][2]float64, len(bounds))
+		for i, b := range bounds {
+			model[i][0], model[i][1] = b.Limits()
+		}
+
+		tree := intree.NewINTree(bounds)
+
+		for step := 0; step < 1000; step++ {
+			index := rnd.Intn(len(model))
+			lower := model[index][0]
+			if rnd.Intn(2) == 0 {
+				lower = rnd.Float64() * 100.0
+			}
+
+			model[index] = [2]float64{lower, lower + rnd.Float64()*10.0}
+			assert.NoError(t, tree.UpdateBounds(index, model[index][0], model[index][1]))
+
+			if step%100 == 0 {
+				assert.NoError(t, tree.CheckInvariants())
+				assert.EqualValues(t, model, tree.Intervals())
+
+				val := rnd.Float64() * 100.0
+				expected := []int{}
+				for i, interval := range model {
+					if interval[0] <= val && val <= interval[1] {
+						expected = append(expected, i)
+					}
+				}
+
+				assert.ElementsMatch(t, expected, tree.Including(val))
+			}
+		}
+	})
+	t.Run("Case_Border/invalid", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.NoError(t, tree.Remove(1))
+
+		assert.ErrorIs(t, tree.UpdateBounds(-1, 0.0, 1.0), intree.ErrIndexOutOfRange)
+		assert.ErrorIs(t, tree.UpdateBounds(13, 0.0, 1.0), intree.ErrIndexOutOfRange)
+		assert.ErrorIs(t, tree.UpdateBounds(1, 0.0, 1.0), intree.ErrIndexOutOfRange)
+		assert.ErrorIs(t, tree.UpdateBounds(0, 2.0, 1.0), intree.ErrInvalidBounds)
+		assert.EqualValues(t, [2]float64{4.0, 6.0}, tree.Intervals()[0])
+	})
+}
+
+func Test_Tree_Updates_Randomized(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	model, deleted := [][2]float64{}, []bool{}
+	tree := intree.NewINTree(nil)
+
+	for step := 0; step < 2000; step++ {
+		if len(model) > 0 && rnd.Intn(3) == 0 {
+			// Deleted indices stay taken, so deleting one twice fails
+			index := rnd.Intn(len(model))
+			if deleted[index] {
+				assert.ErrorIs(t, tree.Delete(index), intree.ErrIndexOutOfRange)
+			} else {
+				deleted[index] = true
+				assert.NoError(t, tree.Delete(index))
+			}
+		} else {
+			lower := rnd.Float64() * 100.0
+			upper := lower + rnd.Float64()*5.0
+			model, deleted = append(model, [2]float64{lower, upper}), append(deleted, false)
+			assert.EqualValues(t, len(model)-1, tree.Insert(&testBounds{Lower: lower, Upper: upper}))
+		}
+
+		if step%500 == 499 {
+			// Compaction shifts the following indices down
+			assert.True(t, tree.Compact())
+
+			n := 0
+			for i, interval := range model {
+				if !deleted[i] {
+					model[n] = interval
+					n++
+				}
+			}
+
+			model, deleted = model[:n], make([]bool, n)
+		}
+
+		if step%100 == 0 {
+			assert.NoError(t, tree.CheckInvariants())
+			assert.EqualValues(t, len(model), tree.Len())
+
+			live := [][2]float64{}
+			for i, interval := range model {
+				if !deleted[i] {
+					live = append(live, interval)
+				}
+			}
+
+			assert.EqualValues(t, live, tree.Intervals())
+
+			val := rnd.Float64() * 100.0
+			expected := []int{}
+			for i, interval := range model {
+				if !deleted[i] && interval[0] <= val && val <= interval[1] {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.Including(val))
+		}
+	}
+}
+
+func Test_Tree_Rebuild(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree := intree.NewINTree(inputBounds[:5])
+
+		tree.Add(inputBounds[5:9]...)
+		tree.Add(inputBounds[9:]...)
+
+		// Staged intervals are not searchable until rebuilt
+		assert.EqualValues(t, 5, tree.Len())
+		assert.ElementsMatch(t, []int{0, 2}, tree.Including(4.3))
+
+		tree.Rebuild()
+
+		assert.EqualValues(t, 13, tree.Len())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+		assert.EqualValues(t, intree.NewINTree(inputBounds).Intervals(), tree.Intervals())
+	})
+	t.Run("Case_Valued", func(t *testing.T) {
+		tree := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: 0.0, Upper: 2.0, value: 1},
+		})
+
+		tree.Add(&valuedTestBounds{Lower: 1.0, Upper: 3.0, value: 2}, &testBounds{Lower: 1.5, Upper: 1.6})
+		tree.Rebuild()
+
+		assert.ElementsMatch(t, []interface{}{1, 2, nil}, tree.IncludingValues(1.55))
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		bounds := randomBounds(2000, 100.0, 5.0)
+		tree := intree.NewINTree(bounds[:500])
+
+		for i := 500; i < len(bounds); i += 250 {
+			tree.Add(bounds[i : i+250]...)
+			tree.Rebuild()
+		}
+
+		reference := intree.NewINTree(bounds)
+		for val := -1.0; val < 106.0; val += 0.7 {
+			assert.ElementsMatch(t, reference.Including(val), tree.Including(val))
+		}
+	})
+	t.Run("Case_Border/nothing_staged", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		tree.Rebuild()
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+	})
+}
+
+func Benchmark_Insert(b *testing.B) {
+	bounds := randomBounds(100000, 100.0, 5.0)
+	tree := intree.NewINTree(bounds)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.Insert(bounds[i%len(bounds)])
+	}
+}
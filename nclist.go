@@ -0,0 +1,201 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// NCList is a nested containment list, an alternative to INTree outperforming augmented trees when many
+// intervals are nested: intervals are grouped into lists of intervals containing none of each other, sorted by
+// both limits and searched by binary search, each interval holding the list of the intervals it contains.
+// Exposes the same search methods as INTree, returning indices to the initial bounds array.
+type NCList struct {
+	// lowers, uppers and indexes hold the nodes list by list, each list sorted by ascending lower limit
+	lowers, uppers []float64
+	indexes        []int
+	// sublists holds the node bounds of the list contained by each node, and those of the top level list at the end
+	sublists  [][2]int
+	endpoints Endpoints
+}
+
+// NewNCList is the nested containment list initialization function;
+// creates the list from the given Slice of Bounds in O(n log n) time. Only the endpoints option applies.
+func NewNCList(bounds []Bounds, opts ...Option) *NCList {
+	n := len(bounds)
+	l := NCList{
+		lowers:    make([]float64, n),
+		uppers:    make([]float64, n),
+		indexes:   make([]int, n),
+		sublists:  make([][2]int, n+1),
+		endpoints: newOptions(opts).endpoints,
+	}
+
+	order := make([]int, n)
+	limits := make([][2]float64, n)
+
+	for i, b := range bounds {
+		order[i] = i
+		limits[i][0], limits[i][1] = b.Limits()
+	}
+
+	// Containers sort before the intervals they contain
+	slices.SortStableFunc(order, func(i, j int) int {
+		if c := cmp.Compare(limits[i][0], limits[j][0]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(limits[j][1], limits[i][1])
+	})
+
+	// The parent of each interval is its tightest container: the latest one sorted before it reaching its upper
+	// limit. Intervals ending before a later one are dropped as candidates, as the later one reaches further
+	parents := make([]int, n)
+	containers := []int{}
+
+	for i, idx := range order {
+		for len(containers) > 0 && limits[order[containers[len(containers)-1]]][1] < limits[idx][1] {
+			containers = containers[:len(containers)-1]
+		}
+
+		parents[i] = n
+		if len(containers) > 0 {
+			parents[i] = containers[len(containers)-1]
+		}
+
+		containers = append(containers, i)
+	}
+
+	// Lay out the lists contiguously, keeping the sorted order within each of them
+	counts := make([]int, n+1)
+	for _, p := range parents {
+		counts[p]++
+	}
+
+	starts, start := make([]int, n+1), 0
+	for p, c := range counts {
+		starts[p] = start
+		start += c
+	}
+
+	next := slices.Clone(starts)
+	slots := make([]int, n)
+
+	for i, p := range parents {
+		slots[i] = next[p]
+		next[p]++
+	}
+
+	for i, idx := range order {
+		slot := slots[i]
+		l.lowers[slot], l.uppers[slot], l.indexes[slot] = limits[idx][0], limits[idx][1], idx
+		l.sublists[slot] = [2]int{starts[i], next[i]}
+	}
+
+	l.sublists[n] = [2]int{starts[n], next[n]}
+
+	return &l
+}
+
+// Len returns the number of intervals stored in the list.
+func (l *NCList) Len() int {
+	return len(l.indexes)
+}
+
+// Including searches the list and collects intervals that overlap with the given value.
+func (l *NCList) Including(val float64) []int {
+	return l.Overlapping(val, val)
+}
+
+// IncludingFunc is the allocation free counterpart of Including;
+// calls fn with the index of every interval that overlaps with the given value,
+// and stops the search as soon as fn returns false.
+func (l *NCList) IncludingFunc(val float64, fn func(idx int) bool) {
+	lower, upper := l.endpoints.searchRange(val, val)
+
+	l.search(lower, upper, func(pos int) bool {
+		return fn(l.indexes[pos])
+	})
+}
+
+// Overlapping searches the list and collects intervals that overlap with the given range, boundaries included.
+// Returns an empty Slice if lower is greater than upper.
+func (l *NCList) Overlapping(lower, upper float64) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	lower, upper = l.endpoints.searchRange(lower, upper)
+
+	l.search(lower, upper, func(pos int) bool {
+		result = append(result, l.indexes[pos])
+		return true
+	})
+
+	return result
+}
+
+// Intersecting is an alias of Overlapping, for callers used to the intersection terminology.
+func (l *NCList) Intersecting(lo, hi float64) []int {
+	return l.Overlapping(lo, hi)
+}
+
+// search is an internal utility function, calling fn with the position of every node overlapping with
+// the given closed range, stopping as soon as fn returns false. Lists are sorted by both limits, so that
+// the first match of each is found by binary search and the following nodes match until one starts past the range.
+func (l *NCList) search(lower, upper float64, fn func(pos int) bool) {
+	var stock [stockSize][2]int
+	listStock := append(stock[:0], l.sublists[len(l.indexes)])
+
+	for len(listStock) > 0 {
+		list := listStock[len(listStock)-1]
+		listStock = listStock[:len(listStock)-1]
+
+		// Uppers ascend along the list: find the first one reaching the range
+		first, last := list[0], list[1]
+		for first < last {
+			if mid := int(uint(first+last) >> 1); l.uppers[mid] < lower {
+				first = mid + 1
+			} else {
+				last = mid
+			}
+		}
+
+		for pos := first; pos < list[1]; pos++ {
+			if l.lowers[pos] > upper {
+				break
+			}
+
+			if !fn(pos) {
+				return
+			}
+
+			if sub := l.sublists[pos]; sub[0] < sub[1] {
+				listStock = append(listStock, sub)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+// nestedBounds returns n intervals drawn from a fixed seed, nested in chains of up to depth intervals
+// centered on whole values in [0, domain).
+func nestedBounds(n, depth int, domain float64) []intree.Bounds {
+	rnd := rand.New(rand.NewSource(13))
+	bounds := make([]intree.Bounds, 0, n)
+
+	for len(bounds) < n {
+		center := float64(rnd.Intn(int(domain)))
+		for d := rnd.Intn(depth) + 1; d > 0 && len(bounds) < n; d-- {
+			half := float64(d) * (1.0 + rnd.Float64())
+			bounds = append(bounds, intree.Interval{Lower: center - half, Upper: center + half})
+		}
+	}
+
+	return bounds
+}
+
+func Test_NCList(t *testing.T) {
+	t.Run("Case_Reference", func(t *testing.T) {
+		for _, bounds := range [][]intree.Bounds{nestedBounds(3000, 20, 500.0), randomBounds(3000, 500.0, 30.0)} {
+			list := intree.NewNCList(bounds)
+			reference := intree.NewINTree(bounds)
+
+			assert.EqualValues(t, len(bounds), list.Len())
+
+			for val := -50.0; val <= 550.0; val += 0.75 {
+				assert.ElementsMatch(t, reference.Including(val), list.Including(val), "at %.2f", val)
+			}
+
+			for lower := -10.0; lower <= 510.0; lower += 13.0 {
+				assert.ElementsMatch(t, reference.Overlapping(lower, lower+7.0), list.Overlapping(lower, lower+7.0))
+				assert.ElementsMatch(t, reference.Intersecting(lower, lower+7.0), list.Intersecting(lower, lower+7.0))
+			}
+		}
+	})
+	t.Run("Case_Duplicates", func(t *testing.T) {
+		list := intree.NewNCList([]intree.Bounds{
+			intree.Interval{Lower: 1.0, Upper: 4.0},
+			intree.Interval{Lower: 0.0, Upper: 5.0},
+			intree.Interval{Lower: 1.0, Upper: 4.0},
+			intree.Interval{Lower: 3.0, Upper: 6.0},
+		})
+
+		assert.ElementsMatch(t, []int{0, 1, 2, 3}, list.Including(3.5))
+		assert.ElementsMatch(t, []int{1, 3}, list.Including(5.0))
+		assert.ElementsMatch(t, []int{3}, list.Overlapping(5.5, 10.0))
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		bounds := []intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 4.0},
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 3.0},
+		}
+
+		for _, e := range []intree.Endpoints{intree.Closed, intree.LowerOpen, intree.UpperOpen, intree.Open} {
+			list := intree.NewNCList(bounds, intree.WithEndpoints(e))
+			reference := intree.NewINTree(bounds, intree.WithEndpoints(e))
+
+			for _, val := range []float64{0.0, 1.0, 1.5, 2.0, 3.0, 4.0} {
+				assert.ElementsMatch(t, reference.Including(val), list.Including(val), "%v at %.1f", e, val)
+				assert.ElementsMatch(t, reference.Overlapping(val, val+1.0), list.Overlapping(val, val+1.0), "%v at %.1f", e, val)
+			}
+		}
+	})
+	t.Run("Case_IncludingFunc", func(t *testing.T) {
+		list := intree.NewNCList(nestedBounds(100, 10, 10.0))
+		calls := 0
+
+		list.IncludingFunc(5.0, func(int) bool {
+			calls++
+			return false
+		})
+
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_Border/invalid_range", func(t *testing.T) {
+		list := intree.NewNCList(nestedBounds(100, 10, 10.0))
+		assert.Empty(t, list.Overlapping(5.0, 4.0))
+		assert.Empty(t, intree.NewNCList(nil).Including(1.0))
+	})
+}
+
+func Benchmark_NCList(b *testing.B) {
+	bounds := nestedBounds(100000, 50, 10000.0)
+	list := intree.NewNCList(bounds)
+	reference := intree.NewINTree(bounds)
+
+	b.Run("INTree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			reference.Including(float64(i % 10000))
+		}
+	})
+	b.Run("NCList", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			list.Including(float64(i % 10000))
+		}
+	})
+}
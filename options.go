@@ -0,0 +1,167 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Option is a tree construction setting, accepted by the initialization functions.
+type Option func(*options)
+
+// options holds the tree construction settings.
+type options struct {
+	retainBounds        bool
+	deterministicSort   bool
+	validation          bool
+	capacityHint        int
+	eytzingerLayout     bool
+	endpoints           Endpoints
+	compactionThreshold float64
+	metadata            map[int]any
+	priorities          []int
+	traceHook           func(ev QueryEvent)
+	backend             Backend
+}
+
+// WithRetainedBounds makes the tree keep references to the given bounds, indexed by original index,
+// so that searches can return them directly through IncludingBounds and IncludingValuedBounds.
+func WithRetainedBounds() Option {
+	return func(o *options) {
+		o.retainBounds = true
+	}
+}
+
+// WithDeterministicSort makes the tree pick sort pivots by median of three instead of at random,
+// so that the same input always results in the same layout without depending on a Source.
+// Takes precedence over the Source given to NewINTreeWithSource and NewINTreeVWithSource.
+func WithDeterministicSort() Option {
+	return func(o *options) {
+		o.deterministicSort = true
+	}
+}
+
+// WithEndpoints sets whether searches include the interval limits, which are closed by default.
+func WithEndpoints(e Endpoints) Option {
+	return func(o *options) {
+		o.endpoints = e
+	}
+}
+
+// WithClosedOpenSemantics makes searches exclude the interval upper limits, as in WithEndpoints(UpperOpen).
+// Useful for time slots, where an interval ending at a given instant does not include it.
+func WithClosedOpenSemantics() Option {
+	return WithEndpoints(UpperOpen)
+}
+
+// WithValidation makes the initialization functions building from Bounds check every interval as
+// NewINTreeChecked does, without failing on the invalid ones: intervals that are nil, have NaN or infinite
+// limits, or a lower limit greater than its upper limit are stored removed, as if by Remove. They keep their
+// index, are skipped by searches and counted by Removed until Compact drops them.
+func WithValidation() Option {
+	return func(o *options) {
+		o.validation = true
+	}
+}
+
+// WithCapacityHint makes the tree reserve room for the given number of intervals, so that streamed
+// initialization and later updates grow the nodes without reallocating them until the hint is reached.
+// Hints below the number of intervals are ignored.
+func WithCapacityHint(n int) Option {
+	return func(o *options) {
+		o.capacityHint = n
+	}
+}
+
+// WithEytzingerLayout makes the tree keep a breadth first (Eytzinger) copy of its nodes for searches,
+// improving cache locality on large trees at the cost of doubling the node memory. Matches are found in a
+// different order than with the default in-order layout. The layout is not encoded, and is rebuilt on updates.
+func WithEytzingerLayout() Option {
+	return func(o *options) {
+		o.eytzingerLayout = true
+	}
+}
+
+// WithCompactionThreshold sets the fraction of removed intervals, between 0 and 1, from which Compact drops them
+// from the tree. Compact drops any removed interval by default.
+func WithCompactionThreshold(fraction float64) Option {
+	return func(o *options) {
+		o.compactionThreshold = fraction
+	}
+}
+
+// WithMetadata makes the tree keep the given annotations of its intervals, by original index, so that they can be
+// looked up through MetadataFor without a parallel Slice maintained by the caller. The map is copied, ignoring
+// indices out of range.
+func WithMetadata(metadata map[int]any) Option {
+	return func(o *options) {
+		o.metadata = metadata
+	}
+}
+
+// WithPriorities sets the priorities of the intervals, by original index, so that FirstByPriority finds the
+// highest priority interval including a value, as in firewall rules or pricing tiers. The Slice is copied;
+// intervals past its length, including inserted ones, get priority 0.
+func WithPriorities(priorities []int) Option {
+	return func(o *options) {
+		o.priorities = priorities
+	}
+}
+
+// WithBackend sets the structure built by NewIndex, an INTree by default.
+func WithBackend(b Backend) Option {
+	return func(o *options) {
+		o.backend = b
+	}
+}
+
+// pivots is an internal utility function, returning the pivot generator of the sort: nil for median of three.
+func (o options) pivots(src rand.Source) *rand.Rand {
+	if o.deterministicSort || src == nil {
+		return nil
+	}
+
+	return rand.New(src)
+}
+
+// limits is an internal utility function, returning the limits of the given bounds for the initialization
+// functions; nil bounds take NaN limits when validating, so that they are stored removed.
+func (o options) limits(b Bounds) (float64, float64) {
+	if b == nil && o.validation {
+		return math.NaN(), math.NaN()
+	}
+
+	return b.Limits()
+}
+
+// newOptions is an internal utility function, applying the given options over the default settings.
+func newOptions(opts []Option) options {
+	o := options{}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
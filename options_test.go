@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Options(t *testing.T) {
+	t.Run("Case_Validation", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithValidation())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+		assert.EqualValues(t, 0, tree.Removed())
+
+		tree = intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 3.0, Upper: 2.0},
+			nil,
+			&testBounds{Lower: math.Inf(-1), Upper: 2.5},
+			&testBounds{Lower: 2.0, Upper: 3.0},
+		}, intree.WithValidation(), intree.WithEytzingerLayout())
+		assert.EqualValues(t, 5, tree.Len())
+		assert.EqualValues(t, 3, tree.Removed())
+		assert.ElementsMatch(t, []int{4}, tree.Including(2.2))
+		assert.EqualValues(t, [][2]float64{{0.0, 1.0}, {2.0, 3.0}}, tree.Intervals())
+		assert.ErrorIs(t, tree.Remove(1), intree.ErrIndexOutOfRange)
+		assert.NoError(t, tree.CheckInvariants())
+
+		assert.True(t, tree.Compact())
+		assert.ElementsMatch(t, []int{1}, tree.Including(2.2))
+
+		valued := intree.NewINTreeV([]intree.ValuedBounds{
+			&valuedTestBounds{Lower: math.NaN(), Upper: 2.0, value: 1},
+			nil,
+			&valuedTestBounds{Lower: 1.0, Upper: 2.0, value: 3},
+		}, intree.WithValidation())
+		assert.EqualValues(t, 2, valued.Removed())
+		assert.ElementsMatch(t, []interface{}{3}, valued.IncludingValues(1.5))
+
+		streamed := intree.NewINTreeFrom(func(yield func(intree.Bounds) bool) {
+			_ = yield(nil) && yield(&testBounds{Lower: 1.0, Upper: 2.0})
+		}, intree.WithValidation())
+		assert.EqualValues(t, 1, streamed.Removed())
+		assert.ElementsMatch(t, []int{1}, streamed.Including(1.5))
+	})
+	t.Run("Case_Closed_open_semantics", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 1.0, Upper: 2.0},
+		}, intree.WithClosedOpenSemantics())
+
+		assert.EqualValues(t, intree.UpperOpen, tree.Endpoints())
+		assert.EqualValues(t, []int{1}, tree.Including(1.0))
+		assert.EqualValues(t, []int{0}, tree.Including(0.0))
+		assert.Empty(t, tree.Including(2.0))
+	})
+}
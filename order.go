@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	gosort "sort"
+)
+
+// ErrInvalidComparator is returned by NewINTreeSortedBy when the given comparator is not a consistent ordering.
+var ErrInvalidComparator = errors.New("intree: invalid comparator")
+
+// NewINTreeSortedBy is the custom order initialization function;
+// creates the tree from the given Slice of Bounds, laying out nodes by the given comparator over
+// original indices, so searches yield matches in that order.
+// As searches depend on nodes being sorted by lower limit for pruning, a custom order disables pruning
+// and every search becomes a full traversal; operations relying on the lower limit order work on a
+// lower ordered view of the nodes instead, built along with the tree. Insertions append nodes past the
+// custom order, so that inserted intervals are yielded last, and updates other than Remove rebuild the view
+// in O(n log n) time.
+// Falls back to the default construction if less is nil, and keeps pruning enabled if the resulting
+// order is sorted by lower limit anyway. Returns an ErrInvalidComparator wrapped error if less
+// contradicts itself on the given bounds.
+func NewINTreeSortedBy(bounds []Bounds, less func(i, j int) bool) (*INTree, error) {
+	if less == nil {
+		return NewINTree(bounds), nil
+	}
+
+	order := make([]int, len(bounds))
+	for i := range order {
+		order[i] = i
+	}
+
+	gosort.SliceStable(order, func(i, j int) bool {
+		return less(order[i], order[j])
+	})
+
+	for i := 1; i < len(order); i++ {
+		if less(order[i], order[i-1]) {
+			return nil, fmt.Errorf("%w: index %d sorts both before and after index %d", ErrInvalidComparator, order[i], order[i-1])
+		}
+	}
+
+	tree := INTree{
+		indexes: order,
+		limits:  make([]float64, 3*len(bounds)),
+	}
+
+	for pos, idx := range order {
+		l, u := bounds[idx].Limits()
+
+		tree.limits[3*pos] = l
+		tree.limits[3*pos+1] = u
+		tree.limits[3*pos+2] = 0
+	}
+
+	augment(tree.limits, tree.indexes)
+	tree.positions = mapPositions(tree.indexes)
+	tree.unordered = !isLowerSorted(tree.limits)
+	tree.sortView()
+
+	return &tree, nil
+}
+
+// orderedView holds the nodes of an unordered tree sorted by lower limit, along with their subtree priorities.
+type orderedView struct {
+	indexes     []int
+	limits      []float64
+	positions   []int
+	priorityMax []int
+}
+
+// lowerOrdered is an internal utility function, returning the tree itself if its nodes are sorted by
+// lower limit, a copy of it with the nodes buffered by Insert merged in if there are any, or a copy of it over
+// the lower ordered view of its nodes otherwise. Every other field is shared.
+func (t *INTree) lowerOrdered() *INTree {
+	if !t.unordered {
+		return t.merged()
+	}
+
+	view := t.view
+	if view == nil {
+		view = t.newView()
+	}
+
+	tree := *t
+	tree.indexes, tree.limits, tree.positions, tree.priorityMax = view.indexes, view.limits, view.positions, view.priorityMax
+	tree.unordered, tree.layout, tree.view = false, nil, nil
+
+	return &tree
+}
+
+// sortView is an internal utility function, rebuilding the lower ordered view of unordered trees in O(n log n)
+// time after their nodes changed, and dropping it from trees sorted by lower limit.
+func (t *INTree) sortView() {
+	t.view = nil
+
+	if t.unordered {
+		t.view = t.newView()
+	}
+}
+
+// newView is an internal utility function, sorting a copy of the tree nodes by lower limit.
+func (t *INTree) newView() *orderedView {
+	tree := INTree{
+		indexes:    slices.Clone(t.indexes),
+		limits:     slices.Clone(t.limits),
+		priorities: t.priorities,
+	}
+
+	sort(tree.limits, tree.indexes, nil)
+	augment(tree.limits, tree.indexes)
+	tree.positions = mapPositions(tree.indexes)
+	tree.augmentPriorities()
+
+	return &orderedView{indexes: tree.indexes, limits: tree.limits, positions: tree.positions, priorityMax: tree.priorityMax}
+}
+
+// isLowerSorted is an internal utility function, reporting whether the given node limits are sorted by lower limit.
+func isLowerSorted(limits []float64) bool {
+	for i := 3; i < len(limits); i += 3 {
+		if limits[i] < limits[i-3] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_SortedBy(t *testing.T) {
+	t.Run("Case_Custom_order", func(t *testing.T) {
+		inputBounds := exampleBounds()
+
+		// Sort by descending upper limit, disabling pruning
+		tree, err := intree.NewINTreeSortedBy(inputBounds, func(i, j int) bool {
+			_, ui := inputBounds[i].Limits()
+			_, uj := inputBounds[j].Limits()
+			return ui > uj
+		})
+		assert.NoError(t, err)
+
+		matches := tree.Including(4.3)
+		assert.EqualValues(t, []int{2, 8, 0, 5, 10}, matches)
+
+		reference := intree.NewINTree(inputBounds)
+		for _, val := range []float64{0.5, 1.0, 3.0, 4.3, 6.0, 7.9, 9.0, 9.5} {
+			assert.ElementsMatch(t, reference.Including(val), tree.Including(val))
+			assert.ElementsMatch(t, reference.Overlapping(val, val+1.0), tree.Overlapping(val, val+1.0))
+			assert.EqualValues(t, reference.CountIncluding(val), tree.CountIncluding(val))
+		}
+	})
+	t.Run("Case_Custom_order/lower_ordered_operations", func(t *testing.T) {
+		inputBounds := randomBounds(300, 100.0, 3.0)
+		less := func(i, j int) bool { return i > j }
+
+		tree, err := intree.NewINTreeSortedBy(inputBounds, less)
+		assert.NoError(t, err)
+
+		reference := intree.NewINTree(inputBounds)
+
+		assert.EqualValues(t, reference.Intervals(), tree.Intervals())
+		assert.InDelta(t, reference.CoverageEntropy(), tree.CoverageEntropy(), 1e-9)
+		assert.InDelta(t, reference.CoverageRatio(10.0, 60.0), tree.CoverageRatio(10.0, 60.0), 1e-9)
+		assert.EqualValues(t, reference.CoverageLostIfRemoved(7), tree.CoverageLostIfRemoved(7))
+
+		for _, val := range []float64{-1.0, 25.2, 50.0, 77.7, 110.0} {
+			expected, _ := reference.Nearest(val)
+			index, ok := tree.Nearest(val)
+			assert.True(t, ok)
+			assert.EqualValues(t, expected, index)
+		}
+
+		// Updates keep the lower ordered view in sync
+		tree.InsertCoalesce(&testBounds{Lower: 200.0, Upper: 201.0}, 0.0)
+		reference.InsertCoalesce(&testBounds{Lower: 200.0, Upper: 201.0}, 0.0)
+		assert.EqualValues(t, reference.Intervals(), tree.Intervals())
+		assert.ElementsMatch(t, reference.Including(200.5), tree.Including(200.5))
+	})
+	t.Run("Case_Custom_order/updates", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree, err := intree.NewINTreeSortedBy(inputBounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		reference := intree.NewINTree(inputBounds)
+
+		// Inserted intervals follow the custom order
+		assert.EqualValues(t, 13, tree.Insert(&testBounds{Lower: 4.0, Upper: 4.5}))
+		reference.Insert(&testBounds{Lower: 4.0, Upper: 4.5})
+		assert.EqualValues(t, []int{10, 8, 5, 2, 0, 13}, tree.Including(4.3))
+
+		tree.Add(&testBounds{Lower: 4.2, Upper: 4.4}, &testBounds{Lower: 4.1, Upper: 4.3})
+		tree.Rebuild()
+		reference.Add(&testBounds{Lower: 4.2, Upper: 4.4}, &testBounds{Lower: 4.1, Upper: 4.3})
+		reference.Rebuild()
+		assert.EqualValues(t, []int{10, 8, 5, 2, 0, 13, 14, 15}, tree.Including(4.3))
+
+		clone := tree.Clone()
+
+		assert.NoError(t, tree.Remove(2))
+		assert.NoError(t, reference.Remove(2))
+		assert.NoError(t, tree.UpdateBounds(5, 0.0, 0.5))
+		assert.NoError(t, reference.UpdateBounds(5, 0.0, 0.5))
+		assert.NoError(t, tree.CheckInvariants())
+		assert.EqualValues(t, []int{10, 8, 0, 13, 14, 15}, tree.Including(4.3))
+
+		assert.InDelta(t, reference.CoverageEntropy(), tree.CoverageEntropy(), 1e-9)
+		assert.EqualValues(t, reference.CoverageLostIfRemoved(0), tree.CoverageLostIfRemoved(0))
+		assert.EqualValues(t, slices.Collect(reference.CoverageSeq()), slices.Collect(tree.CoverageSeq()))
+
+		for _, val := range []float64{-1.0, 0.25, 4.3, 7.9, 20.0} {
+			expected, _ := reference.Nearest(val)
+			index, _ := tree.Nearest(val)
+			assert.EqualValues(t, expected, index, "at %.2f", val)
+		}
+
+		// Clones keep their own view
+		assert.EqualValues(t, []int{10, 8, 5, 2, 0, 13, 14, 15}, clone.Including(4.3))
+		assert.Nil(t, tree.CoverageLostIfRemoved(2))
+		assert.NotNil(t, clone.CoverageLostIfRemoved(2))
+	})
+	t.Run("Case_Custom_order/encoding", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree, err := intree.NewINTreeSortedBy(inputBounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.EqualValues(t, tree.Including(4.3), decoded.Including(4.3))
+	})
+	t.Run("Case_Lower_order", func(t *testing.T) {
+		inputBounds := exampleBounds()
+
+		tree, err := intree.NewINTreeSortedBy(inputBounds, func(i, j int) bool {
+			li, _ := inputBounds[i].Limits()
+			lj, _ := inputBounds[j].Limits()
+			return li < lj
+		})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+	})
+	t.Run("Case_Border/nil_comparator", func(t *testing.T) {
+		tree, err := intree.NewINTreeSortedBy(exampleBounds(), nil)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree, err := intree.NewINTreeSortedBy(nil, func(i, j int) bool { return i < j })
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0, len(tree.Including(4.3)))
+	})
+	t.Run("Case_Border/invalid_comparator", func(t *testing.T) {
+		tree, err := intree.NewINTreeSortedBy(exampleBounds(), func(i, j int) bool { return true })
+		assert.Nil(t, tree)
+		assert.True(t, errors.Is(err, intree.ErrInvalidComparator))
+	})
+}
@@ -0,0 +1,178 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"math/rand"
+)
+
+// BoundsOf is the main interface expected by NewINTreeOf(); requires Limits method to access interval limits
+// of any ordered coordinate type.
+type BoundsOf[T cmp.Ordered] interface {
+	Limits() (lower, upper T)
+}
+
+// INTreeOf is the coordinate generic counterpart of INTree;
+// holds Slice of reference indices and the respective interval limits, without converting them to float64.
+type INTreeOf[T cmp.Ordered] struct {
+	indexes []int
+	limits  []T
+}
+
+// NewINTreeOf is the coordinate generic initialization function;
+// creates the tree from the given Slice of BoundsOf, comparing limits exactly in their own type.
+func NewINTreeOf[T cmp.Ordered](bounds []BoundsOf[T]) *INTreeOf[T] {
+	tree := INTreeOf[T]{}
+	tree.buildTree(bounds, rand.New(runtimeSource{}))
+
+	return &tree
+}
+
+// buildTree is the internal tree construction function;
+// creates, sorts and augments nodes into Slices.
+func (t *INTreeOf[T]) buildTree(bounds []BoundsOf[T], rnd *rand.Rand) {
+	t.indexes = make([]int, len(bounds))
+	t.limits = make([]T, 3*len(bounds))
+
+	for i, v := range bounds {
+		t.indexes[i] = i
+		l, u := v.Limits()
+
+		t.limits[3*i] = l
+		t.limits[3*i+1] = u
+		t.limits[3*i+2] = u
+	}
+
+	sort(t.limits, t.indexes, rnd)
+	augment(t.limits, t.indexes)
+}
+
+// Len returns the number of intervals stored in the tree.
+func (t *INTreeOf[T]) Len() int {
+	return len(t.indexes)
+}
+
+// Including traverses the tree and collects intervals that overlap with the given value.
+func (t *INTreeOf[T]) Including(val T) []int {
+	result := []int{}
+
+	t.traverse(val, val, func(pos int) bool {
+		result = append(result, t.indexes[pos])
+		return true
+	})
+
+	return result
+}
+
+// IncludingFunc calls fn with the index of every interval that overlaps with the given value,
+// stopping the traversal as soon as fn returns false.
+func (t *INTreeOf[T]) IncludingFunc(val T, fn func(idx int) bool) {
+	t.traverse(val, val, func(pos int) bool {
+		return fn(t.indexes[pos])
+	})
+}
+
+// CountIncluding returns the number of intervals that overlap with the given value, without collecting them.
+func (t *INTreeOf[T]) CountIncluding(val T) int {
+	count := 0
+
+	t.traverse(val, val, func(int) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+// Overlapping traverses the tree and collects intervals that overlap with the given range, boundaries included.
+// Returns an empty Slice if lower is greater than upper.
+func (t *INTreeOf[T]) Overlapping(lower, upper T) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	t.traverse(lower, upper, func(pos int) bool {
+		result = append(result, t.indexes[pos])
+		return true
+	})
+
+	return result
+}
+
+// traverse is the internal tree search function;
+// calls fn with the position of every node overlapping with the given range, stopping as soon as fn returns false.
+func (t *INTreeOf[T]) traverse(lower, upper T, fn func(pos int) bool) {
+	var stock [stockSize]int
+	idxStock := append(stock[:0], 0, len(t.indexes)-1)
+
+	for len(idxStock) > 0 {
+		// Retrieve right and left boundaries from index stock
+		rBoundIdx := idxStock[len(idxStock)-1]
+		idxStock = idxStock[:len(idxStock)-1]
+		lBoundIdx := idxStock[len(idxStock)-1]
+		idxStock = idxStock[:len(idxStock)-1]
+
+		if lBoundIdx == rBoundIdx+1 {
+			continue
+		}
+
+		centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+		if lower <= t.limits[3*centerIdx+2] {
+			idxStock = append(idxStock, lBoundIdx, centerIdx-1)
+		}
+
+		if t.limits[3*centerIdx] <= upper {
+			idxStock = append(idxStock, centerIdx+1, rBoundIdx)
+
+			if lower <= t.limits[3*centerIdx+1] && !fn(centerIdx) {
+				return
+			}
+		}
+	}
+}
+
+// Bounds64 is the int64 instantiation of BoundsOf, for limits beyond the exact float64 range (2^53), such as
+// Unix nanosecond timestamps or 64 bit identifiers.
+type Bounds64 = BoundsOf[int64]
+
+// INTree64 is the int64 instantiation of INTreeOf, comparing limits exactly as int64.
+type INTree64 = INTreeOf[int64]
+
+// Interval64 is a plain Bounds64 implementation.
+type Interval64 struct {
+	Lower, Upper int64
+}
+
+// Limits accesses the interval limits.
+func (i Interval64) Limits() (int64, int64) {
+	return i.Lower, i.Upper
+}
+
+// NewINTree64 is the int64 initialization function, equivalent to NewINTreeOf on int64 limits.
+func NewINTree64(bounds []Bounds64) *INTree64 {
+	return NewINTreeOf(bounds)
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderedTestBounds[T int64 | uint32 | float32 | string] struct {
+	Lower, Upper T
+}
+
+func (ob orderedTestBounds[T]) Limits() (T, T) {
+	return ob.Lower, ob.Upper
+}
+
+func Test_Tree_Ordered(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := []intree.BoundsOf[float32]{}
+		for _, b := range exampleBounds() {
+			lowerLimit, upperLimit := b.Limits()
+			inputBounds = append(inputBounds, orderedTestBounds[float32]{Lower: float32(lowerLimit), Upper: float32(upperLimit)})
+		}
+
+		tree := intree.NewINTreeOf(inputBounds)
+
+		assert.EqualValues(t, 13, tree.Len())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Including(4.3))
+		assert.EqualValues(t, 5, tree.CountIncluding(4.3))
+		assert.ElementsMatch(t, []int{4}, tree.Overlapping(8.95, 10.0))
+	})
+	t.Run("Case_Int64/exact_limits", func(t *testing.T) {
+		// Consecutive nanosecond timestamps collapse to the same float64 at this magnitude
+		base := int64(1) << 60
+		tree := intree.NewINTreeOf([]intree.BoundsOf[int64]{
+			orderedTestBounds[int64]{Lower: base, Upper: base + 1},
+			orderedTestBounds[int64]{Lower: base + 2, Upper: base + 3},
+		})
+
+		assert.EqualValues(t, []int{0}, tree.Including(base+1))
+		assert.EqualValues(t, []int{1}, tree.Including(base+2))
+		assert.EqualValues(t, 0, len(tree.Including(base+4)))
+		assert.ElementsMatch(t, []int{0, 1}, tree.Overlapping(base+1, base+2))
+	})
+	t.Run("Case_INTree64", func(t *testing.T) {
+		// 2^53 + 1 is not representable as float64, and would round down to 2^53
+		base := int64(1) << 53
+		tree := intree.NewINTree64([]intree.Bounds64{
+			intree.Interval64{Lower: base + 1, Upper: base + 1},
+			intree.Interval64{Lower: base - 1, Upper: base},
+		})
+
+		assert.EqualValues(t, 2, tree.Len())
+		assert.EqualValues(t, []int{0}, tree.Including(base+1))
+		assert.EqualValues(t, []int{1}, tree.Including(base))
+		assert.ElementsMatch(t, []int{0, 1}, tree.Overlapping(base, base+1))
+	})
+	t.Run("Case_Uint32", func(t *testing.T) {
+		tree := intree.NewINTreeOf([]intree.BoundsOf[uint32]{
+			orderedTestBounds[uint32]{Lower: 0x0a000000, Upper: 0x0affffff},
+			orderedTestBounds[uint32]{Lower: 0xc0a80000, Upper: 0xc0a8ffff},
+			orderedTestBounds[uint32]{Lower: 0xc0a80100, Upper: 0xc0a801ff},
+		})
+
+		assert.ElementsMatch(t, []int{1, 2}, tree.Including(0xc0a80101))
+		assert.EqualValues(t, []int{0}, tree.Including(0x0a0a0a0a))
+		assert.EqualValues(t, 0, len(tree.Including(0x7f000001)))
+	})
+	t.Run("Case_String", func(t *testing.T) {
+		tree := intree.NewINTreeOf([]intree.BoundsOf[string]{
+			orderedTestBounds[string]{Lower: "apple", Upper: "banana"},
+			orderedTestBounds[string]{Lower: "avocado", Upper: "cherry"},
+		})
+
+		assert.ElementsMatch(t, []int{0, 1}, tree.Including("b"))
+		assert.EqualValues(t, []int{1}, tree.Including("c"))
+	})
+	t.Run("Case_IncludingFunc/early_exit", func(t *testing.T) {
+		tree := intree.NewINTreeOf([]intree.BoundsOf[int64]{
+			orderedTestBounds[int64]{Lower: 0, Upper: 10},
+			orderedTestBounds[int64]{Lower: 5, Upper: 15},
+		})
+
+		calls := 0
+		tree.IncludingFunc(7, func(idx int) bool {
+			calls++
+			return false
+		})
+
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_EqualLimits", func(t *testing.T) {
+		bounds := make([]intree.BoundsOf[int64], 2000)
+		for i := range bounds {
+			bounds[i] = orderedTestBounds[int64]{Lower: 0, Upper: int64(i)}
+		}
+
+		tree := intree.NewINTreeOf(bounds)
+
+		assert.EqualValues(t, 1000, tree.CountIncluding(1000))
+		assert.EqualValues(t, 2000, tree.CountIncluding(0))
+		assert.EqualValues(t, 0, tree.CountIncluding(2000))
+		assert.EqualValues(t, 10, len(tree.Overlapping(1990, 3000)))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTreeOf[int64](nil)
+
+		assert.EqualValues(t, 0, tree.Len())
+		assert.EqualValues(t, 0, len(tree.Including(4)))
+		assert.EqualValues(t, 0, len(tree.Overlapping(5, 4)))
+	})
+}
+
+func Benchmark_NewINTree64(b *testing.B) {
+	// Equal lower limits, as in events sharing a start timestamp
+	bounds := make([]intree.Bounds64, 100000)
+	for i := range bounds {
+		bounds[i] = intree.Interval64{Lower: 1 << 60, Upper: 1<<60 + int64(i)}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		intree.NewINTree64(bounds)
+	}
+}
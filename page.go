@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+// Cursor is the resume point of a paged search, as returned by IncludingPaged. The zero value starts
+// a search from the first match. Cursors stay valid as long as the tree is not modified.
+type Cursor struct {
+	pos  int
+	done bool
+}
+
+// Done reports whether the paged search the cursor was returned for has no further matches.
+func (c Cursor) Done() bool {
+	return c.done
+}
+
+// IncludingPaged is the paged counterpart of Including, letting callers stream huge match sets without
+// collecting them into a single Slice; collects up to pageSize indices of the intervals that overlap with
+// the given value, resuming from the given cursor, along with the cursor of the next page.
+// Matches come in node order, so that each page costs O(log n + pageSize) regardless of the matches skipped.
+// The returned cursor is Done once no matches remain; a zero or negative pageSize returns no matches
+// and the given cursor.
+func (t *INTree) IncludingPaged(val float64, cursor Cursor, pageSize int) ([]int, Cursor) {
+	result := []int{}
+
+	if cursor.done || pageSize <= 0 {
+		return result, cursor
+	}
+
+	next := Cursor{done: true}
+	lower, upper := t.searchRange(val, val)
+
+	t.traverseFrom(cursor.pos, lower, upper, func(pos int) bool {
+		if len(result) == pageSize {
+			next = Cursor{pos: pos}
+			return false
+		}
+
+		result = append(result, t.indexes[pos])
+
+		return true
+	})
+
+	return result, next
+}
+
+// traverseFrom is an internal utility function, calling fn with the position of every node from the given
+// position on that overlaps with the given closed range, in ascending position order.
+// Stops the traversal as soon as fn returns false.
+func (t *INTree) traverseFrom(from int, lower, upper float64, fn func(pos int) bool) {
+	if t.unordered {
+		for pos := max(from, 0); pos < len(t.indexes); pos++ {
+			if t.limits[3*pos] <= upper && lower <= t.limits[3*pos+1] && !fn(pos) {
+				return
+			}
+		}
+
+		return
+	}
+
+	// Nodes buffered by Insert follow the sorted ones, whose traversal also ends once past the range
+	stopped := false
+	t.overlapsFrom(0, t.treeLen()-1, from, lower, upper, func(pos int) bool {
+		stopped = !fn(pos)
+		return !stopped
+	})
+
+	if !stopped {
+		t.scanBuffered(from, lower, upper, fn)
+	}
+}
+
+// overlapsFrom is an internal utility function, the resumable counterpart of overlapsInOrder;
+// skips the subtrees of nodes in the given bounds that lie before the given position.
+func (t *INTree) overlapsFrom(lBoundIdx, rBoundIdx, from int, lower, upper float64, fn func(pos int) bool) bool {
+	if lBoundIdx > rBoundIdx || rBoundIdx < from {
+		return true
+	}
+
+	if lBoundIdx >= from {
+		return t.overlapsInOrder(lBoundIdx, rBoundIdx, lower, upper, fn)
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+	// Prune the subtree if no upper limit in it reaches the range
+	if lower > t.limits[3*centerIdx+2] {
+		return true
+	}
+
+	if !t.overlapsFrom(lBoundIdx, centerIdx-1, from, lower, upper, fn) {
+		return false
+	}
+
+	// Center and right subtree nodes all start past the range
+	if t.limits[3*centerIdx] > upper {
+		return false
+	}
+
+	if centerIdx >= from && lower <= t.limits[3*centerIdx+1] && !fn(centerIdx) {
+		return false
+	}
+
+	return t.overlapsFrom(centerIdx+1, rBoundIdx, from, lower, upper, fn)
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func pagedIncluding(t *testing.T, tree *intree.INTree, val float64, pageSize int) (result []int, pages int) {
+	result = []int{}
+
+	for cursor := (intree.Cursor{}); !cursor.Done(); pages++ {
+		var page []int
+		page, cursor = tree.IncludingPaged(val, cursor, pageSize)
+		assert.LessOrEqual(t, len(page), pageSize)
+		result = append(result, page...)
+	}
+
+	return result, pages
+}
+
+func Test_Tree_IncludingPaged(t *testing.T) {
+	t.Run("Case_Pages", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(2000, 100.0, 40.0))
+
+		for val := 0.0; val <= 100.0; val += 3.7 {
+			expected := tree.Including(val)
+
+			for _, pageSize := range []int{1, 7, 64, 5000} {
+				result, pages := pagedIncluding(t, tree, val, pageSize)
+				assert.ElementsMatch(t, expected, result, "at %.1f with pages of %d", val, pageSize)
+				assert.EqualValues(t, max((len(expected)+pageSize-1)/pageSize, 1), pages)
+			}
+		}
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		bounds := []intree.Bounds{
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 3.0},
+			intree.Interval{Lower: 0.0, Upper: 4.0},
+		}
+		tree := intree.NewINTree(bounds, intree.WithEndpoints(intree.UpperOpen))
+
+		result, _ := pagedIncluding(t, tree, 2.0, 1)
+		assert.ElementsMatch(t, []int{1, 2}, result)
+	})
+	t.Run("Case_Unordered", func(t *testing.T) {
+		bounds := randomBounds(500, 100.0, 20.0)
+		tree, err := intree.NewINTreeSortedBy(bounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		for val := 0.0; val <= 100.0; val += 9.1 {
+			result, _ := pagedIncluding(t, tree, val, 5)
+			assert.ElementsMatch(t, tree.Including(val), result, "at %.1f", val)
+		}
+	})
+	t.Run("Case_Removed", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		matches := tree.Including(4.3)
+		assert.NotEmpty(t, matches)
+		assert.NoError(t, tree.Remove(matches[0]))
+
+		result, _ := pagedIncluding(t, tree, 4.3, 2)
+		assert.ElementsMatch(t, matches[1:], result)
+	})
+	t.Run("Case_Border/no_matches", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		page, cursor := tree.IncludingPaged(-100.0, intree.Cursor{}, 10)
+		assert.Empty(t, page)
+		assert.True(t, cursor.Done())
+	})
+	t.Run("Case_Border/invalid_page_size", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		page, cursor := tree.IncludingPaged(4.3, intree.Cursor{}, 0)
+		assert.Empty(t, page)
+		assert.False(t, cursor.Done())
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+		page, cursor := tree.IncludingPaged(1.0, intree.Cursor{}, 10)
+		assert.Empty(t, page)
+		assert.True(t, cursor.Done())
+	})
+}
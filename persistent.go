@@ -0,0 +1,241 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+// persistentChunkWords is the number of 64 bit words of every copy-on-write chunk of deleted index flags,
+// so that deletions copy 512 bytes of flags (and the chunk pointers) instead of every flag.
+const persistentChunkWords = 64
+
+// Persistent is an immutable, versioned tree: Insert and Delete return a new version, leaving the receiver
+// untouched, so concurrent readers keep querying older versions without locks.
+// Versions share their nodes structurally: intervals live in immutable segment trees of doubling sizes,
+// merged as in a binary counter (so insertions take O(log² n) amortized time and searches visit O(log n)
+// segments), and deleted index flags are split in copy-on-write chunks. Original indices are given in insertion
+// order and never reused; deleted ones stay taken, matching no search.
+type Persistent struct {
+	segments []*persistentSegment
+	deleted  [][]uint64
+	next     int
+	live     int
+	version  uint64
+	opts     []Option
+	// metadata holds the annotations given to WithMetadata, by original index, shared by every version
+	metadata map[int]any
+}
+
+// persistentSegment is an immutable tree of a Persistent, along with the original index of each of its intervals.
+type persistentSegment struct {
+	tree    *INTree
+	indexes []int
+}
+
+// NewPersistent is the persistent initialization function;
+// creates the first version of the tree from the given Slice of Bounds, building its segments with the given
+// options. Segments number their intervals on their own, so index keyed options are not passed to them:
+// WithMetadata annotations are kept by original index for MetadataFor, while WithPriorities is ignored.
+func NewPersistent(bounds []Bounds, opts ...Option) *Persistent {
+	p := &Persistent{
+		next:     len(bounds),
+		live:     len(bounds),
+		opts:     append(opts[:len(opts):len(opts)], WithDeterministicSort(), WithMetadata(nil), WithPriorities(nil)),
+		metadata: newMetadata(newOptions(opts).metadata, len(bounds)),
+	}
+
+	if len(bounds) > 0 {
+		indexes := make([]int, len(bounds))
+		for i := range indexes {
+			indexes[i] = i
+		}
+
+		p.segments = []*persistentSegment{{tree: NewINTree(bounds, p.opts...), indexes: indexes}}
+	}
+
+	return p
+}
+
+// Len returns the number of intervals of the version, excluding deleted ones.
+func (p *Persistent) Len() int {
+	return p.live
+}
+
+// Version returns the number of updates the version results from, starting at 0 for NewPersistent.
+func (p *Persistent) Version() uint64 {
+	return p.version
+}
+
+// MetadataFor returns the annotation of the interval at the given original index, as given to WithMetadata.
+// Returns false if the interval has no annotation or is not stored in the version.
+func (p *Persistent) MetadataFor(index int) (any, bool) {
+	if index < 0 || index >= p.next || p.isDeleted(index) {
+		return nil, false
+	}
+
+	md, ok := p.metadata[index]
+
+	return md, ok
+}
+
+// Insert returns a new version holding the given interval too, along with its original index: the next one
+// in insertion order.
+func (p *Persistent) Insert(b Bounds) (*Persistent, int) {
+	l, u := b.Limits()
+	index := p.next
+
+	next := *p
+	next.next++
+	next.live++
+	next.version++
+	next.segments = append(p.segments[:len(p.segments):len(p.segments)], p.newSegment([]int{index}, [][2]float64{{l, u}}))
+
+	// Merge trailing segments of similar sizes, so that segment sizes keep doubling
+	for n := len(next.segments); n > 1 && next.segments[n-1].tree.Len() >= next.segments[n-2].tree.Len(); n-- {
+		merged := next.merge(next.segments[n-2], next.segments[n-1])
+		next.segments = append(next.segments[:n-2:n-2], merged)
+	}
+
+	return &next, index
+}
+
+// Delete returns a new version without the interval at the given original index.
+// Returns ErrIndexOutOfRange if the index is not stored in the version.
+func (p *Persistent) Delete(index int) (*Persistent, error) {
+	if index < 0 || index >= p.next || p.isDeleted(index) {
+		return nil, ErrIndexOutOfRange
+	}
+
+	chunk, word := index/(64*persistentChunkWords), index/64%persistentChunkWords
+
+	next := *p
+	next.live--
+	next.version++
+	next.deleted = append([][]uint64(nil), p.deleted...)
+
+	for len(next.deleted) <= chunk {
+		next.deleted = append(next.deleted, nil)
+	}
+
+	flags := make([]uint64, persistentChunkWords)
+	copy(flags, p.chunk(chunk))
+	flags[word] |= 1 << (index % 64)
+	next.deleted[chunk] = flags
+
+	return &next, nil
+}
+
+// Including traverses the version segments and collects intervals that overlap with the given value,
+// boundaries included.
+func (p *Persistent) Including(val float64) []int {
+	return p.Overlapping(val, val)
+}
+
+// IncludingFunc calls fn with the index of every interval that overlaps with the given value,
+// stopping the traversal as soon as fn returns false.
+func (p *Persistent) IncludingFunc(val float64, fn func(idx int) bool) {
+	p.overlapping(val, val, fn)
+}
+
+// Overlapping traverses the version segments and collects intervals that overlap with the given range,
+// boundaries included. Returns an empty Slice if lower is greater than upper.
+func (p *Persistent) Overlapping(lower, upper float64) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	p.overlapping(lower, upper, func(idx int) bool {
+		result = append(result, idx)
+		return true
+	})
+
+	return result
+}
+
+// overlapping is an internal utility function, calling fn with the index of every interval not deleted
+// that overlaps with the given range, stopping as soon as fn returns false.
+func (p *Persistent) overlapping(lower, upper float64, fn func(idx int) bool) {
+	for _, s := range p.segments {
+		stop := false
+
+		s.tree.traverse(lower, upper, func(pos int) bool {
+			idx := s.indexes[s.tree.indexes[pos]]
+			if p.isDeleted(idx) {
+				return true
+			}
+
+			stop = !fn(idx)
+
+			return !stop
+		})
+
+		if stop {
+			return
+		}
+	}
+}
+
+// newSegment is an internal utility function, building an immutable segment from the given original indices
+// and their limits.
+func (p *Persistent) newSegment(indexes []int, limits [][2]float64) *persistentSegment {
+	bounds := make([]Bounds, len(limits))
+	for i, l := range limits {
+		bounds[i] = Interval{Lower: l[0], Upper: l[1]}
+	}
+
+	return &persistentSegment{tree: NewINTree(bounds, p.opts...), indexes: indexes}
+}
+
+// merge is an internal utility function, building a new segment from the intervals of the given segments,
+// dropping the deleted (and removed) ones.
+func (p *Persistent) merge(a, b *persistentSegment) *persistentSegment {
+	indexes := make([]int, 0, len(a.indexes)+len(b.indexes))
+	limits := make([][2]float64, 0, len(a.indexes)+len(b.indexes))
+
+	for _, s := range [2]*persistentSegment{a, b} {
+		s.tree.liveIntervals(func(i int, lower, upper float64) {
+			if !p.isDeleted(s.indexes[i]) {
+				indexes = append(indexes, s.indexes[i])
+				limits = append(limits, [2]float64{lower, upper})
+			}
+		})
+	}
+
+	return p.newSegment(indexes, limits)
+}
+
+// chunk is an internal utility function, returning the deleted index flags chunk at the given position,
+// or nil if no index of the chunk was deleted.
+func (p *Persistent) chunk(chunk int) []uint64 {
+	if chunk >= len(p.deleted) {
+		return nil
+	}
+
+	return p.deleted[chunk]
+}
+
+// isDeleted is an internal utility function, reporting whether the given original index was deleted.
+func (p *Persistent) isDeleted(index int) bool {
+	flags := p.chunk(index / (64 * persistentChunkWords))
+
+	return flags != nil && flags[index/64%persistentChunkWords]&(1<<(index%64)) != 0
+}
@@ -0,0 +1,177 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Persistent(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		v0 := intree.NewPersistent(exampleBounds())
+		v1, index := v0.Insert(&testBounds{Lower: 4.2, Upper: 4.4})
+		v2, err := v1.Delete(0)
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, 13, index)
+		assert.EqualValues(t, []uint64{0, 1, 2}, []uint64{v0.Version(), v1.Version(), v2.Version()})
+		assert.EqualValues(t, []int{13, 14, 13}, []int{v0.Len(), v1.Len(), v2.Len()})
+
+		// Older versions are left untouched
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, v0.Including(4.3))
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10, 13}, v1.Including(4.3))
+		assert.ElementsMatch(t, []int{2, 5, 8, 10, 13}, v2.Including(4.3))
+		assert.Empty(t, v2.Overlapping(4.3, 4.2))
+
+		calls := 0
+		v2.IncludingFunc(4.3, func(idx int) bool {
+			calls++
+			return false
+		})
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_Metadata", func(t *testing.T) {
+		v0 := intree.NewPersistent(exampleBounds(),
+			intree.WithMetadata(map[int]any{0: "a", 5: "f", 13: "n"}), intree.WithPriorities([]int{1, 2, 3}))
+		v1, index := v0.Insert(&testBounds{Lower: 4.2, Upper: 4.4})
+		v2, err := v1.Delete(0)
+		assert.NoError(t, err)
+
+		// The inserted interval comes first in its segment, and must not take the annotation of index 0
+		md, ok := v2.MetadataFor(5)
+		assert.True(t, ok)
+		assert.EqualValues(t, "f", md)
+
+		_, ok = v2.MetadataFor(index)
+		assert.False(t, ok)
+		_, ok = v2.MetadataFor(0)
+		assert.False(t, ok)
+		_, ok = v2.MetadataFor(-1)
+		assert.False(t, ok)
+
+		md, ok = v1.MetadataFor(0)
+		assert.True(t, ok)
+		assert.EqualValues(t, "a", md)
+
+		v3, _ := v2.Insert(&testBounds{Lower: 0.0, Upper: 1.0})
+		assert.ElementsMatch(t, []int{2, 5, 8, 10, 13}, v3.Including(4.3))
+	})
+	t.Run("Case_Randomized/versions", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(11))
+		versions := []*intree.Persistent{intree.NewPersistent(nil, intree.WithEndpoints(intree.UpperOpen))}
+		models := [][][2]float64{{}}
+		deleted := []map[int]bool{{}}
+
+		for step := 0; step < 3000; step++ {
+			last := len(versions) - 1
+			p, model, dead := versions[last], models[last], map[int]bool{}
+			for idx := range deleted[last] {
+				dead[idx] = true
+			}
+
+			if len(model) > 0 && rnd.Intn(4) == 0 {
+				index := rnd.Intn(len(model))
+				next, err := p.Delete(index)
+
+				if dead[index] {
+					assert.ErrorIs(t, err, intree.ErrIndexOutOfRange)
+					continue
+				}
+
+				assert.NoError(t, err)
+				dead[index] = true
+				p = next
+			} else {
+				lower := rnd.Float64() * 100.0
+				upper := lower + rnd.Float64()*5.0
+
+				var index int
+				p, index = p.Insert(&testBounds{Lower: lower, Upper: upper})
+				assert.EqualValues(t, len(model), index)
+				model = append(model[:len(model):len(model)], [2]float64{lower, upper})
+			}
+
+			versions, models, deleted = append(versions, p), append(models, model), append(deleted, dead)
+		}
+
+		// Every version answers as of its own updates
+		for v := 0; v < len(versions); v += 97 {
+			assert.EqualValues(t, len(models[v])-len(deleted[v]), versions[v].Len())
+
+			for _, val := range []float64{rnd.Float64() * 100.0, rnd.Float64() * 100.0} {
+				expected := []int{}
+				for i, interval := range models[v] {
+					if !deleted[v][i] && interval[0] <= val && val < interval[1] {
+						expected = append(expected, i)
+					}
+				}
+
+				assert.ElementsMatch(t, expected, versions[v].Including(val), "version %d, value %v", v, val)
+			}
+		}
+	})
+	t.Run("Case_Concurrent_readers", func(t *testing.T) {
+		p := intree.NewPersistent(randomBounds(1000, 100.0, 5.0))
+		expected := p.Including(50.0)
+
+		var wg sync.WaitGroup
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := 0; i < 200; i++ {
+					assert.ElementsMatch(t, expected, p.Including(50.0))
+				}
+			}()
+		}
+
+		next := p
+		for i := 0; i < 500; i++ {
+			next, _ = next.Insert(&testBounds{Lower: 49.0, Upper: 51.0})
+			next, _ = next.Delete(i)
+		}
+
+		wg.Wait()
+		assert.EqualValues(t, 1000, next.Len())
+	})
+	t.Run("Case_Border/out_of_range", func(t *testing.T) {
+		p := intree.NewPersistent(exampleBounds())
+		next, err := p.Delete(3)
+		assert.NoError(t, err)
+
+		for _, index := range []int{-1, 13} {
+			_, err := p.Delete(index)
+			assert.ErrorIs(t, err, intree.ErrIndexOutOfRange)
+		}
+
+		_, err = next.Delete(3)
+		assert.ErrorIs(t, err, intree.ErrIndexOutOfRange)
+		assert.Empty(t, intree.NewPersistent(nil).Including(0.0))
+	})
+}
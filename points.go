@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// SplitTree is an INTree counterpart for datasets of mostly point events (intervals whose lower limit equals
+// their upper limit): points are kept apart in a sorted array searched by binary search, taking 16 bytes each,
+// and only true ranges are stored in the tree. Returns indices to the initial bounds array.
+type SplitTree struct {
+	tree *INTree
+	// ranges maps the tree indices to the original ones
+	ranges []int
+	// points and pointIndexes hold the point values in ascending order, and their original indices
+	points       []float64
+	pointIndexes []int
+	endpoints    Endpoints
+}
+
+// NewSplitTree is the point splitting initialization function;
+// creates the tree from the given Slice of Bounds, storing point intervals apart from the range ones.
+// Options apply to the tree of ranges; the endpoints apply to points as well, so that points only match
+// with closed endpoints.
+func NewSplitTree(bounds []Bounds, opts ...Option) *SplitTree {
+	t := SplitTree{endpoints: newOptions(opts).endpoints}
+	ranges := []Bounds{}
+
+	for i, b := range bounds {
+		if l, u := b.Limits(); l == u {
+			t.points = append(t.points, l)
+			t.pointIndexes = append(t.pointIndexes, i)
+
+			continue
+		}
+
+		ranges = append(ranges, b)
+		t.ranges = append(t.ranges, i)
+	}
+
+	t.tree = NewINTree(ranges, opts...)
+
+	// Sort the points along with their indices, keeping equal points in ascending index order
+	order := make([]int, len(t.points))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(i, j int) int { return cmp.Compare(t.points[i], t.points[j]) })
+
+	points, indexes := make([]float64, len(order)), make([]int, len(order))
+	for i, o := range order {
+		points[i], indexes[i] = t.points[o], t.pointIndexes[o]
+	}
+
+	t.points, t.pointIndexes = points, indexes
+
+	return &t
+}
+
+// Len returns the number of intervals stored in the tree, points included.
+func (t *SplitTree) Len() int {
+	return len(t.ranges) + len(t.points)
+}
+
+// Points returns the number of point intervals stored apart from the tree.
+func (t *SplitTree) Points() int {
+	return len(t.points)
+}
+
+// Including traverses the tree and collects the intervals that overlap with the given value: the matching
+// ranges in traversal order, followed by the points equal to it in ascending index order.
+func (t *SplitTree) Including(val float64) []int {
+	return t.Overlapping(val, val)
+}
+
+// Overlapping traverses the tree and collects the intervals that overlap with the given range: the matching
+// ranges in traversal order, followed by the points within it in ascending value order.
+// Returns an empty Slice if lower is greater than upper.
+func (t *SplitTree) Overlapping(lower, upper float64) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	t.tree.traverse(lower, upper, func(pos int) bool {
+		result = append(result, t.ranges[t.tree.indexes[pos]])
+		return true
+	})
+
+	from, to := t.pointRange(lower, upper)
+
+	return append(result, t.pointIndexes[from:to]...)
+}
+
+// CountIncluding returns the number of intervals that overlap with the given value, without collecting them.
+func (t *SplitTree) CountIncluding(val float64) int {
+	from, to := t.pointRange(val, val)
+
+	return t.tree.CountIncluding(val) + to - from
+}
+
+// pointRange is an internal utility function, returning the bounds of the points within the given range.
+// Points never match with open endpoints, as they exclude their only value.
+func (t *SplitTree) pointRange(lower, upper float64) (from, to int) {
+	if t.endpoints != Closed {
+		return 0, 0
+	}
+
+	from, _ = slices.BinarySearch(t.points, lower)
+	to, _ = slices.BinarySearch(t.points, math.Nextafter(upper, math.Inf(1)))
+
+	return from, max(from, to)
+}
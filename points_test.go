@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+// pointEvents returns n intervals drawn from a fixed seed, of which about nine in ten are points
+// on whole values in [0, domain).
+func pointEvents(n int, domain float64) []intree.Bounds {
+	rnd := rand.New(rand.NewSource(11))
+	bounds := make([]intree.Bounds, n)
+
+	for i := range bounds {
+		lower := float64(rnd.Intn(int(domain)))
+		if rnd.Intn(10) == 0 {
+			bounds[i] = intree.Interval{Lower: lower, Upper: lower + rnd.Float64()*10.0}
+		} else {
+			bounds[i] = intree.Interval{Lower: lower, Upper: lower}
+		}
+	}
+
+	return bounds
+}
+
+func Test_SplitTree(t *testing.T) {
+	t.Run("Case_Reference", func(t *testing.T) {
+		bounds := pointEvents(5000, 1000.0)
+		tree := intree.NewSplitTree(bounds)
+		reference := intree.NewINTree(bounds)
+
+		assert.EqualValues(t, len(bounds), tree.Len())
+		assert.Greater(t, tree.Points(), len(bounds)/2)
+
+		for val := -1.0; val <= 1001.0; val += 0.5 {
+			assert.ElementsMatch(t, reference.Including(val), tree.Including(val), "at %.1f", val)
+			assert.EqualValues(t, reference.CountIncluding(val), tree.CountIncluding(val), "at %.1f", val)
+		}
+
+		for lower := 0.0; lower <= 1000.0; lower += 37.5 {
+			assert.ElementsMatch(t, reference.Overlapping(lower, lower+20.0), tree.Overlapping(lower, lower+20.0))
+		}
+	})
+	t.Run("Case_Order", func(t *testing.T) {
+		tree := intree.NewSplitTree([]intree.Bounds{
+			intree.Interval{Lower: 2.0, Upper: 2.0},
+			intree.Interval{Lower: 0.0, Upper: 5.0},
+			intree.Interval{Lower: 1.0, Upper: 1.0},
+			intree.Interval{Lower: 2.0, Upper: 2.0},
+		})
+
+		assert.EqualValues(t, 3, tree.Points())
+		assert.EqualValues(t, 4, tree.Len())
+		assert.EqualValues(t, []int{1, 0, 3}, tree.Including(2.0))
+		assert.EqualValues(t, []int{1, 2, 0, 3}, tree.Overlapping(0.5, 3.0))
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		bounds := []intree.Bounds{
+			intree.Interval{Lower: 2.0, Upper: 2.0},
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+		}
+
+		for _, e := range []intree.Endpoints{intree.Closed, intree.LowerOpen, intree.UpperOpen, intree.Open} {
+			tree := intree.NewSplitTree(bounds, intree.WithEndpoints(e))
+			reference := intree.NewINTree(bounds, intree.WithEndpoints(e))
+
+			for _, val := range []float64{1.0, 1.5, 2.0} {
+				assert.ElementsMatch(t, reference.Including(val), tree.Including(val), "%v at %.1f", e, val)
+			}
+		}
+	})
+	t.Run("Case_Border/invalid_range", func(t *testing.T) {
+		tree := intree.NewSplitTree(pointEvents(100, 10.0))
+		assert.Empty(t, tree.Overlapping(5.0, 4.0))
+		assert.Empty(t, intree.NewSplitTree(nil).Including(1.0))
+	})
+}
+
+func Benchmark_SplitTree(b *testing.B) {
+	bounds := pointEvents(100000, 100000.0)
+	tree := intree.NewSplitTree(bounds)
+	reference := intree.NewINTree(bounds)
+
+	b.Run("INTree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			reference.Including(float64(i % 100000))
+		}
+	})
+	b.Run("SplitTree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tree.Including(float64(i % 100000))
+		}
+	})
+}
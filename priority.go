@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "math"
+
+// FirstByPriority returns the original index of the highest priority interval among those that overlap with
+// the given value, as set by WithPriorities, breaking ties by the lowest index. Subtrees whose greatest priority
+// is below the best match found so far are pruned, so that values covered by many low priority intervals do not
+// require visiting every match. Returns false if no interval matches.
+func (t *INTree) FirstByPriority(val float64) (int, bool) {
+	if t.priorities == nil {
+		return t.MaxIncluding(val, func(index int) float64 {
+			return float64(t.priority(index))
+		})
+	}
+
+	if t.unordered {
+		t = t.lowerOrdered()
+	}
+
+	lower, upper := t.searchRange(val, val)
+	index, best := -1, math.MinInt
+
+	// Nodes buffered by Insert are scanned first, their best match bounding the sorted ones searched next
+	t.scanBuffered(0, lower, upper, func(pos int) bool {
+		if idx, p := t.indexes[pos], t.priorities[t.indexes[pos]]; index < 0 || p > best || p == best && idx < index {
+			index, best = idx, p
+		}
+
+		return true
+	})
+	t.priorityFirst(0, t.treeLen()-1, lower, upper, &index, &best)
+
+	return index, index >= 0
+}
+
+// Priority returns the priority of the interval at the given original index, as set by WithPriorities or
+// SetPriority. Returns ErrIndexOutOfRange if the index is not stored in the tree.
+func (t *INTree) Priority(index int) (int, error) {
+	if t.position(index) < 0 {
+		return 0, ErrIndexOutOfRange
+	}
+
+	return t.priority(index), nil
+}
+
+// SetPriority sets the priority of the interval at the given original index, restoring the subtree priorities
+// along its path in O(log n) time. Returns ErrIndexOutOfRange if the index is not stored in the tree.
+// Updates must not run concurrently with queries.
+func (t *INTree) SetPriority(index int, priority int) error {
+	pos := t.position(index)
+	if pos < 0 {
+		return ErrIndexOutOfRange
+	}
+
+	if t.priorities == nil {
+		// Every priority is 0, and so is the greatest one of every subtree
+		t.priorities = make([]int, len(t.indexes))
+		t.priorityMax = make([]int, len(t.indexes))
+		t.sortView()
+	}
+
+	t.priorities[index] = priority
+	t.repairPath(pos, t.repairPriority)
+
+	if t.view != nil {
+		view := t.lowerOrdered()
+		view.repairPath(view.positions[index], view.repairPriority)
+	}
+
+	return nil
+}
+
+// repairPriority is an internal utility function, recomputing the greatest priority of the subtree
+// of the node at the given center position from its children, as called by repairPath.
+func (t *INTree) repairPriority(lBoundIdx, centerIdx, rBoundIdx int) {
+	p := t.priorities[t.indexes[centerIdx]]
+
+	if lBoundIdx < centerIdx {
+		p = max(p, t.priorityMax[(lBoundIdx+centerIdx)>>1])
+	}
+
+	if centerIdx < rBoundIdx {
+		p = max(p, t.priorityMax[(centerIdx+rBoundIdx+2)>>1])
+	}
+
+	t.priorityMax[centerIdx] = p
+}
+
+// priority is an internal utility function, returning the priority of the interval at the given original index.
+func (t *INTree) priority(index int) int {
+	if t.priorities == nil {
+		return 0
+	}
+
+	return t.priorities[index]
+}
+
+// setPriorities is an internal utility function, copying the given priorities by original index, padded with
+// zeroes up to the tree length, and computing the subtree priorities. Does nothing if none are given.
+func (t *INTree) setPriorities(priorities []int) {
+	if priorities == nil {
+		return
+	}
+
+	t.priorities = make([]int, len(t.indexes))
+	copy(t.priorities, priorities)
+	t.augmentPriorities()
+}
+
+// augmentPriorities is an internal utility function, computing the greatest priority of every node subtree.
+func (t *INTree) augmentPriorities() {
+	if t.priorities == nil {
+		t.priorityMax = nil
+		return
+	}
+
+	t.priorityMax = make([]int, len(t.indexes))
+	t.augmentPriority(0, len(t.indexes)-1)
+}
+
+// augmentPriority is an internal utility function, computing the greatest priority of the node subtrees
+// in the given bounds, returning the one of the subtree root.
+func (t *INTree) augmentPriority(lBoundIdx, rBoundIdx int) int {
+	if lBoundIdx > rBoundIdx {
+		return math.MinInt
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+	p := max(
+		t.priorities[t.indexes[centerIdx]],
+		t.augmentPriority(lBoundIdx, centerIdx-1),
+		t.augmentPriority(centerIdx+1, rBoundIdx),
+	)
+	t.priorityMax[centerIdx] = p
+
+	return p
+}
+
+// priorityFirst is an internal utility function, updating the given best index and priority with the
+// matching nodes in the given bounds, pruning the subtrees that cannot hold a better one.
+func (t *INTree) priorityFirst(lBoundIdx, rBoundIdx int, lower, upper float64, index, best *int) {
+	if lBoundIdx > rBoundIdx {
+		return
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+	// Prune the subtree if no upper limit in it reaches the value, or no priority in it is high enough
+	if lower > t.limits[3*centerIdx+2] || t.priorityMax[centerIdx] < *best {
+		return
+	}
+
+	t.priorityFirst(lBoundIdx, centerIdx-1, lower, upper, index, best)
+
+	// Center and right subtree nodes all start past the value
+	if t.limits[3*centerIdx] > upper {
+		return
+	}
+
+	if lower <= t.limits[3*centerIdx+1] {
+		idx := t.indexes[centerIdx]
+
+		if p := t.priorities[idx]; *index < 0 || p > *best || p == *best && idx < *index {
+			*index, *best = idx, p
+		}
+	}
+
+	t.priorityFirst(centerIdx+1, rBoundIdx, lower, upper, index, best)
+}
@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_FirstByPriority(t *testing.T) {
+	t.Run("Case_Rules", func(t *testing.T) {
+		// Pricing tiers, where narrower promotions take precedence over the base price
+		tree := intree.NewINTree([]intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 100.0},
+			intree.Interval{Lower: 10.0, Upper: 20.0},
+			intree.Interval{Lower: 15.0, Upper: 30.0},
+			intree.Interval{Lower: 15.0, Upper: 16.0},
+		}, intree.WithPriorities([]int{0, 10, 5, 10}))
+
+		for _, tc := range []struct {
+			val      float64
+			expected int
+		}{{5.0, 0}, {12.0, 1}, {15.5, 1}, {17.0, 1}, {25.0, 2}, {50.0, 0}} {
+			idx, ok := tree.FirstByPriority(tc.val)
+			assert.True(t, ok)
+			assert.EqualValues(t, tc.expected, idx, "at %.1f", tc.val)
+		}
+
+		_, ok := tree.FirstByPriority(200.0)
+		assert.False(t, ok)
+	})
+	t.Run("Case_Reference", func(t *testing.T) {
+		bounds := randomBounds(2000, 100.0, 30.0)
+		rnd := rand.New(rand.NewSource(7))
+		priorities := make([]int, len(bounds))
+		for i := range priorities {
+			priorities[i] = rnd.Intn(20) - 10
+		}
+
+		tree := intree.NewINTree(bounds, intree.WithPriorities(priorities))
+		valid := func(t *testing.T, tree *intree.INTree) {
+			assert.NoError(t, tree.CheckInvariants())
+
+			for val := 0.0; val <= 100.0; val += 1.7 {
+				expected, ok := tree.MaxIncluding(val, func(index int) float64 {
+					p, _ := tree.Priority(index)
+					return float64(p)
+				})
+
+				idx, found := tree.FirstByPriority(val)
+				assert.EqualValues(t, ok, found)
+				assert.EqualValues(t, expected, idx, "at %.1f", val)
+			}
+		}
+
+		valid(t, tree)
+
+		// Priorities follow updates
+		assert.NoError(t, tree.SetPriority(17, 100))
+		assert.NoError(t, tree.Delete(3))
+		tree.Insert(intree.Interval{Lower: 40.0, Upper: 60.0})
+		assert.NoError(t, tree.Remove(100))
+		valid(t, tree)
+
+		p, err := tree.Priority(17)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 100, p)
+		p, _ = tree.Priority(tree.Len() - 1)
+		assert.EqualValues(t, 0, p)
+
+		valid(t, tree.Clone())
+	})
+	t.Run("Case_SetPriority", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		matches := tree.IncludingSorted(4.3)
+
+		idx, _ := tree.FirstByPriority(4.3)
+		assert.EqualValues(t, matches[0], idx)
+
+		assert.NoError(t, tree.SetPriority(matches[2], 1))
+		idx, _ = tree.FirstByPriority(4.3)
+		assert.EqualValues(t, matches[2], idx)
+
+		assert.ErrorIs(t, tree.SetPriority(-1, 1), intree.ErrIndexOutOfRange)
+		_, err := tree.Priority(tree.Len())
+		assert.ErrorIs(t, err, intree.ErrIndexOutOfRange)
+	})
+	t.Run("Case_Unordered", func(t *testing.T) {
+		bounds := randomBounds(200, 100.0, 30.0)
+		tree, err := intree.NewINTreeSortedBy(bounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+		assert.NoError(t, tree.SetPriority(42, 5))
+
+		l, u := bounds[42].Limits()
+		idx, ok := tree.FirstByPriority((l + u) / 2)
+		assert.True(t, ok)
+		assert.EqualValues(t, 42, idx)
+
+		// Later updates repair the subtree priorities of the lower ordered view along their path
+		reference := intree.NewINTree(bounds)
+		for i := range bounds {
+			p := (i * 7) % 11
+			assert.NoError(t, tree.SetPriority(i, p))
+			assert.NoError(t, reference.SetPriority(i, p))
+		}
+
+		for val := 0.0; val <= 100.0; val += 2.5 {
+			expected, found := reference.FirstByPriority(val)
+			idx, ok := tree.FirstByPriority(val)
+			assert.EqualValues(t, found, ok)
+			assert.EqualValues(t, expected, idx, "at %.1f", val)
+		}
+	})
+}
@@ -0,0 +1,619 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"iter"
+	"math"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// ErrMatchLimit is returned by IncludingCtx when more intervals than the given limit match.
+var ErrMatchLimit = errors.New("intree: match limit exceeded")
+
+// ctxCheckInterval is the number of matches collected by IncludingCtx between context checks.
+const ctxCheckInterval = 64
+
+// batchChunkSize is the minimum number of query points evaluated by each goroutine in IncludingBatch.
+const batchChunkSize = 256
+
+// Overlapping is the entry point for range searches;
+// traverses the tree and collects intervals that overlap with the given range, boundaries included.
+// Returns an empty Slice if lower is greater than upper.
+func (t *INTree) Overlapping(lower, upper float64) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	t.traverse(lower, upper, func(pos int) bool {
+		result = append(result, t.indexes[pos])
+		return true
+	})
+
+	return result
+}
+
+// Intersecting is an alias of Overlapping;
+// collects intervals that overlap with the given range, pruning subtrees by their augmented limits.
+func (t *INTree) Intersecting(lo, hi float64) []int {
+	return t.Overlapping(lo, hi)
+}
+
+// Covering traverses the tree and collects intervals that fully contain the given range, boundaries included.
+// Returns an empty Slice if lo is greater than hi.
+func (t *INTree) Covering(lo, hi float64) []int {
+	result := []int{}
+
+	if lo > hi {
+		return result
+	}
+
+	// Swapping the range limits turns the overlap conditions into containment ones:
+	// upper limits reaching hi and lower limits not past lo
+	t.traverse(hi, lo, func(pos int) bool {
+		result = append(result, t.indexes[pos])
+		return true
+	})
+
+	return result
+}
+
+// CoveredBy traverses the tree and collects intervals entirely inside the given range, boundaries included.
+// Returns an empty Slice if lo is greater than hi.
+func (t *INTree) CoveredBy(lo, hi float64) []int {
+	result := []int{}
+
+	if lo > hi {
+		return result
+	}
+
+	t.traverse(lo, hi, func(pos int) bool {
+		if lo <= t.limits[3*pos] && t.limits[3*pos+1] <= hi {
+			result = append(result, t.indexes[pos])
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// IncludingWithin is the rounding tolerant counterpart of Including;
+// collects intervals that overlap with the range [val-epsilon, val+epsilon], so that values off an interval
+// limit by floating point rounding still match it. A negative epsilon is treated as zero.
+func (t *INTree) IncludingWithin(val, epsilon float64) []int {
+	epsilon = math.Max(epsilon, 0)
+
+	return t.Overlapping(val-epsilon, val+epsilon)
+}
+
+// IncludingFunc is the allocation free counterpart of Including;
+// calls fn with the index of every interval that overlaps with the given value, in the same order,
+// and stops the traversal as soon as fn returns false.
+func (t *INTree) IncludingFunc(val float64, fn func(idx int) bool) {
+	t.traverse(val, val, func(pos int) bool {
+		return fn(t.indexes[pos])
+	})
+}
+
+// IncludingSeq is the iterator counterpart of IncludingFunc;
+// yields the index of every interval that overlaps with the given value, in the same order as Including.
+func (t *INTree) IncludingSeq(val float64) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		t.IncludingFunc(val, yield)
+	}
+}
+
+// IncludingInto is the buffered counterpart of Including;
+// truncates the given buffer and appends the indices of the matching intervals to it, reusing its capacity.
+func (t *INTree) IncludingInto(val float64, buf []int) []int {
+	buf = buf[:0]
+
+	t.traverse(val, val, func(pos int) bool {
+		buf = append(buf, t.indexes[pos])
+		return true
+	})
+
+	return buf
+}
+
+// IncludingSorted is the deterministic counterpart of Including, whose matches come in traversal order,
+// which depends on the tree layout; collects the same indices sorted in ascending original index order.
+func (t *INTree) IncludingSorted(val float64) []int {
+	result := t.Including(val)
+	slices.Sort(result)
+
+	return result
+}
+
+// IncludingByLower is the lower limit ordered counterpart of IncludingSorted;
+// collects the indices of the matching intervals sorted by ascending lower limit, then by original index.
+func (t *INTree) IncludingByLower(val float64) []int {
+	result := t.Including(val)
+
+	slices.SortFunc(result, func(a, b int) int {
+		if c := cmp.Compare(t.limits[3*t.positions[a]], t.limits[3*t.positions[b]]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	return result
+}
+
+// IncludingCtx is the bounded counterpart of Including, protecting latency sensitive callers against
+// pathological high overlap values. Stops the traversal once the given context is done, returning the matches
+// collected so far along with the context error, or once more than limit intervals match, returning the first
+// limit matches along with ErrMatchLimit. A zero or negative limit disables the latter.
+func (t *INTree) IncludingCtx(ctx context.Context, val float64, limit int) ([]int, error) {
+	result := []int{}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	var err error
+
+	t.traverse(val, val, func(pos int) bool {
+		if limit > 0 && len(result) == limit {
+			err = ErrMatchLimit
+			return false
+		}
+
+		result = append(result, t.indexes[pos])
+
+		if len(result)%ctxCheckInterval == 0 {
+			err = ctx.Err()
+		}
+
+		return err == nil
+	})
+
+	return result, err
+}
+
+// IncludingBatch evaluates Including for every given value, returning the matches of each value at
+// the same position. Large batches are split across goroutines; results do not depend on the split.
+func (t *INTree) IncludingBatch(vals []float64) [][]int {
+	result := make([][]int, len(vals))
+
+	workers := runtime.GOMAXPROCS(0)
+	if max := (len(vals) + batchChunkSize - 1) / batchChunkSize; workers > max {
+		workers = max
+	}
+
+	if workers <= 1 {
+		for i, val := range vals {
+			result[i] = t.Including(val)
+		}
+
+		return result
+	}
+
+	var wg sync.WaitGroup
+	chunk := (len(vals) + workers - 1) / workers
+
+	for start := 0; start < len(vals); start += chunk {
+		end := start + chunk
+		if end > len(vals) {
+			end = len(vals)
+		}
+
+		wg.Add(1)
+
+		go func(start, end int) {
+			defer wg.Done()
+
+			for i := start; i < end; i++ {
+				result[i] = t.Including(vals[i])
+			}
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	return result
+}
+
+// IncludingAll evaluates Including for every given value in a single call, returning the matches of each value
+// at the same position and in the same order as Including. All results share a single backing Slice,
+// so the number of allocations does not grow with the number of values; results have their capacity capped
+// to their length, so that appending to one does not overwrite another.
+func (t *INTree) IncludingAll(vals []float64) [][]int {
+	buf := []int{}
+	ends := make([]int, len(vals))
+
+	for i, val := range vals {
+		t.traverse(val, val, func(pos int) bool {
+			buf = append(buf, t.indexes[pos])
+			return true
+		})
+
+		ends[i] = len(buf)
+	}
+
+	return splitResults(buf, ends)
+}
+
+// IncludingAllSorted is the sorted input counterpart of IncludingAll, for values in ascending order;
+// sweeps the values along the nodes with a Sweeper instead of searching the tree for each one.
+// Takes O((n + m) log n) time plus the size of the results for m values, matching the intervals of each value
+// in no particular order. Falls back to IncludingAll if the values are not sorted.
+func (t *INTree) IncludingAllSorted(vals []float64) [][]int {
+	if !slices.IsSorted(vals) {
+		return t.IncludingAll(vals)
+	}
+
+	s := NewSweeper(t)
+	buf := []int{}
+	ends := make([]int, len(vals))
+
+	for i, val := range vals {
+		s.IncludingFunc(val, func(idx int) bool {
+			buf = append(buf, idx)
+			return true
+		})
+
+		ends[i] = len(buf)
+	}
+
+	return splitResults(buf, ends)
+}
+
+// splitResults is an internal utility function, splitting the given shared buffer at the given result ends.
+func splitResults(buf []int, ends []int) [][]int {
+	result := make([][]int, len(ends))
+	start := 0
+
+	for i, end := range ends {
+		result[i] = buf[start:end:end]
+		start = end
+	}
+
+	return result
+}
+
+// Excluding is the complement counterpart of Including;
+// collects the indices of the intervals that do not overlap with the given value, in ascending index order.
+// Only the matches are searched for, flagging them so that the complement is collected in a single pass
+// instead of testing every interval against the value. Removed intervals are left out.
+func (t *INTree) Excluding(val float64) []int {
+	matched := make([]bool, len(t.indexes))
+	count := 0
+
+	t.traverse(val, val, func(pos int) bool {
+		matched[pos] = true
+		count++
+
+		return true
+	})
+
+	result := make([]int, 0, len(t.indexes)-t.removed-count)
+
+	for idx, pos := range t.positions {
+		if !matched[pos] && !t.isRemoved(pos) {
+			result = append(result, idx)
+		}
+	}
+
+	return result
+}
+
+// IncludingAllOf is the set intersection counterpart of IncludingAll, for intervals available at every
+// given time; collects intervals that overlap with every given value. As intervals are convex, these are the
+// intervals covering the range between the least and greatest values, found by a single Covering search.
+// Returns an empty Slice if no value is given or any of them is NaN.
+func (t *INTree) IncludingAllOf(vals []float64) []int {
+	if len(vals) == 0 || slices.ContainsFunc(vals, math.IsNaN) {
+		return []int{}
+	}
+
+	return t.Covering(slices.Min(vals), slices.Max(vals))
+}
+
+// IncludingAnyOf is the set union counterpart of IncludingAll; collects intervals that overlap with at least
+// one of the given values, each of them once, in traversal order. A single search over the range between
+// the least and greatest values visits every candidate once, checking it against the sorted values
+// by binary search instead of merging the results of each value. NaN values are ignored.
+func (t *INTree) IncludingAnyOf(vals []float64) []int {
+	result := []int{}
+
+	sorted := slices.DeleteFunc(slices.Clone(vals), math.IsNaN)
+	if len(sorted) == 0 {
+		return result
+	}
+
+	slices.Sort(sorted)
+
+	t.traverse(sorted[0], sorted[len(sorted)-1], func(pos int) bool {
+		lower, upper := t.limits[3*pos], t.limits[3*pos+1]
+
+		if t.endpoints&LowerOpen != 0 {
+			lower = math.Nextafter(lower, math.Inf(1))
+		}
+
+		if t.endpoints&UpperOpen != 0 {
+			upper = math.Nextafter(upper, math.Inf(-1))
+		}
+
+		// First value not below the interval lower limit
+		if i, _ := slices.BinarySearch(sorted, lower); i < len(sorted) && sorted[i] <= upper {
+			result = append(result, t.indexes[pos])
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// CountIncluding returns the number of intervals that overlap with the given value, without collecting them.
+func (t *INTree) CountIncluding(val float64) int {
+	count := 0
+
+	t.traverse(val, val, func(int) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+// AnyIncluding is the short-circuiting counterpart of Including, for boolean coverage checks;
+// returns the index of the first interval found to overlap with the given value, stopping the traversal there.
+// Returns false if no interval matches.
+func (t *INTree) AnyIncluding(val float64) (int, bool) {
+	index := -1
+
+	t.traverse(val, val, func(pos int) bool {
+		index = t.indexes[pos]
+		return false
+	})
+
+	return index, index >= 0
+}
+
+// IncludingBySide traverses the tree and partitions the intervals that overlap with the given value by
+// the side of their midpoint the value falls on: below it for leftOfCenter, at or above it for rightOfCenter.
+func (t *INTree) IncludingBySide(val float64) (leftOfCenter, rightOfCenter []int) {
+	leftOfCenter, rightOfCenter = []int{}, []int{}
+
+	t.traverse(val, val, func(pos int) bool {
+		l, u := t.limits[3*pos], t.limits[3*pos+1]
+
+		if val < l+(u-l)/2 {
+			leftOfCenter = append(leftOfCenter, t.indexes[pos])
+		} else {
+			rightOfCenter = append(rightOfCenter, t.indexes[pos])
+		}
+
+		return true
+	})
+
+	return leftOfCenter, rightOfCenter
+}
+
+// CoversIndex reports whether the interval at the given original index overlaps with the given value.
+// Checks the stored interval directly instead of searching the tree; returns false for an out of range index.
+func (t *INTree) CoversIndex(val float64, index int) bool {
+	pos := t.position(index)
+	if pos < 0 {
+		return false
+	}
+
+	lower, upper := t.searchRange(val, val)
+
+	return t.limits[3*pos] <= upper && lower <= t.limits[3*pos+1]
+}
+
+// Nearest returns the index of the interval closest to the given value, where the distance from a value
+// to an interval is zero inside it and the distance to its nearest limit outside it.
+// When several intervals are at the same distance (including several intervals overlapping with the value),
+// the lowest original index wins. Returns (0, false) for an empty tree.
+// Finding the closest intervals takes O(log n) time for distinct limits, degrading to O(n) with many ties
+// or removed intervals.
+func (t *INTree) Nearest(val float64) (index int, ok bool) {
+	if index, _ = t.nearest(val); index < 0 {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// NearestDistance behaves like Nearest, returning the distance from the value to the closest interval
+// along with its index: zero if an interval includes the value, the distance to its closest limit otherwise.
+// Returns (-1, +Inf) for an empty tree.
+func (t *INTree) NearestDistance(val float64) (index int, distance float64) {
+	return t.nearest(val)
+}
+
+// nearest is an internal utility function, returning the index of the interval closest to the given value
+// and its distance, or (-1, +Inf) for an empty tree.
+func (t *INTree) nearest(val float64) (index int, distance float64) {
+	index, distance = -1, math.Inf(1)
+
+	if len(t.indexes) == 0 {
+		return index, distance
+	}
+
+	// Removed intervals hold limits no search matches, but still bound the distances below
+	if t.removed > 0 {
+		return t.nearestLive(val)
+	}
+
+	t = t.lowerOrdered()
+
+	t.traverse(val, val, func(pos int) bool {
+		if index < 0 || t.indexes[pos] < index {
+			index = t.indexes[pos]
+		}
+
+		return true
+	})
+
+	if index >= 0 {
+		return index, 0
+	}
+
+	// As no interval overlaps with the value, every interval starting at or below it ends below it
+	above := t.searchLower(val)
+	below := t.greatestUpper(0, len(t.indexes)-1, above, -1)
+
+	if below >= 0 {
+		distance = val - t.limits[3*below+1]
+		index = t.indexes[below]
+	}
+
+	// Ties above the value share the same lower limit, which are contiguous in sorted order
+	for pos := above; pos < len(t.indexes) && t.limits[3*pos] == t.limits[3*above]; pos++ {
+		d := t.limits[3*pos] - val
+
+		if d < distance || (d == distance && t.indexes[pos] < index) {
+			distance = d
+			index = t.indexes[pos]
+		}
+	}
+
+	return index, distance
+}
+
+// nearestLive is an internal utility function, the linear counterpart of nearest skipping removed intervals.
+func (t *INTree) nearestLive(val float64) (index int, distance float64) {
+	index, distance = -1, math.Inf(1)
+
+	for pos, idx := range t.indexes {
+		if t.isRemoved(pos) {
+			continue
+		}
+
+		d := math.Max(0, math.Max(t.limits[3*pos]-val, val-t.limits[3*pos+1]))
+
+		if d < distance || (d == distance && idx < index) {
+			distance = d
+			index = idx
+		}
+	}
+
+	return index, distance
+}
+
+// KNearest returns the indices of the k intervals closest to the given value, ordered by distance, where
+// the distance from a value to an interval is zero inside it and the distance to its nearest limit outside it.
+// Ties are broken by lowest original index. Returns every interval if k is greater than the number of intervals not removed,
+// and an empty Slice if k is not positive. Takes O(n log k) time.
+func (t *INTree) KNearest(val float64, k int) []int {
+	if k > len(t.indexes)-t.removed {
+		k = len(t.indexes) - t.removed
+	}
+
+	if k <= 0 {
+		return []int{}
+	}
+
+	h := make(candidateHeap, 0, k)
+
+	for pos, idx := range t.indexes {
+		if t.isRemoved(pos) {
+			continue
+		}
+
+		c := candidate{index: idx, distance: math.Max(0, math.Max(t.limits[3*pos]-val, val-t.limits[3*pos+1]))}
+
+		if len(h) < k {
+			heap.Push(&h, c)
+		} else if c.closerThan(h[0]) {
+			h[0] = c
+			heap.Fix(&h, 0)
+		}
+	}
+
+	result := make([]int, len(h))
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(&h).(candidate).index
+	}
+
+	return result
+}
+
+// greatestUpper is an internal utility function, returning the position of the node with the greatest upper
+// limit among the positions in the given bounds that are lower than end, or best if none beats it.
+// Ties are broken by lowest original index. Prunes subtrees whose augmented limit is below the best
+// upper limit found so far.
+func (t *INTree) greatestUpper(lBoundIdx, rBoundIdx, end, best int) int {
+	if lBoundIdx > rBoundIdx || lBoundIdx >= end {
+		return best
+	}
+
+	centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+
+	if best >= 0 && t.limits[3*centerIdx+2] < t.limits[3*best+1] {
+		return best
+	}
+
+	if centerIdx < end {
+		if best < 0 || t.limits[3*centerIdx+1] > t.limits[3*best+1] ||
+			(t.limits[3*centerIdx+1] == t.limits[3*best+1] && t.indexes[centerIdx] < t.indexes[best]) {
+			best = centerIdx
+		}
+	}
+
+	best = t.greatestUpper(centerIdx+1, rBoundIdx, end, best)
+
+	return t.greatestUpper(lBoundIdx, centerIdx-1, end, best)
+}
+
+// candidate is an internal utility type, holding an interval index along with its distance to a value.
+type candidate struct {
+	index    int
+	distance float64
+}
+
+// closerThan reports whether the candidate comes before the given one: lower distance, then lower index.
+func (c candidate) closerThan(o candidate) bool {
+	return c.distance < o.distance || (c.distance == o.distance && c.index < o.index)
+}
+
+// candidateHeap is an internal max-heap of candidates, keeping the farthest one on top.
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int            { return len(h) }
+func (h candidateHeap) Less(i, j int) bool  { return h[j].closerThan(h[i]) }
+func (h candidateHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+
+func (h *candidateHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return c
+}
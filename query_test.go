@@ -0,0 +1,1011 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Overlapping(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		matches := tree.Overlapping(8.95, 10.0)
+		assert.ElementsMatch(t, []int{4}, matches)
+
+		matches = tree.Overlapping(3.05, 3.15)
+		assert.ElementsMatch(t, []int{5, 11}, matches)
+
+		matches = tree.Overlapping(0.0, 1.0)
+		assert.ElementsMatch(t, []int{3}, matches)
+
+		matches = tree.Overlapping(9.5, 10.0)
+		assert.EqualValues(t, 0, len(matches))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+		matches := tree.Overlapping(1.0, 4.3)
+		assert.EqualValues(t, 0, len(matches))
+	})
+	t.Run("Case_Border/degenerate_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		for _, val := range []float64{0.5, 1.0, 3.0, 4.3, 6.0, 7.9, 9.0, 9.5} {
+			assert.ElementsMatch(t, tree.Including(val), tree.Overlapping(val, val))
+		}
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		matches := tree.Overlapping(6.0, 4.0)
+		assert.NotNil(t, matches)
+		assert.EqualValues(t, 0, len(matches))
+	})
+	t.Run("Case_Border/overlap_at_boundary", func(t *testing.T) {
+		inputBounds := []intree.Bounds{
+			&testBounds{Lower: 4.0, Upper: 6.0},
+			&testBounds{Lower: 6.0, Upper: 9.0},
+			&testBounds{Lower: 9.0, Upper: 11.0},
+		}
+
+		tree := intree.NewINTree(inputBounds)
+
+		matches := tree.Overlapping(6.0, 6.0)
+		assert.ElementsMatch(t, []int{0, 1}, matches)
+
+		matches = tree.Overlapping(1.0, 4.0)
+		assert.ElementsMatch(t, []int{0}, matches)
+
+		matches = tree.Overlapping(11.0, 12.0)
+		assert.ElementsMatch(t, []int{2}, matches)
+
+		matches = tree.Overlapping(6.0, 9.0)
+		assert.ElementsMatch(t, []int{0, 1, 2}, matches)
+	})
+}
+
+func Test_Tree_IncludingBySide(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		// Midpoints 5.0, 6.0, 4.5, 5.35 and 4.5 all lie above 4.3
+		left, right := tree.IncludingBySide(4.3)
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, left)
+		assert.EqualValues(t, 0, len(right))
+	})
+	t.Run("Case_Example/both_sides", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		// [4,6] has its midpoint at 5.0 exactly and goes to the right side
+		left, right := tree.IncludingBySide(5.0)
+		assert.ElementsMatch(t, []int{1, 2, 8}, left)
+		assert.ElementsMatch(t, []int{0, 5, 9}, right)
+		assert.ElementsMatch(t, tree.Including(5.0), append(left, right...))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		left, right := intree.NewINTree(nil).IncludingBySide(4.3)
+		assert.EqualValues(t, 0, len(left))
+		assert.EqualValues(t, 0, len(right))
+	})
+}
+
+func Test_Tree_Intersecting(t *testing.T) {
+	tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+
+	for lo := -2.0; lo < 102.0; lo += 3.3 {
+		assert.EqualValues(t, tree.Overlapping(lo, lo+1.5), tree.Intersecting(lo, lo+1.5))
+	}
+
+	assert.EqualValues(t, 0, len(tree.Intersecting(5.0, 4.0)))
+}
+
+func Test_Tree_Covering(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.ElementsMatch(t, []int{0, 2, 5, 8}, tree.Covering(4.3, 5.5))
+		assert.ElementsMatch(t, []int{2}, tree.Covering(4.0, 8.0))
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.Covering(4.3, 4.3))
+		assert.EqualValues(t, 0, len(tree.Covering(0.0, 9.0)))
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		bounds := randomBounds(1000, 100.0, 20.0)
+		tree := intree.NewINTree(bounds)
+
+		for lo := -2.0; lo < 102.0; lo += 3.3 {
+			hi := lo + 4.0
+			expected := []int{}
+			for i, b := range bounds {
+				l, u := b.Limits()
+				if l <= lo && hi <= u {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.Covering(lo, hi))
+		}
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.EqualValues(t, 0, len(tree.Covering(5.5, 4.3)))
+	})
+}
+
+func Test_Tree_CoveredBy(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.ElementsMatch(t, []int{9, 10}, tree.CoveredBy(4.1, 5.1))
+		assert.ElementsMatch(t, []int{3, 6, 11}, tree.CoveredBy(0.0, 3.1))
+		assert.EqualValues(t, 0, len(tree.CoveredBy(4.3, 4.3)))
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		bounds := randomBounds(1000, 100.0, 5.0)
+		tree := intree.NewINTree(bounds)
+
+		for lo := -2.0; lo < 102.0; lo += 3.3 {
+			hi := lo + 8.0
+			expected := []int{}
+			for i, b := range bounds {
+				l, u := b.Limits()
+				if lo <= l && u <= hi {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.ElementsMatch(t, expected, tree.CoveredBy(lo, hi))
+		}
+	})
+	t.Run("Case_Border/inverted_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.EqualValues(t, 0, len(tree.CoveredBy(5.1, 4.1)))
+	})
+}
+
+func Test_Tree_CoversIndex(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		inputBounds := exampleBounds()
+		tree := intree.NewINTree(inputBounds)
+
+		for _, val := range []float64{0.5, 1.0, 3.0, 4.3, 6.0, 7.9, 9.0, 9.5} {
+			matches := map[int]bool{}
+			for _, matchedIndex := range tree.Including(val) {
+				matches[matchedIndex] = true
+			}
+
+			for index := range inputBounds {
+				assert.EqualValues(t, matches[index], tree.CoversIndex(val, index), "index %d at %.1f", index, val)
+			}
+		}
+	})
+	t.Run("Case_Border/out_of_range", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.False(t, tree.CoversIndex(4.3, -1))
+		assert.False(t, tree.CoversIndex(4.3, 13))
+		assert.False(t, intree.NewINTree(nil).CoversIndex(4.3, 0))
+	})
+}
+
+func Test_Tree_IncludingWithin(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.ElementsMatch(t, tree.Including(4.3), tree.IncludingWithin(4.3, 0))
+		assert.ElementsMatch(t, tree.Overlapping(4.0, 4.6), tree.IncludingWithin(4.3, 0.3))
+	})
+	t.Run("Case_Border/fine_grained", func(t *testing.T) {
+		inputBounds := []intree.Bounds{
+			&testBounds{Lower: 3.43567981e-21, Upper: 3.43567984e-21},
+			&testBounds{Lower: 3.43567987e-21, Upper: 3.43567990e-21},
+		}
+
+		tree := intree.NewINTree(inputBounds)
+
+		assert.Empty(t, tree.IncludingWithin(3.43567985e-21, 0.5e-29))
+		assert.ElementsMatch(t, []int{0}, tree.IncludingWithin(3.43567985e-21, 1.5e-29))
+		assert.ElementsMatch(t, []int{0, 1}, tree.IncludingWithin(3.43567985e-21, 2.5e-29))
+
+		// 0.1 + 0.2 rounds above 0.3, missing an interval ending there
+		a, b := 0.1, 0.2
+		rounded := intree.NewINTree([]intree.Bounds{&testBounds{Lower: 0.1, Upper: 0.3}})
+		assert.Empty(t, rounded.Including(a+b))
+		assert.ElementsMatch(t, []int{0}, rounded.IncludingWithin(a+b, 1e-12))
+	})
+	t.Run("Case_Border/negative_epsilon", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.ElementsMatch(t, tree.Including(4.3), tree.IncludingWithin(4.3, -1.0))
+	})
+}
+
+func Test_Tree_IncludingFunc(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		matches := []int{}
+		tree.IncludingFunc(4.3, func(idx int) bool {
+			matches = append(matches, idx)
+			return true
+		})
+
+		assert.EqualValues(t, tree.Including(4.3), matches)
+	})
+	t.Run("Case_Example/early_exit", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		calls := 0
+		tree.IncludingFunc(4.3, func(idx int) bool {
+			calls++
+			return false
+		})
+
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_Example/zero_allocs", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(10000, 1000.0, 10.0))
+
+		count := 0
+		allocs := testing.AllocsPerRun(100, func() {
+			tree.IncludingFunc(500.0, func(idx int) bool {
+				count++
+				return true
+			})
+		})
+
+		assert.EqualValues(t, 0, allocs)
+		assert.NotZero(t, count)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+
+		calls := 0
+		tree.IncludingFunc(4.3, func(idx int) bool {
+			calls++
+			return true
+		})
+
+		assert.EqualValues(t, 0, calls)
+	})
+}
+
+func Test_Tree_IncludingSeq(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		matches := []int{}
+		for idx := range tree.IncludingSeq(4.3) {
+			matches = append(matches, idx)
+		}
+
+		assert.EqualValues(t, tree.Including(4.3), matches)
+	})
+	t.Run("Case_Example/early_exit", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		calls := 0
+		for range tree.IncludingSeq(4.3) {
+			calls++
+			break
+		}
+
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		for range intree.NewINTree(nil).IncludingSeq(4.3) {
+			t.Fail()
+		}
+	})
+}
+
+func Test_Tree_IncludingCtx(t *testing.T) {
+	tree := intree.NewINTree(randomBounds(10000, 1000.0, 50.0))
+	expected := tree.Including(500.0)
+
+	t.Run("Case_Unbounded", func(t *testing.T) {
+		result, err := tree.IncludingCtx(context.Background(), 500.0, 0)
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, result)
+
+		result, err = tree.IncludingCtx(context.Background(), 500.0, len(expected))
+		assert.NoError(t, err)
+		assert.EqualValues(t, expected, result)
+	})
+	t.Run("Case_Limit", func(t *testing.T) {
+		result, err := tree.IncludingCtx(context.Background(), 500.0, 10)
+		assert.True(t, errors.Is(err, intree.ErrMatchLimit))
+		assert.EqualValues(t, expected[:10], result)
+	})
+	t.Run("Case_Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := tree.IncludingCtx(ctx, 500.0, 0)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Empty(t, result)
+	})
+	t.Run("Case_Cancelled/during_traversal", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(10000, 1.0, 1000.0))
+		assert.Greater(t, tree.CountIncluding(0.5), 200)
+
+		// Passes the upfront check and the one after 64 matches, then reports the cancellation after 128
+		ctx := &countdownCtx{Context: context.Background(), checks: 2}
+
+		result, err := tree.IncludingCtx(ctx, 0.5, 0)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.EqualValues(t, 128, len(result))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		result, err := intree.NewINTree(nil).IncludingCtx(context.Background(), 1.0, 5)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func Test_Tree_IncludingSorted(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.EqualValues(t, []int{0, 2, 5, 8, 10}, tree.IncludingSorted(4.3))
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		bounds := randomBounds(10000, 1000.0, 50.0)
+
+		for _, tree := range []*intree.INTree{
+			intree.NewINTree(bounds),
+			intree.NewINTree(bounds, intree.WithEytzingerLayout()),
+		} {
+			for val := 0.0; val <= 1000.0; val += 13.3 {
+				result := tree.IncludingSorted(val)
+				assert.True(t, slices.IsSorted(result))
+				assert.ElementsMatch(t, tree.Including(val), result)
+
+				byLower := tree.IncludingByLower(val)
+				assert.ElementsMatch(t, result, byLower)
+				assert.True(t, slices.IsSortedFunc(byLower, func(a, b int) int {
+					la, _ := bounds[a].Limits()
+					lb, _ := bounds[b].Limits()
+
+					return cmp.Or(cmp.Compare(la, lb), cmp.Compare(a, b))
+				}))
+			}
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(nil)
+		assert.Empty(t, tree.IncludingSorted(1.0))
+		assert.Empty(t, tree.IncludingByLower(1.0))
+	})
+}
+
+func Test_Tree_AnyIncluding(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		index, ok := tree.AnyIncluding(4.3)
+		assert.True(t, ok)
+		assert.Contains(t, []int{0, 2, 5, 8, 10}, index)
+
+		index, ok = tree.AnyIncluding(100.0)
+		assert.False(t, ok)
+		assert.EqualValues(t, -1, index)
+	})
+	t.Run("Case_Random_bounds", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 1000.0, 2.0))
+
+		for val := 0.0; val <= 1000.0; val += 0.7 {
+			index, ok := tree.AnyIncluding(val)
+			assert.EqualValues(t, tree.CountIncluding(val) > 0, ok, "at %.1f", val)
+
+			if ok {
+				assert.Contains(t, tree.Including(val), index)
+			}
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		_, ok := intree.NewINTree(nil).AnyIncluding(1.0)
+		assert.False(t, ok)
+	})
+}
+
+// countdownCtx is a context cancelled once its Err method was called the given number of checks.
+type countdownCtx struct {
+	context.Context
+	checks int
+}
+
+func (c *countdownCtx) Err() error {
+	if c.checks == 0 {
+		return context.Canceled
+	}
+
+	c.checks--
+
+	return nil
+}
+
+func Test_Tree_IncludingInto(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		buf := make([]int, 3, 16)
+		buf = tree.IncludingInto(4.3, buf)
+		assert.EqualValues(t, tree.Including(4.3), buf)
+		assert.EqualValues(t, 16, cap(buf))
+
+		buf = tree.IncludingInto(9.5, buf)
+		assert.EqualValues(t, 0, len(buf))
+		assert.EqualValues(t, 16, cap(buf))
+	})
+	t.Run("Case_Example/zero_allocs", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(10000, 1000.0, 10.0))
+		buf := make([]int, 0, 1024)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = tree.IncludingInto(500.0, buf)
+		})
+
+		assert.EqualValues(t, 0, allocs)
+		assert.NotZero(t, len(buf))
+	})
+	t.Run("Case_Border/nil_buffer", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.ElementsMatch(t, []int{0, 2, 5, 8, 10}, tree.IncludingInto(4.3, nil))
+	})
+}
+
+func Test_Tree_IncludingBatch(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		vals := []float64{0.5, 1.0, 3.0, 4.3, 6.0, 7.9, 9.0, 9.5}
+
+		result := tree.IncludingBatch(vals)
+
+		assert.EqualValues(t, len(vals), len(result))
+		for i, val := range vals {
+			assert.EqualValues(t, tree.Including(val), result[i])
+		}
+	})
+	t.Run("Case_Concurrent", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(5000, 1000.0, 10.0))
+
+		vals := make([]float64, 4096)
+		for i := range vals {
+			vals[i] = float64(i%1100) - 50.0
+		}
+
+		// Run several batches at once, each of them split across goroutines
+		results := make([][][]int, 4)
+		done := make(chan int)
+
+		for i := range results {
+			go func(i int) {
+				results[i] = tree.IncludingBatch(vals)
+				done <- i
+			}(i)
+		}
+
+		for range results {
+			<-done
+		}
+
+		for _, result := range results {
+			assert.EqualValues(t, len(vals), len(result))
+			for i, val := range vals {
+				assert.EqualValues(t, tree.Including(val), result[i])
+			}
+		}
+	})
+	t.Run("Case_Border/empty", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.EqualValues(t, 0, len(tree.IncludingBatch(nil)))
+	})
+}
+
+func Test_Tree_IncludingAll(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		vals := []float64{4.3, 0.5, 9.5, 1.0, 7.9}
+
+		result := tree.IncludingAll(vals)
+
+		assert.EqualValues(t, len(vals), len(result))
+		for i, val := range vals {
+			assert.EqualValues(t, tree.Including(val), result[i])
+		}
+	})
+	t.Run("Case_Example/capped_results", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		result := tree.IncludingAll([]float64{4.3, 4.3})
+		_ = append(result[0], -1)
+
+		assert.EqualValues(t, tree.Including(4.3), result[1])
+	})
+	t.Run("Case_Sorted", func(t *testing.T) {
+		for _, e := range []intree.Endpoints{intree.Closed, intree.UpperOpen} {
+			bounds := randomBounds(2000, 100.0, 5.0)
+			tree := intree.NewINTree(bounds, intree.WithEndpoints(e))
+
+			vals := []float64{}
+			for val := -1.0; val <= 106.0; val += 0.25 {
+				vals = append(vals, val, val)
+			}
+
+			result := tree.IncludingAllSorted(vals)
+
+			assert.EqualValues(t, len(vals), len(result))
+			for i, val := range vals {
+				assert.ElementsMatch(t, tree.Including(val), result[i], "%s at %.2f", e, val)
+			}
+		}
+	})
+	t.Run("Case_Sorted/custom_order", func(t *testing.T) {
+		bounds := exampleBounds()
+		tree, err := intree.NewINTreeSortedBy(bounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		result := tree.IncludingAllSorted([]float64{1.0, 4.3, 9.5})
+		assert.ElementsMatch(t, tree.Including(4.3), result[1])
+	})
+	t.Run("Case_Sorted/unsorted_fallback", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		vals := []float64{4.3, 0.5, 9.5}
+
+		assert.EqualValues(t, tree.IncludingAll(vals), tree.IncludingAllSorted(vals))
+	})
+	t.Run("Case_Border/empty", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.Empty(t, tree.IncludingAll(nil))
+		assert.Empty(t, tree.IncludingAllSorted(nil))
+
+		result := intree.NewINTree(nil).IncludingAllSorted([]float64{1.0, 2.0})
+		assert.EqualValues(t, [][]int{{}, {}}, result)
+	})
+}
+
+func Benchmark_IncludingAll(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+	vals := make([]float64, 1000)
+	for i := range vals {
+		vals[i] = float64(i)
+	}
+
+	b.Run("Including", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, val := range vals {
+				tree.Including(val)
+			}
+		}
+	})
+	b.Run("IncludingAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tree.IncludingAll(vals)
+		}
+	})
+	b.Run("IncludingAllSorted", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tree.IncludingAllSorted(vals)
+		}
+	})
+}
+
+func Test_Tree_Excluding(t *testing.T) {
+	t.Run("Case_Complement", func(t *testing.T) {
+		bounds := randomBounds(1000, 100.0, 10.0)
+		tree := intree.NewINTree(bounds)
+
+		for val := -1.0; val <= 101.0; val += 6.1 {
+			expected := []int{}
+			for i, b := range bounds {
+				if l, u := b.Limits(); val < l || val > u {
+					expected = append(expected, i)
+				}
+			}
+
+			assert.EqualValues(t, expected, tree.Excluding(val), "at %.1f", val)
+		}
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 3.0},
+		}, intree.WithEndpoints(intree.UpperOpen))
+
+		assert.EqualValues(t, []int{0}, tree.Excluding(2.0))
+	})
+	t.Run("Case_Removed", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		excluded := tree.Excluding(4.3)
+		assert.NotEmpty(t, excluded)
+		assert.NoError(t, tree.Remove(excluded[0]))
+
+		assert.EqualValues(t, excluded[1:], tree.Excluding(4.3))
+		assert.NotContains(t, tree.Excluding(-100.0), excluded[0])
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		assert.Empty(t, intree.NewINTree(nil).Excluding(1.0))
+	})
+}
+
+func Test_Tree_IncludingAllOf(t *testing.T) {
+	t.Run("Case_Intersection", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 20.0))
+
+		for _, vals := range [][]float64{{50.0}, {10.0, 12.5}, {40.0, 30.0, 35.0}, {1.0, 99.0}} {
+			expected := tree.IncludingSorted(vals[0])
+			for _, val := range vals[1:] {
+				including := tree.Including(val)
+				expected = slices.DeleteFunc(expected, func(idx int) bool { return !slices.Contains(including, idx) })
+			}
+
+			assert.ElementsMatch(t, expected, tree.IncludingAllOf(vals), "at %v", vals)
+		}
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 0.0, Upper: 3.0},
+		}, intree.WithEndpoints(intree.UpperOpen))
+
+		assert.ElementsMatch(t, []int{1}, tree.IncludingAllOf([]float64{1.0, 2.0}))
+		assert.ElementsMatch(t, []int{0, 1}, tree.IncludingAllOf([]float64{1.0, 1.5}))
+	})
+	t.Run("Case_Border/no_values", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.Empty(t, tree.IncludingAllOf(nil))
+		assert.Empty(t, tree.IncludingAllOf([]float64{4.3, math.NaN()}))
+	})
+}
+
+func Test_Tree_IncludingAnyOf(t *testing.T) {
+	t.Run("Case_Union", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+
+		for _, vals := range [][]float64{{50.0}, {10.0, 12.5}, {40.0, 30.0, 35.0, 30.0}, {1.0, 99.0}} {
+			expected := []int{}
+			for _, val := range vals {
+				for _, idx := range tree.Including(val) {
+					if !slices.Contains(expected, idx) {
+						expected = append(expected, idx)
+					}
+				}
+			}
+
+			result := tree.IncludingAnyOf(vals)
+			assert.ElementsMatch(t, expected, result, "at %v", vals)
+			assert.EqualValues(t, len(result), len(slices.Compact(slices.Sorted(slices.Values(result)))))
+		}
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 2.0, Upper: 3.0},
+			intree.Interval{Lower: 5.0, Upper: 6.0},
+		}, intree.WithEndpoints(intree.Open))
+
+		assert.ElementsMatch(t, []int{}, tree.IncludingAnyOf([]float64{2.0, 5.0}))
+		assert.ElementsMatch(t, []int{1, 2}, tree.IncludingAnyOf([]float64{2.5, 5.5}))
+	})
+	t.Run("Case_Border/no_values", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.Empty(t, tree.IncludingAnyOf(nil))
+		assert.ElementsMatch(t, tree.Including(4.3), tree.IncludingAnyOf([]float64{math.NaN(), 4.3}))
+	})
+}
+
+func Test_Tree_CountIncluding(t *testing.T) {
+	tree := intree.NewINTree(exampleBounds())
+
+	for _, val := range []float64{0.5, 1.0, 3.0, 4.3, 6.0, 7.9, 9.0, 9.5} {
+		assert.EqualValues(t, len(tree.Including(val)), tree.CountIncluding(val))
+	}
+
+	assert.EqualValues(t, 5, tree.CountIncluding(4.3))
+	assert.EqualValues(t, 0, intree.NewINTree(nil).CountIncluding(4.3))
+
+	// Counting must not materialize the matches
+	large := intree.NewINTree(randomBounds(10000, 1000.0, 10.0))
+	allocs := testing.AllocsPerRun(100, func() {
+		large.CountIncluding(500.0)
+	})
+	assert.EqualValues(t, 0, allocs)
+}
+
+func Benchmark_CountIncluding(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.CountIncluding(float64(i % 1000))
+	}
+}
+
+// randomBounds returns n intervals with lower limits in [0, domain) and lengths in [0, maxLength),
+// drawn from a fixed seed.
+func randomBounds(n int, domain, maxLength float64) []intree.Bounds {
+	rnd := rand.New(rand.NewSource(42))
+	bounds := make([]intree.Bounds, n)
+
+	for i := range bounds {
+		lower := rnd.Float64() * domain
+		bounds[i] = &testBounds{Lower: lower, Upper: lower + rnd.Float64()*maxLength}
+	}
+
+	return bounds
+}
+
+func Benchmark_Including(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.Including(float64(i % 1000))
+	}
+}
+
+func Benchmark_IncludingFunc(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+	count := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.IncludingFunc(float64(i%1000), func(idx int) bool {
+			count++
+			return true
+		})
+	}
+}
+
+func Benchmark_IncludingInto(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	// Every goroutine reuses its own scratch buffer, as a high throughput service would
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]int, 0, 64)
+
+		for i := 0; pb.Next(); i++ {
+			buf = tree.IncludingInto(float64(i%1000), buf)
+		}
+	})
+}
+
+func Benchmark_IncludingSeq(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+	count := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for range tree.IncludingSeq(float64(i % 1000)) {
+			count++
+		}
+	}
+}
+
+func Test_Tree_Nearest(t *testing.T) {
+	inputBounds := []intree.Bounds{
+		&testBounds{Lower: 10.0, Upper: 12.0},
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 5.0, Upper: 6.0},
+		&testBounds{Lower: 1.0, Upper: 2.0},
+		&testBounds{Lower: 14.0, Upper: 15.0},
+	}
+
+	t.Run("Case_Example/including", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		index, ok := tree.Nearest(5.5)
+		assert.True(t, ok)
+		assert.EqualValues(t, 2, index)
+
+		// Lowest index among the matches
+		index, ok = tree.Nearest(1.5)
+		assert.True(t, ok)
+		assert.EqualValues(t, 1, index)
+	})
+	t.Run("Case_Example/gap", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		index, ok := tree.Nearest(3.0)
+		assert.True(t, ok)
+		assert.EqualValues(t, 1, index) // [0,2] and [1,2] tie at distance 1, lowest index wins
+
+		index, ok = tree.Nearest(4.5)
+		assert.True(t, ok)
+		assert.EqualValues(t, 2, index)
+
+		index, ok = tree.Nearest(8.5)
+		assert.True(t, ok)
+		assert.EqualValues(t, 0, index)
+	})
+	t.Run("Case_Example/outside", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		index, ok := tree.Nearest(-3.0)
+		assert.True(t, ok)
+		assert.EqualValues(t, 1, index)
+
+		index, ok = tree.Nearest(100.0)
+		assert.True(t, ok)
+		assert.EqualValues(t, 4, index)
+	})
+	t.Run("Case_Tie/lowest_index", func(t *testing.T) {
+		// 13 is equidistant from [10,12] (index 0) and [14,15] (index 4)
+		tree := intree.NewINTree(inputBounds)
+
+		index, ok := tree.Nearest(13.0)
+		assert.True(t, ok)
+		assert.EqualValues(t, 0, index)
+
+		// Same distance with the lowest index being above the value
+		tree = intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 4.0, Upper: 5.0},
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 4.0, Upper: 6.0},
+		})
+
+		index, ok = tree.Nearest(3.0)
+		assert.True(t, ok)
+		assert.EqualValues(t, 0, index)
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		// Also covers trees entirely over negative coordinates
+		for _, offset := range []float64{0.0, -2000.0} {
+			bounds := randomBounds(500, 1000.0, 1.0)
+			for i, b := range bounds {
+				l, u := b.Limits()
+				bounds[i] = &testBounds{Lower: l + offset, Upper: u + offset}
+			}
+
+			tree := intree.NewINTree(bounds)
+
+			for val := offset - 5.0; val < offset+1005.0; val += 0.37 {
+				expected, best := -1, 0.0
+				for i, b := range bounds {
+					l, u := b.Limits()
+					d := 0.0
+					if val < l {
+						d = l - val
+					} else if val > u {
+						d = val - u
+					}
+
+					if expected < 0 || d < best {
+						expected, best = i, d
+					}
+				}
+
+				index, ok := tree.Nearest(val)
+				assert.True(t, ok)
+				assert.EqualValues(t, expected, index, "at %.2f", val)
+
+				index, distance := tree.NearestDistance(val)
+				assert.EqualValues(t, expected, index, "at %.2f", val)
+				assert.InDelta(t, best, distance, 1e-9, "at %.2f", val)
+			}
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		index, ok := intree.NewINTree(nil).Nearest(4.3)
+		assert.False(t, ok)
+		assert.EqualValues(t, 0, index)
+	})
+}
+
+func Test_Tree_NearestDistance(t *testing.T) {
+	inputBounds := []intree.Bounds{
+		&testBounds{Lower: 10.0, Upper: 12.0},
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 5.0, Upper: 6.0},
+	}
+
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		index, distance := tree.NearestDistance(5.5)
+		assert.EqualValues(t, 2, index)
+		assert.EqualValues(t, 0.0, distance)
+
+		index, distance = tree.NearestDistance(4.5)
+		assert.EqualValues(t, 2, index)
+		assert.EqualValues(t, 0.5, distance)
+
+		index, distance = tree.NearestDistance(15.0)
+		assert.EqualValues(t, 0, index)
+		assert.EqualValues(t, 3.0, distance)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		index, distance := intree.NewINTree(nil).NearestDistance(4.3)
+		assert.EqualValues(t, -1, index)
+		assert.True(t, math.IsInf(distance, 1))
+	})
+}
+
+func Test_Tree_KNearest(t *testing.T) {
+	inputBounds := []intree.Bounds{
+		&testBounds{Lower: 10.0, Upper: 12.0},
+		&testBounds{Lower: 0.0, Upper: 2.0},
+		&testBounds{Lower: 5.0, Upper: 6.0},
+		&testBounds{Lower: 1.0, Upper: 2.0},
+		&testBounds{Lower: 14.0, Upper: 15.0},
+	}
+
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+
+		assert.EqualValues(t, []int{2}, tree.KNearest(5.5, 1))
+		assert.EqualValues(t, []int{2, 1, 3}, tree.KNearest(4.0, 3))
+		// [10,12] and [14,15] tie at distance 1, lowest index first
+		assert.EqualValues(t, []int{0, 4, 2, 1, 3}, tree.KNearest(13.0, 10))
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		bounds := randomBounds(500, 1000.0, 1.0)
+		tree := intree.NewINTree(bounds)
+
+		for val := -5.0; val < 1005.0; val += 7.3 {
+			result := tree.KNearest(val, 20)
+			assert.Len(t, result, 20)
+
+			// The first result must agree with Nearest, and distances must not decrease
+			index, _ := tree.NearestDistance(val)
+			assert.EqualValues(t, index, result[0])
+
+			prev := -1.0
+			for _, idx := range result {
+				l, u := bounds[idx].Limits()
+				d := math.Max(0, math.Max(l-val, val-u))
+				assert.True(t, d >= prev, "at %.2f", val)
+				prev = d
+			}
+		}
+	})
+	t.Run("Case_Border/non_positive_k", func(t *testing.T) {
+		tree := intree.NewINTree(inputBounds)
+		assert.Empty(t, tree.KNearest(4.3, 0))
+		assert.Empty(t, tree.KNearest(4.3, -1))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.Empty(t, intree.NewINTree(nil).KNearest(4.3, 3))
+	})
+}
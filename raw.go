@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"fmt"
+	"math"
+)
+
+// Raw exposes the internal node arrays for zero-copy interop with other languages consuming the same index:
+// the original index of every node, and its lower, upper and augmented limits (3 values per node), both in
+// node order. The limits Slice is shared with the tree, unless intervals were buffered by Insert, and must not be
+// modified; the indexes are converted into a new Slice. Returns an ErrInvalidEncoding wrapped error if the tree holds 2^31 intervals or more.
+func (t *INTree) Raw() (indexes []int32, limits []float64, err error) {
+	t = t.merged()
+
+	if len(t.indexes) > math.MaxInt32 {
+		return nil, nil, fmt.Errorf("%w: %d intervals do not fit raw indexes", ErrInvalidEncoding, len(t.indexes))
+	}
+
+	indexes = make([]int32, len(t.indexes))
+	for pos, idx := range t.indexes {
+		indexes[pos] = int32(idx)
+	}
+
+	return indexes, t.limits, nil
+}
+
+// NewINTreeFromRaw is the raw initialization function, the counterpart of Raw;
+// creates the tree from the given node arrays, adopting the limits Slice without copying it, so it must not be
+// modified afterwards. Nodes are checked as decoded ones are, and pruning is disabled if they are not sorted by
+// lower limit. Only the endpoints and layout options apply. Returns an ErrInvalidEncoding wrapped error if the
+// indexes are not a permutation or the lengths do not match, also wrapping ErrCorruptedTree if the nodes fail Validate.
+func NewINTreeFromRaw(indexes []int32, limits []float64, opts ...Option) (*INTree, error) {
+	if len(limits) != 3*len(indexes) {
+		return nil, fmt.Errorf("%w: length mismatch between %d indexes and %d limits", ErrInvalidEncoding,
+			len(indexes), len(limits))
+	}
+
+	nodes := make([]int, len(indexes))
+	for pos, idx := range indexes {
+		nodes[pos] = int(idx)
+	}
+
+	o := newOptions(opts)
+	tree := &INTree{}
+
+	if err := tree.setDecodedNodes(header{endpoints: o.endpoints}, nodes, limits); err != nil {
+		return nil, err
+	}
+
+	if o.eytzingerLayout {
+		tree.layout = newEytzinger(tree.limits)
+	}
+
+	return tree, nil
+}
@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Raw(t *testing.T) {
+	t.Run("Case_Roundtrip", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(1000, 100.0, 5.0))
+		indexes, limits, err := tree.Raw()
+		assert.NoError(t, err)
+		assert.EqualValues(t, tree.Len(), len(indexes))
+		assert.EqualValues(t, 3*tree.Len(), len(limits))
+
+		for _, opts := range [][]intree.Option{nil, {intree.WithEytzingerLayout()}} {
+			adopted, err := intree.NewINTreeFromRaw(indexes, limits, opts...)
+			assert.NoError(t, err)
+			assert.EqualValues(t, tree.Intervals(), adopted.Intervals())
+
+			for val := -1.0; val <= 106.0; val += 0.25 {
+				assert.ElementsMatch(t, tree.Including(val), adopted.Including(val))
+			}
+		}
+	})
+	t.Run("Case_Zero_copy", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		indexes, limits, err := tree.Raw()
+		assert.NoError(t, err)
+
+		adopted, err := intree.NewINTreeFromRaw(indexes, limits, intree.WithEndpoints(intree.Open))
+		assert.NoError(t, err)
+		assert.EqualValues(t, intree.Open, adopted.Endpoints())
+
+		_, adoptedLimits, err := adopted.Raw()
+		assert.NoError(t, err)
+		assert.True(t, &limits[0] == &adoptedLimits[0])
+	})
+	t.Run("Case_Custom_order", func(t *testing.T) {
+		tree, err := intree.NewINTreeSortedBy(exampleBounds(), func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		indexes, limits, err := tree.Raw()
+		assert.NoError(t, err)
+
+		adopted, err := intree.NewINTreeFromRaw(indexes, limits)
+		assert.NoError(t, err)
+		assert.EqualValues(t, tree.Including(4.3), adopted.Including(4.3))
+	})
+	t.Run("Case_Border/invalid_arrays", func(t *testing.T) {
+		indexes, limits, err := intree.NewINTree(exampleBounds()).Raw()
+		assert.NoError(t, err)
+
+		_, err = intree.NewINTreeFromRaw(indexes, limits[:len(limits)-3])
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+
+		duplicated := append([]int32(nil), indexes...)
+		duplicated[0] = duplicated[1]
+		_, err = intree.NewINTreeFromRaw(duplicated, limits)
+		assert.True(t, errors.Is(err, intree.ErrInvalidEncoding))
+
+		stale := append([]float64(nil), limits...)
+		stale[1] = 100.0
+		_, err = intree.NewINTreeFromRaw(indexes, stale)
+		assert.True(t, errors.Is(err, intree.ErrCorruptedTree))
+	})
+	t.Run("Case_Border/nil_arrays", func(t *testing.T) {
+		tree, err := intree.NewINTreeFromRaw(nil, nil)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0, tree.Len())
+		assert.Empty(t, tree.Including(1.0))
+	})
+}
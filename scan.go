@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+// LinearScan is a brute force interval index, checking every interval on each search. Useful on datasets small
+// enough for the scan to beat a tree traversal, and as a reference to benchmark the other backends against.
+type LinearScan struct {
+	limits    [][2]float64
+	endpoints Endpoints
+}
+
+// NewLinearScan is the brute force index initialization function;
+// creates the index from the given Slice of Bounds in O(n) time. Only the endpoints option applies.
+func NewLinearScan(bounds []Bounds, opts ...Option) *LinearScan {
+	s := LinearScan{endpoints: newOptions(opts).endpoints}
+	s.Build(bounds)
+
+	return &s
+}
+
+// Build replaces the index contents with the given bounds, keeping its endpoints.
+func (s *LinearScan) Build(bounds []Bounds) {
+	s.limits = make([][2]float64, len(bounds))
+
+	for i, b := range bounds {
+		s.limits[i][0], s.limits[i][1] = b.Limits()
+	}
+}
+
+// Len returns the number of intervals stored in the index.
+func (s *LinearScan) Len() int {
+	return len(s.limits)
+}
+
+// Including scans the index and collects intervals that overlap with the given value, in ascending index order.
+func (s *LinearScan) Including(val float64) []int {
+	return s.Overlapping(val, val)
+}
+
+// Overlapping scans the index and collects intervals that overlap with the given range, boundaries included,
+// in ascending index order. Returns an empty Slice if lower is greater than upper.
+func (s *LinearScan) Overlapping(lower, upper float64) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	lower, upper = s.endpoints.searchRange(lower, upper)
+
+	for i, l := range s.limits {
+		if l[0] <= upper && l[1] >= lower {
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
+
+// Intersecting is an alias of Overlapping, for callers used to the intersection terminology.
+func (s *LinearScan) Intersecting(lo, hi float64) []int {
+	return s.Overlapping(lo, hi)
+}
@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "slices"
+
+// MaskedBy returns a new tree holding the intervals of the tree clipped to the union of the mask intervals.
+// Intervals split by gaps in the mask yield one interval per covered piece, and intervals outside the mask
+// are dropped. New intervals are indexed in ascending original index and lower limit order; the returned
+// tree is valued, holding as value the original index (an int) each interval was clipped from, and keeps
+// the tree endpoints.
+func (t *INTree) MaskedBy(mask *INTree) *INTree {
+	bounds := []ValuedBounds{}
+
+	for idx, pos := range t.positions {
+		for _, s := range mask.CoverageIn(t.limits[3*pos], t.limits[3*pos+1]) {
+			bounds = append(bounds, sourceBounds{lower: s[0], upper: s[1], source: idx})
+		}
+	}
+
+	return NewINTreeV(bounds, WithEndpoints(t.endpoints))
+}
+
+// Union returns the union of the intervals of both bounds, as disjoint intervals sorted by lower limit.
+// Overlapping or touching intervals are merged, as in Merge.
+func Union(a, b []Bounds) []Bounds {
+	return Merge(slices.Concat(a, b))
+}
+
+// Intersect returns the intersection of the intervals of both bounds, as disjoint intervals sorted by
+// lower limit. As intervals are closed, touching intervals intersect on a single point.
+func Intersect(a, b []Bounds) []Bounds {
+	ma, mb := Merge(a), Merge(b)
+	result := []Bounds{}
+
+	for i, j := 0, 0; i < len(ma) && j < len(mb); {
+		la, ua := ma[i].Limits()
+		lb, ub := mb[j].Limits()
+
+		if lower, upper := max(la, lb), min(ua, ub); lower <= upper {
+			result = append(result, Interval{Lower: lower, Upper: upper})
+		}
+
+		// Advance the interval ending first, as it cannot intersect any further one
+		if ua < ub {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return result
+}
+
+// Subtract returns the coordinates covered by the intervals of a but not by those of b, as disjoint
+// intervals sorted by lower limit. Resulting intervals are closed, so they keep the limits shared with
+// the subtracted intervals; intervals of a entirely covered by b are dropped.
+func Subtract(a, b []Bounds) []Bounds {
+	ma, mb := Merge(a), Merge(b)
+	result := []Bounds{}
+	j := 0
+
+	for _, interval := range ma {
+		lower, upper := interval.Limits()
+
+		for j < len(mb) && mb[j].(Interval).Upper < lower {
+			j++
+		}
+
+		start := lower
+		overlapped := false
+
+		for k := j; k < len(mb) && mb[k].(Interval).Lower <= upper; k++ {
+			lb, ub := mb[k].Limits()
+			if lb > start {
+				result = append(result, Interval{Lower: start, Upper: lb})
+			}
+
+			start = max(start, ub)
+			overlapped = true
+		}
+
+		if start < upper || !overlapped {
+			result = append(result, Interval{Lower: start, Upper: upper})
+		}
+	}
+
+	return result
+}
+
+// Union returns a new tree holding the union of the intervals of both trees, as computed by the Union function.
+// The new tree keeps the tree endpoints, although set operations treat intervals as closed.
+func (t *INTree) Union(other *INTree) *INTree {
+	return NewINTree(Union(t.intervalBounds(), other.intervalBounds()), WithEndpoints(t.endpoints))
+}
+
+// Intersect returns a new tree holding the intersection of the intervals of both trees, as computed by the
+// Intersect function. The new tree keeps the tree endpoints, although set operations treat intervals as closed.
+func (t *INTree) Intersect(other *INTree) *INTree {
+	return NewINTree(Intersect(t.intervalBounds(), other.intervalBounds()), WithEndpoints(t.endpoints))
+}
+
+// Subtract returns a new tree holding the intervals of the tree minus those of the other one, as computed by the
+// Subtract function. The new tree keeps the tree endpoints, although set operations treat intervals as closed.
+func (t *INTree) Subtract(other *INTree) *INTree {
+	return NewINTree(Subtract(t.intervalBounds(), other.intervalBounds()), WithEndpoints(t.endpoints))
+}
+
+// intervalBounds is an internal utility function, returning the limits of every interval not removed as Bounds,
+// in original input order.
+func (t *INTree) intervalBounds() []Bounds {
+	result := make([]Bounds, 0, len(t.positions)-t.removed)
+
+	t.liveIntervals(func(_ int, lower, upper float64) {
+		result = append(result, Interval{Lower: lower, Upper: upper})
+	})
+
+	return result
+}
+
+// sourceBounds is an internal ValuedBounds implementation, holding the original index of an interval as value.
+type sourceBounds struct {
+	lower, upper float64
+	source       int
+}
+
+// Limits accesses the interval limits.
+func (sb sourceBounds) Limits() (float64, float64) {
+	return sb.lower, sb.upper
+}
+
+// Value accesses the original index.
+func (sb sourceBounds) Value() interface{} {
+	return sb.source
+}
@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_MaskedBy(t *testing.T) {
+	tree := intree.NewINTree([]intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 10.0},
+		&testBounds{Lower: 12.0, Upper: 14.0},
+		&testBounds{Lower: 3.0, Upper: 4.0},
+	})
+
+	t.Run("Case_Clipping", func(t *testing.T) {
+		mask := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 2.0, Upper: 5.0},
+			&testBounds{Lower: 8.0, Upper: 9.0},
+			&testBounds{Lower: 4.5, Upper: 6.0},
+		})
+
+		masked := tree.MaskedBy(mask)
+
+		assert.EqualValues(t, [][2]float64{{2.0, 6.0}, {8.0, 9.0}, {3.0, 4.0}}, masked.Intervals())
+		assert.ElementsMatch(t, []interface{}{0, 2}, masked.IncludingValues(3.5))
+		assert.EqualValues(t, []interface{}{0}, masked.IncludingValues(8.5))
+		assert.EqualValues(t, 0, len(masked.Including(7.0)))
+		assert.EqualValues(t, 0, len(masked.Including(13.0)))
+	})
+	t.Run("Case_Full_mask", func(t *testing.T) {
+		mask := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: -100.0, Upper: 100.0},
+		})
+
+		masked := tree.MaskedBy(mask)
+
+		assert.EqualValues(t, tree.Intervals(), masked.Intervals())
+		assert.EqualValues(t, []interface{}{1}, masked.IncludingValues(13.0))
+	})
+	t.Run("Case_Border/empty_mask", func(t *testing.T) {
+		masked := tree.MaskedBy(intree.NewINTree(nil))
+		assert.EqualValues(t, 0, masked.Len())
+	})
+}
+
+func Test_SetOperations(t *testing.T) {
+	a := []intree.Bounds{
+		&testBounds{Lower: 0.0, Upper: 4.0},
+		&testBounds{Lower: 6.0, Upper: 8.0},
+		&testBounds{Lower: 10.0, Upper: 10.0},
+	}
+	b := []intree.Bounds{
+		&testBounds{Lower: 1.0, Upper: 2.0},
+		&testBounds{Lower: 3.0, Upper: 7.0},
+		&testBounds{Lower: 8.0, Upper: 9.0},
+	}
+
+	t.Run("Case_Union", func(t *testing.T) {
+		assert.EqualValues(t, []intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 9.0},
+			intree.Interval{Lower: 10.0, Upper: 10.0},
+		}, intree.Union(a, b))
+	})
+	t.Run("Case_Intersect", func(t *testing.T) {
+		assert.EqualValues(t, []intree.Bounds{
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 3.0, Upper: 4.0},
+			intree.Interval{Lower: 6.0, Upper: 7.0},
+			intree.Interval{Lower: 8.0, Upper: 8.0},
+		}, intree.Intersect(a, b))
+		assert.EqualValues(t, intree.Intersect(a, b), intree.Intersect(b, a))
+	})
+	t.Run("Case_Subtract", func(t *testing.T) {
+		assert.EqualValues(t, []intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 1.0},
+			intree.Interval{Lower: 2.0, Upper: 3.0},
+			intree.Interval{Lower: 7.0, Upper: 8.0},
+			intree.Interval{Lower: 10.0, Upper: 10.0},
+		}, intree.Subtract(a, b))
+		assert.EqualValues(t, []intree.Bounds{
+			intree.Interval{Lower: 4.0, Upper: 6.0},
+			intree.Interval{Lower: 8.0, Upper: 9.0},
+		}, intree.Subtract(b, a))
+	})
+	t.Run("Case_Trees", func(t *testing.T) {
+		ta, tb := intree.NewINTree(a), intree.NewINTree(b)
+
+		assert.EqualValues(t, [][2]float64{{0.0, 9.0}, {10.0, 10.0}}, ta.Union(tb).Intervals())
+		assert.EqualValues(t, [][2]float64{{1.0, 2.0}, {3.0, 4.0}, {6.0, 7.0}, {8.0, 8.0}}, ta.Intersect(tb).Intervals())
+		assert.EqualValues(t, [][2]float64{{0.0, 1.0}, {2.0, 3.0}, {7.0, 8.0}, {10.0, 10.0}}, ta.Subtract(tb).Intervals())
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.EqualValues(t, intree.Merge(a), intree.Union(a, nil))
+		assert.Empty(t, intree.Intersect(a, nil))
+		assert.EqualValues(t, intree.Merge(a), intree.Subtract(a, nil))
+		assert.Empty(t, intree.Subtract(nil, b))
+	})
+}
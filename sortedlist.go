@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortedList is a sorted endpoints list, an alternative to INTree for datasets of short intervals:
+// intervals are sorted by lower limit alongside the running maximum of their upper limits, so that searches
+// find the first candidate by binary search and scan the following ones until their lower limit exceeds the range.
+// Searches cost O(log n + k), k being the number of intervals starting between the first candidate and the range
+// upper limit: long intervals make every later one a candidate.
+type SortedList struct {
+	// lowers, uppers and indexes hold the intervals sorted by ascending lower limit;
+	// reach holds the maximum upper limit of the intervals up to each position
+	lowers, uppers, reach []float64
+	indexes               []int
+	endpoints             Endpoints
+}
+
+// NewSortedList is the sorted endpoints list initialization function;
+// creates the list from the given Slice of Bounds in O(n log n) time. Only the endpoints option applies.
+func NewSortedList(bounds []Bounds, opts ...Option) *SortedList {
+	l := SortedList{endpoints: newOptions(opts).endpoints}
+	l.Build(bounds)
+
+	return &l
+}
+
+// Build replaces the list contents with the given bounds, keeping its endpoints.
+func (l *SortedList) Build(bounds []Bounds) {
+	n := len(bounds)
+	l.lowers = make([]float64, n)
+	l.uppers = make([]float64, n)
+	l.reach = make([]float64, n)
+	l.indexes = make([]int, n)
+
+	limits := make([][2]float64, n)
+	for i, b := range bounds {
+		l.indexes[i] = i
+		limits[i][0], limits[i][1] = b.Limits()
+	}
+
+	slices.SortStableFunc(l.indexes, func(i, j int) int {
+		return cmp.Compare(limits[i][0], limits[j][0])
+	})
+
+	for pos, idx := range l.indexes {
+		l.lowers[pos], l.uppers[pos] = limits[idx][0], limits[idx][1]
+		l.reach[pos] = l.uppers[pos]
+
+		if pos > 0 {
+			l.reach[pos] = max(l.reach[pos], l.reach[pos-1])
+		}
+	}
+}
+
+// Len returns the number of intervals stored in the list.
+func (l *SortedList) Len() int {
+	return len(l.indexes)
+}
+
+// Including searches the list and collects intervals that overlap with the given value, in lower limit order.
+func (l *SortedList) Including(val float64) []int {
+	return l.Overlapping(val, val)
+}
+
+// Overlapping searches the list and collects intervals that overlap with the given range, boundaries included,
+// in lower limit order. Returns an empty Slice if lower is greater than upper.
+func (l *SortedList) Overlapping(lower, upper float64) []int {
+	result := []int{}
+
+	if lower > upper {
+		return result
+	}
+
+	lower, upper = l.endpoints.searchRange(lower, upper)
+
+	// Intervals before the first position reaching the range end before it
+	first, _ := slices.BinarySearch(l.reach, lower)
+
+	for pos := first; pos < len(l.lowers) && l.lowers[pos] <= upper; pos++ {
+		if l.uppers[pos] >= lower {
+			result = append(result, l.indexes[pos])
+		}
+	}
+
+	return result
+}
+
+// Intersecting is an alias of Overlapping, for callers used to the intersection terminology.
+func (l *SortedList) Intersecting(lo, hi float64) []int {
+	return l.Overlapping(lo, hi)
+}
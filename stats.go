@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"math"
+	"math/bits"
+	"unsafe"
+)
+
+// TreeStats holds diagnostics about the layout and contents of a tree, as returned by Stats.
+type TreeStats struct {
+	// Nodes is the number of intervals stored in the tree, excluding staged ones.
+	Nodes int
+	// Depth is the number of levels of the tree, with 0 for an empty tree.
+	Depth int
+	// Degenerate is the number of intervals whose lower limit equals their upper limit.
+	Degenerate int
+	// Inverted is the number of intervals whose lower limit is greater than their upper limit,
+	// which no search matches.
+	Inverted int
+	// Removed is the number of intervals removed by Remove and not compacted yet, which count as nodes but
+	// not as inverted intervals, nor towards the limits range.
+	Removed int
+	// Min is the lowest lower limit, NaN for an empty tree.
+	Min float64
+	// Max is the greatest upper limit, NaN for an empty tree.
+	Max float64
+	// MemoryBytes is the approximate heap footprint of the tree Slices, excluding the referenced
+	// values and retained bounds themselves.
+	MemoryBytes int
+}
+
+// Stats returns diagnostics about the tree, so that index health can be logged and pathological
+// data distributions (such as many degenerate or inverted intervals) detected. Takes O(n) time.
+func (t *INTree) Stats() TreeStats {
+	stats := TreeStats{
+		Nodes:   len(t.indexes),
+		Depth:   bits.Len(uint(len(t.indexes))),
+		Removed: t.removed,
+		Min:     math.NaN(),
+		Max:     math.NaN(),
+	}
+
+	live := 0
+
+	for pos := range t.indexes {
+		if t.isRemoved(pos) {
+			continue
+		}
+
+		lower, upper := t.limits[3*pos], t.limits[3*pos+1]
+
+		switch {
+		case lower == upper:
+			stats.Degenerate++
+		case lower > upper:
+			stats.Inverted++
+		}
+
+		if live == 0 || lower < stats.Min {
+			stats.Min = lower
+		}
+
+		if live == 0 || upper > stats.Max {
+			stats.Max = upper
+		}
+
+		live++
+	}
+
+	intSize, floatSize, ifaceSize := int(unsafe.Sizeof(0)), int(unsafe.Sizeof(0.0)), int(unsafe.Sizeof(interface{}(nil)))
+	stats.MemoryBytes = cap(t.positions)*intSize + (cap(t.values)+cap(t.bounds)+cap(t.staged))*ifaceSize +
+		cap(t.tombstones)
+
+	// Nodes of read-only trees live in the mapped file instead
+	if !t.readOnly {
+		stats.MemoryBytes += cap(t.indexes)*intSize + cap(t.limits)*floatSize
+	}
+
+	if t.layout != nil {
+		stats.MemoryBytes += cap(t.layout.positions)*intSize + cap(t.layout.limits)*floatSize
+	}
+
+	return stats
+}
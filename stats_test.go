@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Stats(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 2.0},
+			&testBounds{Lower: 5.0, Upper: 5.0},
+			&testBounds{Lower: -1.0, Upper: 3.0},
+			&testBounds{Lower: 4.0, Upper: 3.5},
+			&testBounds{Lower: 6.0, Upper: 9.0},
+		})
+
+		stats := tree.Stats()
+		assert.EqualValues(t, 5, stats.Nodes)
+		assert.EqualValues(t, 3, stats.Depth)
+		assert.EqualValues(t, 1, stats.Degenerate)
+		assert.EqualValues(t, 1, stats.Inverted)
+		assert.EqualValues(t, -1.0, stats.Min)
+		assert.EqualValues(t, 9.0, stats.Max)
+		// indexes, positions and limits, at 8 bytes per element
+		assert.EqualValues(t, (5+5+15)*8, stats.MemoryBytes)
+	})
+	t.Run("Case_Memory/valued", func(t *testing.T) {
+		plain := intree.NewINTree(exampleBounds()).Stats()
+		retained := intree.NewINTree(exampleBounds(), intree.WithRetainedBounds()).Stats()
+		assert.Greater(t, retained.MemoryBytes, plain.MemoryBytes)
+	})
+	t.Run("Case_Depth", func(t *testing.T) {
+		for n, depth := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 7: 3, 8: 4, 1000: 10} {
+			assert.EqualValues(t, depth, intree.NewINTree(randomBounds(n, 100.0, 1.0)).Stats().Depth, "n = %d", n)
+		}
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		stats := intree.NewINTree(nil).Stats()
+		assert.EqualValues(t, 0, stats.Nodes)
+		assert.EqualValues(t, 0, stats.Depth)
+		assert.True(t, math.IsNaN(stats.Min))
+		assert.True(t, math.IsNaN(stats.Max))
+	})
+}
@@ -0,0 +1,52 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "iter"
+
+// NewINTreeFrom is the streaming initialization function;
+// creates the tree from the Bounds yielded by the given sequence, in yield order, without requiring the caller
+// to materialize them into a Slice first. Only the interval limits are kept, unless bounds are retained.
+// Channels can be streamed by ranging over them within the sequence.
+func NewINTreeFrom(seq iter.Seq[Bounds], opts ...Option) *INTree {
+	o := newOptions(opts)
+	capacity := max(o.capacityHint, 0)
+	indexes, limits := make([]int, 0, capacity), make([]float64, 0, 3*capacity)
+
+	var retained []Bounds
+	if o.retainBounds {
+		retained = make([]Bounds, 0, capacity)
+	}
+
+	for b := range seq {
+		l, u := o.limits(b)
+		indexes = append(indexes, len(indexes))
+		limits = append(limits, l, u, 0)
+
+		if o.retainBounds {
+			retained = append(retained, b)
+		}
+	}
+
+	return build(indexes, limits, nil, retained, o.pivots(runtimeSource{}), o)
+}
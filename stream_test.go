@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewINTreeFrom(t *testing.T) {
+	t.Run("Case_Sequence", func(t *testing.T) {
+		bounds := randomBounds(1000, 1000.0, 2.0)
+		tree := intree.NewINTreeFrom(slices.Values(bounds))
+		reference := intree.NewINTree(bounds)
+
+		assert.EqualValues(t, reference.Intervals(), tree.Intervals())
+		for val := 0.0; val <= 1000.0; val += 7.3 {
+			assert.ElementsMatch(t, reference.Including(val), tree.Including(val), "at %.1f", val)
+		}
+	})
+	t.Run("Case_Channel", func(t *testing.T) {
+		ch := make(chan intree.Bounds)
+		go func() {
+			for _, b := range exampleBounds() {
+				ch <- b
+			}
+			close(ch)
+		}()
+
+		tree := intree.NewINTreeFrom(func(yield func(intree.Bounds) bool) {
+			for b := range ch {
+				if !yield(b) {
+					return
+				}
+			}
+		}, intree.WithRetainedBounds())
+
+		assert.EqualValues(t, len(exampleBounds()), tree.Len())
+		assert.ElementsMatch(t, intree.NewINTree(exampleBounds()).Including(4.3), tree.Including(4.3))
+		assert.EqualValues(t, len(tree.Including(4.3)), len(tree.IncludingBounds(4.3)))
+	})
+	t.Run("Case_Border/empty_sequence", func(t *testing.T) {
+		tree := intree.NewINTreeFrom(slices.Values([]intree.Bounds(nil)), intree.WithRetainedBounds())
+		assert.EqualValues(t, 0, tree.Len())
+		assert.Empty(t, tree.Including(1.0))
+		assert.Empty(t, tree.IncludingBounds(1.0))
+	})
+}
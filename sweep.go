@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "math"
+
+// Sweeper answers searches for ascending values, such as scanned timestamps, by sweeping them along the nodes:
+// it keeps the set of intervals including the last value and only updates it with the intervals starting or
+// ending since then. Each search takes amortized O(c log n) time for c intervals entering or leaving the set,
+// plus the number of matches. The tree must not be updated while the sweeper is in use.
+type Sweeper struct {
+	tree   *INTree
+	active upperHeap
+	next   int
+	last   float64
+}
+
+// NewSweeper creates a sweeper over the given tree, starting before any value.
+func NewSweeper(t *INTree) *Sweeper {
+	t = t.lowerOrdered()
+
+	s := &Sweeper{tree: t, active: upperHeap{limits: t.limits}}
+	s.Reset()
+
+	return s
+}
+
+// Reset restarts the sweep, so that the next search may take any value.
+func (s *Sweeper) Reset() {
+	s.active.positions = s.active.positions[:0]
+	s.next = 0
+	s.last = math.Inf(-1)
+}
+
+// Including collects the intervals that contain the given value, in no particular order.
+// A value lower than the previous one restarts the sweep, as does NaN, which matches nothing.
+func (s *Sweeper) Including(val float64) []int {
+	result := []int{}
+
+	s.IncludingFunc(val, func(idx int) bool {
+		result = append(result, idx)
+		return true
+	})
+
+	return result
+}
+
+// IncludingFunc is the allocation free counterpart of Including;
+// calls fn with the index of every interval that contains the given value, and stops as soon as fn returns false.
+func (s *Sweeper) IncludingFunc(val float64, fn func(idx int) bool) {
+	s.advance(val)
+
+	for _, pos := range s.active.positions {
+		if !fn(s.tree.indexes[pos]) {
+			return
+		}
+	}
+}
+
+// CountIncluding returns the number of intervals that contain the given value, without collecting them.
+func (s *Sweeper) CountIncluding(val float64) int {
+	s.advance(val)
+
+	return len(s.active.positions)
+}
+
+// advance is an internal utility function, updating the active intervals to the ones including the given value.
+func (s *Sweeper) advance(val float64) {
+	// NaN values match nothing, leaving an empty set to restart from
+	if val < s.last || math.IsNaN(val) {
+		s.Reset()
+	}
+
+	if math.IsNaN(val) {
+		return
+	}
+
+	s.last = val
+
+	t := s.tree
+	lower, upper := t.searchRange(val, val)
+
+	for s.next < len(t.indexes) && t.limits[3*s.next] <= upper {
+		s.active.push(s.next)
+		s.next++
+	}
+
+	// As values are ascending, intervals ending before the current one never match again
+	for len(s.active.positions) > 0 && t.limits[3*s.active.positions[0]+1] < lower {
+		s.active.pop()
+	}
+}
+
+// upperHeap is an internal min-heap of node positions, keeping the node with the lowest upper limit on top.
+// Unlike container/heap, positions are not boxed on push.
+type upperHeap struct {
+	limits    []float64
+	positions []int
+}
+
+// push adds the given node position to the heap.
+func (h *upperHeap) push(pos int) {
+	h.positions = append(h.positions, pos)
+
+	for i := len(h.positions) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+
+		h.positions[i], h.positions[parent] = h.positions[parent], h.positions[i]
+		i = parent
+	}
+}
+
+// pop removes the node position on top of the heap.
+func (h *upperHeap) pop() {
+	n := len(h.positions) - 1
+	h.positions[0] = h.positions[n]
+	h.positions = h.positions[:n]
+
+	for i := 0; ; {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+
+		if child+1 < n && h.less(child+1, child) {
+			child++
+		}
+
+		if !h.less(child, i) {
+			return
+		}
+
+		h.positions[i], h.positions[child] = h.positions[child], h.positions[i]
+		i = child
+	}
+}
+
+// less reports whether the node at position i of the heap has a lower upper limit than the one at position j.
+func (h *upperHeap) less(i, j int) bool {
+	return h.limits[3*h.positions[i]+1] < h.limits[3*h.positions[j]+1]
+}
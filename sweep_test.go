@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Sweeper(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		s := intree.NewSweeper(tree)
+
+		for _, val := range []float64{0.5, 1.0, 3.0, 4.3, 4.3, 6.0, 7.9, 9.0, 9.5} {
+			assert.ElementsMatch(t, tree.Including(val), s.Including(val), "at %.1f", val)
+			assert.EqualValues(t, tree.CountIncluding(val), s.CountIncluding(val))
+		}
+	})
+	t.Run("Case_Randomized", func(t *testing.T) {
+		for _, e := range []intree.Endpoints{intree.Closed, intree.Open} {
+			tree := intree.NewINTree(randomBounds(2000, 100.0, 5.0), intree.WithEndpoints(e))
+			s := intree.NewSweeper(tree)
+
+			for val := -1.0; val <= 106.0; val += 0.1 {
+				assert.ElementsMatch(t, tree.Including(val), s.Including(val), "%s at %.1f", e, val)
+			}
+		}
+	})
+	t.Run("Case_Restart", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		s := intree.NewSweeper(tree)
+
+		assert.ElementsMatch(t, tree.Including(9.0), s.Including(9.0))
+		// Going backwards restarts the sweep
+		assert.ElementsMatch(t, tree.Including(4.3), s.Including(4.3))
+
+		s.Reset()
+		assert.ElementsMatch(t, tree.Including(1.0), s.Including(1.0))
+	})
+	t.Run("Case_Early_exit", func(t *testing.T) {
+		s := intree.NewSweeper(intree.NewINTree(exampleBounds()))
+
+		calls := 0
+		s.IncludingFunc(4.3, func(idx int) bool {
+			calls++
+			return false
+		})
+
+		assert.EqualValues(t, 1, calls)
+	})
+	t.Run("Case_Border/NaN", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		s := intree.NewSweeper(tree)
+
+		assert.NotEmpty(t, s.Including(4.3))
+		assert.Empty(t, s.Including(math.NaN()))
+		assert.ElementsMatch(t, tree.Including(4.3), s.Including(4.3))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		s := intree.NewSweeper(intree.NewINTree(nil))
+		assert.Empty(t, s.Including(4.3))
+	})
+}
+
+func Benchmark_Sweeper(b *testing.B) {
+	tree := intree.NewINTree(randomBounds(100000, 1000.0, 10.0))
+	s := intree.NewSweeper(tree)
+	count := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		s.IncludingFunc(float64(i%100000)/100.0, func(idx int) bool {
+			count++
+			return true
+		})
+	}
+}
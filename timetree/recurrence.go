@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package timetree
+
+import (
+	"slices"
+	"time"
+)
+
+// day is the length of a calendar day without daylight saving time transitions.
+const day = 24 * time.Hour
+
+// Recurrence is a window repeating on given days at the same wall clock times of a location, such as
+// weekdays from 09:00 to 17:00 in Europe/Berlin. Start and End are offsets from midnight in the wall clock
+// of the location; an End not after Start makes the window end the next day, as overnight windows do.
+type Recurrence struct {
+	// Weekdays holds the days the window starts on; every day if empty
+	Weekdays []time.Weekday
+	Start    time.Duration
+	End      time.Duration
+	// Location is the time zone of the wall clock times; UTC if nil
+	Location *time.Location
+}
+
+// Weekdays is the Monday to Friday set of days, as used in Recurrence.
+var Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// Expand returns the concrete occurrences of the recurrence overlapping with the given time range, in
+// chronological order. Wall clock times are resolved on each day, so that occurrences keep their local times
+// across daylight saving time transitions, while their length changes if one happens within them; times
+// skipped by a transition resolve past it, as time.Date does.
+func (r Recurrence) Expand(from, to time.Time) []Interval {
+	result := []Interval{}
+
+	if to.Before(from) {
+		return result
+	}
+
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	length := r.End - r.Start
+	if length <= 0 {
+		length += day
+	}
+
+	// Start a day early to catch occurrences running past midnight into the range
+	first, last := from.In(loc), to.In(loc)
+	y, m, d := first.Date()
+
+	for date := time.Date(y, m, d-1, 0, 0, 0, 0, loc); !date.After(last); date = nextDay(date) {
+		if len(r.Weekdays) > 0 && !slices.Contains(r.Weekdays, date.Weekday()) {
+			continue
+		}
+
+		occurrence := Interval{Start: wallClock(date, r.Start), End: wallClock(date, r.Start+length)}
+
+		if !occurrence.End.Before(from) && !occurrence.Start.After(to) {
+			result = append(result, occurrence)
+		}
+	}
+
+	return result
+}
+
+// RecurrenceTree is the recurring window counterpart of TimeTree; indexes the occurrences of the given
+// recurrences over a horizon, returning indices to the initial recurrences array.
+type RecurrenceTree struct {
+	tree *TimeTree
+	// sources maps the occurrence indices to the recurrence ones
+	sources []int
+	n       int
+}
+
+// NewRecurrenceTree is the recurring window initialization function;
+// creates the tree from the occurrences of the given recurrences overlapping with the horizon from the given
+// instant to the given one. Instants outside of the horizon match no occurrence.
+func NewRecurrenceTree(recurrences []Recurrence, from, to time.Time) *RecurrenceTree {
+	t := RecurrenceTree{n: len(recurrences)}
+	occurrences := []TimeBounds{}
+
+	for i, r := range recurrences {
+		for _, o := range r.Expand(from, to) {
+			occurrences = append(occurrences, o)
+			t.sources = append(t.sources, i)
+		}
+	}
+
+	t.tree = NewTimeTree(occurrences)
+
+	return &t
+}
+
+// Len returns the number of recurrences stored in the tree.
+func (t *RecurrenceTree) Len() int {
+	return t.n
+}
+
+// Occurrences returns the number of occurrences indexed over the tree horizon.
+func (t *RecurrenceTree) Occurrences() int {
+	return t.tree.Len()
+}
+
+// Including traverses the tree and collects the recurrences with an occurrence including the given instant,
+// limits included, in ascending index order.
+func (t *RecurrenceTree) Including(at time.Time) []int {
+	result := []int{}
+
+	t.tree.IncludingFunc(at, func(idx int) bool {
+		result = append(result, t.sources[idx])
+		return true
+	})
+
+	slices.Sort(result)
+
+	return slices.Compact(result)
+}
+
+// nextDay is an internal utility function, returning the midnight following the given one in its location.
+func nextDay(midnight time.Time) time.Time {
+	y, m, d := midnight.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, midnight.Location())
+}
+
+// wallClock is an internal utility function, resolving the given offset from the given midnight as wall clock
+// time in its location, rather than as elapsed time.
+func wallClock(midnight time.Time, offset time.Duration) time.Time {
+	y, m, d := midnight.Date()
+	h, rest := offset/time.Hour, offset%time.Hour
+
+	return time.Date(y, m, d, int(h), 0, 0, int(rest), midnight.Location())
+}
@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package timetree_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/lggomez/intree/timetree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Recurrence_Expand(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	t.Run("Case_Weekdays", func(t *testing.T) {
+		r := timetree.Recurrence{Weekdays: timetree.Weekdays, Start: 9 * time.Hour, End: 17 * time.Hour, Location: berlin}
+
+		// Monday 2024-03-04 to Sunday 2024-03-10
+		occurrences := r.Expand(time.Date(2024, 3, 4, 0, 0, 0, 0, berlin), time.Date(2024, 3, 10, 23, 0, 0, 0, berlin))
+		assert.Len(t, occurrences, 5)
+
+		for i, o := range occurrences {
+			assert.EqualValues(t, time.Date(2024, 3, 4+i, 9, 0, 0, 0, berlin), o.Start)
+			assert.EqualValues(t, 8*time.Hour, o.End.Sub(o.Start))
+		}
+	})
+	t.Run("Case_DST", func(t *testing.T) {
+		// Clocks go forward at 02:00 on 2024-03-31, and back at 03:00 on 2024-10-27
+		night := timetree.Recurrence{Start: 22 * time.Hour, End: 6 * time.Hour, Location: berlin}
+
+		spring := night.Expand(time.Date(2024, 3, 31, 0, 0, 0, 0, berlin), time.Date(2024, 3, 31, 12, 0, 0, 0, berlin))
+		assert.Len(t, spring, 1)
+		assert.EqualValues(t, 7*time.Hour, spring[0].End.Sub(spring[0].Start))
+		assert.EqualValues(t, time.Date(2024, 3, 31, 6, 0, 0, 0, berlin), spring[0].End)
+
+		autumn := night.Expand(time.Date(2024, 10, 27, 0, 0, 0, 0, berlin), time.Date(2024, 10, 27, 12, 0, 0, 0, berlin))
+		assert.Len(t, autumn, 1)
+		assert.EqualValues(t, 9*time.Hour, autumn[0].End.Sub(autumn[0].Start))
+
+		// Wall clock times skipped by the transition resolve past it
+		skipped := timetree.Recurrence{Start: 2*time.Hour + 30*time.Minute, End: 4 * time.Hour, Location: berlin}
+		occurrences := skipped.Expand(time.Date(2024, 3, 31, 0, 0, 0, 0, berlin), time.Date(2024, 3, 31, 12, 0, 0, 0, berlin))
+		assert.Len(t, occurrences, 1)
+		assert.EqualValues(t, time.Date(2024, 3, 31, 3, 30, 0, 0, berlin), occurrences[0].Start)
+	})
+	t.Run("Case_Border/reversed_range", func(t *testing.T) {
+		r := timetree.Recurrence{Start: 9 * time.Hour, End: 17 * time.Hour}
+		assert.Empty(t, r.Expand(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)))
+	})
+}
+
+func Test_RecurrenceTree(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	tree := timetree.NewRecurrenceTree([]timetree.Recurrence{
+		{Weekdays: timetree.Weekdays, Start: 9 * time.Hour, End: 17 * time.Hour, Location: berlin},
+		{Start: 22 * time.Hour, End: 6 * time.Hour, Location: berlin},
+		{Weekdays: []time.Weekday{time.Saturday}, Start: 0, End: 0, Location: time.UTC},
+	}, time.Date(2024, 3, 1, 0, 0, 0, 0, berlin), time.Date(2024, 4, 30, 0, 0, 0, 0, berlin))
+
+	assert.EqualValues(t, 3, tree.Len())
+	assert.Greater(t, tree.Occurrences(), 3)
+
+	// Tuesday at noon in Berlin, queried from another location
+	assert.EqualValues(t, []int{0}, tree.Including(time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)))
+	// Local office hours are kept after the DST transition: 08:30 Berlin is 06:30 UTC in April
+	assert.Empty(t, tree.Including(time.Date(2024, 4, 2, 6, 30, 0, 0, time.UTC)))
+	assert.EqualValues(t, []int{0}, tree.Including(time.Date(2024, 4, 2, 7, 30, 0, 0, time.UTC)))
+	// Overnight windows and whole days
+	assert.EqualValues(t, []int{1, 2}, tree.Including(time.Date(2024, 3, 9, 23, 0, 0, 0, time.UTC)))
+	assert.EqualValues(t, []int{2}, tree.Including(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)))
+	// Outside of the horizon
+	assert.Empty(t, tree.Including(time.Date(2024, 6, 4, 10, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,123 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package timetree provides a time interval layer over intree, for searches over time.Time instants.
+// Instants are kept as int64 Unix nanoseconds and compared exactly, avoiding the precision loss
+// of storing current timestamps (above 2^53 nanoseconds) as float64.
+package timetree
+
+import (
+	"math"
+	"time"
+
+	"github.com/lggomez/intree"
+)
+
+var (
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
+// TimeBounds is the main interface expected by NewTimeTree(); requires Limits method to access interval limits.
+type TimeBounds interface {
+	Limits() (start, end time.Time)
+}
+
+// Interval is a plain TimeBounds implementation, including both its start and end instants.
+type Interval struct {
+	Start, End time.Time
+}
+
+// Span returns the interval starting at the given instant and lasting for the given duration.
+func Span(start time.Time, d time.Duration) Interval {
+	return Interval{Start: start, End: start.Add(d)}
+}
+
+// Limits accesses the interval limits.
+func (i Interval) Limits() (time.Time, time.Time) {
+	return i.Start, i.End
+}
+
+// TimeTree is the time interval counterpart of intree.INTree;
+// returns indices to the initial bounds array, limits included.
+type TimeTree struct {
+	tree *intree.INTreeOf[int64]
+}
+
+// NewTimeTree is the main initialization function;
+// creates the tree from the given Slice of TimeBounds. Instants beyond the range of Unix nanoseconds
+// representable as int64 (years 1678 to 2262) saturate to its limits.
+func NewTimeTree(bounds []TimeBounds) *TimeTree {
+	nanoBounds := make([]intree.BoundsOf[int64], len(bounds))
+
+	for i, b := range bounds {
+		start, end := b.Limits()
+		nanoBounds[i] = nanoInterval{lower: nanos(start), upper: nanos(end)}
+	}
+
+	return &TimeTree{tree: intree.NewINTreeOf(nanoBounds)}
+}
+
+// Len returns the number of intervals stored in the tree.
+func (t *TimeTree) Len() int {
+	return t.tree.Len()
+}
+
+// Including traverses the tree and collects intervals that include the given instant.
+func (t *TimeTree) Including(at time.Time) []int {
+	return t.tree.Including(nanos(at))
+}
+
+// IncludingFunc is the allocation free counterpart of Including;
+// calls fn with the index of every interval that includes the given instant,
+// and stops the traversal as soon as fn returns false.
+func (t *TimeTree) IncludingFunc(at time.Time, fn func(idx int) bool) {
+	t.tree.IncludingFunc(nanos(at), fn)
+}
+
+// Overlapping traverses the tree and collects intervals that overlap with the given time range, limits included.
+// Returns an empty Slice if start is after end.
+func (t *TimeTree) Overlapping(start, end time.Time) []int {
+	return t.tree.Overlapping(nanos(start), nanos(end))
+}
+
+// nanos is an internal utility function, converting an instant to Unix nanoseconds, saturating out of range ones.
+func nanos(t time.Time) int64 {
+	switch {
+	case t.Before(minTime):
+		return math.MinInt64
+	case t.After(maxTime):
+		return math.MaxInt64
+	}
+
+	return t.UnixNano()
+}
+
+// nanoInterval is an internal intree.BoundsOf implementation, holding interval limits as Unix nanoseconds.
+type nanoInterval struct {
+	lower, upper int64
+}
+
+// Limits accesses the interval limits.
+func (ni nanoInterval) Limits() (int64, int64) {
+	return ni.lower, ni.upper
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package timetree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lggomez/intree/timetree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TimeTree(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := timetree.NewTimeTree([]timetree.TimeBounds{
+			timetree.Span(base, time.Hour),
+			timetree.Span(base.Add(30*time.Minute), time.Hour),
+			timetree.Span(base.Add(2*time.Hour), 15*time.Minute),
+		})
+
+		assert.EqualValues(t, 3, tree.Len())
+		assert.ElementsMatch(t, []int{0}, tree.Including(base.Add(10*time.Minute)))
+		assert.ElementsMatch(t, []int{0, 1}, tree.Including(base.Add(45*time.Minute)))
+		assert.ElementsMatch(t, []int{1}, tree.Including(base.Add(90*time.Minute)))
+		assert.Empty(t, tree.Including(base.Add(100*time.Minute)))
+		assert.ElementsMatch(t, []int{1, 2}, tree.Overlapping(base.Add(80*time.Minute), base.Add(2*time.Hour)))
+
+		calls := 0
+		tree.IncludingFunc(base.Add(45*time.Minute), func(idx int) bool {
+			calls++
+			return true
+		})
+		assert.EqualValues(t, 2, calls)
+	})
+	t.Run("Case_Border/nanosecond_precision", func(t *testing.T) {
+		// Current Unix nanoseconds exceed 2^53, so these limits would collapse as float64
+		tree := timetree.NewTimeTree([]timetree.TimeBounds{
+			timetree.Interval{Start: base, End: base.Add(time.Nanosecond)},
+			timetree.Interval{Start: base.Add(2 * time.Nanosecond), End: base.Add(3 * time.Nanosecond)},
+		})
+
+		assert.ElementsMatch(t, []int{0}, tree.Including(base.Add(time.Nanosecond)))
+		assert.ElementsMatch(t, []int{1}, tree.Including(base.Add(2*time.Nanosecond)))
+		assert.Empty(t, tree.Including(base.Add(-time.Nanosecond)))
+	})
+	t.Run("Case_Border/time_zones", func(t *testing.T) {
+		tree := timetree.NewTimeTree([]timetree.TimeBounds{timetree.Span(base, time.Hour)})
+
+		// Instants are compared regardless of their location
+		local := base.In(time.FixedZone("UTC-3", -3*60*60))
+		assert.ElementsMatch(t, []int{0}, tree.Including(local))
+	})
+	t.Run("Case_Border/out_of_range", func(t *testing.T) {
+		far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+		tree := timetree.NewTimeTree([]timetree.TimeBounds{
+			timetree.Interval{Start: base, End: far},
+			timetree.Interval{Start: time.Time{}, End: base},
+		})
+
+		assert.ElementsMatch(t, []int{0}, tree.Including(time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC)))
+		assert.ElementsMatch(t, []int{1}, tree.Including(time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		assert.Empty(t, timetree.NewTimeTree(nil).Including(base))
+	})
+}
+
+func Benchmark_NewTimeTree(b *testing.B) {
+	// Duplicate timestamps, as in events batched at the same instant
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	bounds := make([]timetree.TimeBounds, 100000)
+
+	for i := range bounds {
+		bounds[i] = timetree.Span(base.Add(time.Duration(i%10)*time.Hour), time.Duration(i)*time.Second)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		timetree.NewTimeTree(bounds)
+	}
+}
@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "math"
+
+// Remove marks the interval at the given original index as removed (a tombstone) without shifting any node:
+// its upper limit is set to -Inf so that no search matches it, and the augmented limits along its path are
+// repaired in O(log n) time, or O(n) on trees with an Eytzinger layout, which is rebuilt. The index stays
+// taken and counts towards Len until Compact drops it; Intervals, set operations and MarshalJSON skip it,
+// while MarshalBinary keeps it removed through UnmarshalBinary and OpenMMap. Returns ErrIndexOutOfRange if
+// the index is not stored in the tree or was already removed. Updates must not run concurrently with queries.
+func (t *INTree) Remove(index int) error {
+	pos := t.position(index)
+	if pos < 0 || t.tombstones != nil && t.tombstones[index] {
+		return ErrIndexOutOfRange
+	}
+
+	t.ensureWritable()
+
+	if t.tombstones == nil {
+		t.tombstones = make([]bool, len(t.indexes))
+	}
+
+	t.tombstones[index] = true
+	t.removed++
+	t.limits[3*pos+1] = math.Inf(-1)
+	t.repairPath(pos, t.repairLimit)
+
+	if t.view != nil {
+		view := t.lowerOrdered()
+		view.limits[3*view.positions[index]+1] = math.Inf(-1)
+		view.repairPath(view.positions[index], view.repairLimit)
+	}
+
+	if t.layout != nil {
+		t.layout = newEytzinger(t.limits[:3*t.treeLen()])
+	}
+
+	return nil
+}
+
+// Removed returns the number of intervals removed by Remove and not compacted yet.
+func (t *INTree) Removed() int {
+	return t.removed
+}
+
+// Compact drops the intervals removed by Remove in a single O(n) pass, once they make up at least the fraction
+// of the tree set by WithCompactionThreshold (any removed interval, by default), so that bursts of removals
+// do not require a rebuild each. The following indices shift down to stay contiguous.
+// Returns whether the tree was compacted. Updates must not run concurrently with queries.
+func (t *INTree) Compact() bool {
+	if t.removed == 0 || float64(t.removed) < t.compactionThreshold*float64(len(t.indexes)) {
+		return false
+	}
+
+	t.ensureWritable()
+	t.flush()
+
+	flagged := make([]bool, len(t.indexes))
+	for pos, idx := range t.indexes {
+		flagged[pos] = t.tombstones[idx]
+	}
+
+	// Detach the flags first, as compactIndexes would otherwise compact them while reading them
+	removed := t.tombstones
+	t.tombstones, t.removed = nil, 0
+
+	t.removeNodes(flagged)
+	t.compactIndexes(removed)
+	t.reaugment()
+
+	return true
+}
+
+// isRemoved is an internal utility function, reporting whether the node at the given position was removed.
+func (t *INTree) isRemoved(pos int) bool {
+	return t.removed > 0 && t.tombstones[t.indexes[pos]]
+}
+
+// liveIntervals is an internal utility function, calling fn with the original index and limits of every interval
+// not removed, in ascending original index order.
+func (t *INTree) liveIntervals(fn func(idx int, lower, upper float64)) {
+	for idx, pos := range t.positions {
+		if !t.isRemoved(pos) {
+			fn(idx, t.limits[3*pos], t.limits[3*pos+1])
+		}
+	}
+}
+
+// repairPath is an internal utility function, calling repair bottom-up on the nodes of the path from the root
+// to the node at the given position, with the bounds and center position of their subtrees, so that the
+// augmented data of each node is recomputed from its children after the node changed.
+func (t *INTree) repairPath(pos int, repair func(lBoundIdx, centerIdx, rBoundIdx int)) {
+	// Nodes buffered by Insert hold no augmented data
+	if pos >= t.treeLen() {
+		return
+	}
+
+	// Every level of the path holds the bounds of its subtree
+	var path [stockSize / 2][2]int
+	depth := 0
+
+	for l, r := 0, t.treeLen()-1; l <= r; depth++ {
+		path[depth] = [2]int{l, r}
+
+		centerIdx := (l + r + 1) >> 1
+		if pos == centerIdx {
+			depth++
+			break
+		}
+
+		if pos < centerIdx {
+			r = centerIdx - 1
+		} else {
+			l = centerIdx + 1
+		}
+	}
+
+	for depth--; depth >= 0; depth-- {
+		l, r := path[depth][0], path[depth][1]
+		repair(l, (l+r+1)>>1, r)
+	}
+}
+
+// repairLimit is an internal utility function, recomputing the augmented limit of the node at the given
+// center position from its children, as called by repairPath after its upper limit changed.
+func (t *INTree) repairLimit(lBoundIdx, centerIdx, rBoundIdx int) {
+	max := t.limits[3*centerIdx+1]
+
+	if lBoundIdx < centerIdx {
+		max = maxLimit(max, t.limits[3*((lBoundIdx+centerIdx)>>1)+2])
+	}
+
+	if centerIdx < rBoundIdx {
+		max = maxLimit(max, t.limits[3*((centerIdx+rBoundIdx+2)>>1)+2])
+	}
+
+	t.limits[3*centerIdx+2] = max
+}
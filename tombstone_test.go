@@ -0,0 +1,234 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Remove(t *testing.T) {
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		assert.NoError(t, tree.Remove(0))
+		assert.NoError(t, tree.Remove(8))
+		assert.NoError(t, tree.CheckInvariants())
+
+		assert.EqualValues(t, 13, tree.Len())
+		assert.EqualValues(t, 2, tree.Removed())
+		assert.ElementsMatch(t, []int{2, 5, 10}, tree.Including(4.3))
+		assert.ElementsMatch(t, []int{1, 2, 5, 9, 10}, tree.Overlapping(4.5, 5.0))
+		assert.EqualValues(t, 3, tree.CountIncluding(4.3))
+		assert.ErrorIs(t, tree.Remove(0), intree.ErrIndexOutOfRange)
+		assert.ErrorIs(t, tree.Remove(13), intree.ErrIndexOutOfRange)
+
+		stats := tree.Stats()
+		assert.EqualValues(t, 13, stats.Nodes)
+		assert.EqualValues(t, 2, stats.Removed)
+		assert.EqualValues(t, 0, stats.Inverted)
+	})
+	t.Run("Case_Random/rebuilt", func(t *testing.T) {
+		bounds := randomBounds(500, 100.0, 10.0)
+
+		for _, opts := range [][]intree.Option{nil, {intree.WithEytzingerLayout()}, {intree.WithEndpoints(intree.Open)}} {
+			tree := intree.NewINTree(bounds, opts...)
+			kept, live := []intree.Bounds{}, []int{}
+
+			for i, b := range bounds {
+				if i%3 == 0 {
+					assert.NoError(t, tree.Remove(i))
+					continue
+				}
+
+				kept = append(kept, b)
+				live = append(live, i)
+			}
+
+			assert.NoError(t, tree.CheckInvariants())
+
+			expected := intree.NewINTree(kept, opts...)
+			for val := -1.0; val <= 111.0; val += 0.5 {
+				matches := expected.IncludingSorted(val)
+				for i, m := range matches {
+					matches[i] = live[m]
+				}
+
+				assert.EqualValues(t, matches, tree.IncludingSorted(val), "value %v", val)
+			}
+
+			assert.EqualValues(t, expected.CoveredLength(0, 100), tree.CoveredLength(0, 100))
+			assert.EqualValues(t, expected.Gaps(0, 100), tree.Gaps(0, 100))
+
+			depth, at := expected.MaxDepth(0, 100)
+			removedDepth, removedAt := tree.MaxDepth(0, 100)
+			assert.EqualValues(t, depth, removedDepth)
+			assert.EqualValues(t, at, removedAt)
+		}
+	})
+	t.Run("Case_Nearest", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 0.0, Upper: 1.0},
+			&testBounds{Lower: 5.0, Upper: 6.0},
+			&testBounds{Lower: 9.0, Upper: 10.0},
+		})
+		assert.NoError(t, tree.Remove(1))
+
+		idx, ok := tree.Nearest(5.5)
+		assert.True(t, ok)
+		assert.EqualValues(t, 2, idx)
+		assert.EqualValues(t, []int{0, 2}, tree.KNearest(4.0, 3))
+
+		assert.NoError(t, tree.Remove(0))
+		assert.NoError(t, tree.Remove(2))
+
+		_, ok = tree.Nearest(5.5)
+		assert.False(t, ok)
+		assert.Empty(t, tree.KNearest(4.0, 3))
+	})
+	t.Run("Case_Updates", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.NoError(t, tree.Remove(2))
+
+		assert.EqualValues(t, 13, tree.Insert(&testBounds{Lower: 4.2, Upper: 4.4}))
+		assert.ElementsMatch(t, []int{0, 5, 8, 10, 13}, tree.Including(4.3))
+
+		// Deleting leaves a tombstone as well, including on buffered intervals
+		assert.NoError(t, tree.Delete(0))
+		assert.NoError(t, tree.Delete(13))
+		assert.ElementsMatch(t, []int{5, 8, 10}, tree.Including(4.3))
+		assert.ErrorIs(t, tree.Remove(0), intree.ErrIndexOutOfRange)
+		assert.ErrorIs(t, tree.Delete(2), intree.ErrIndexOutOfRange)
+		assert.EqualValues(t, 3, tree.Removed())
+		assert.NoError(t, tree.CheckInvariants())
+
+		assert.True(t, tree.Compact())
+		assert.EqualValues(t, 0, tree.Removed())
+		assert.ElementsMatch(t, []int{3, 6, 8}, tree.Including(4.3))
+		assert.NoError(t, tree.CheckInvariants())
+	})
+	t.Run("Case_Roundtrip", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(200, 100.0, 5.0))
+		for _, idx := range []int{0, 63, 64, 150, 199} {
+			assert.NoError(t, tree.Remove(idx))
+		}
+
+		data, err := tree.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded := &intree.INTree{}
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.NoError(t, decoded.CheckInvariants())
+
+		assert.EqualValues(t, 5, decoded.Removed())
+		assert.EqualValues(t, tree.Intervals(), decoded.Intervals())
+		assert.EqualValues(t, tree.KNearest(50.0, 200), decoded.KNearest(50.0, 200))
+		assert.ErrorIs(t, decoded.Remove(63), intree.ErrIndexOutOfRange)
+		assert.NoError(t, decoded.Remove(1))
+
+		_, err = decoded.MarshalJSON()
+		assert.NoError(t, err)
+
+		assert.True(t, decoded.Compact())
+		assert.EqualValues(t, 194, decoded.Len())
+		assert.NoError(t, decoded.CheckInvariants())
+	})
+	t.Run("Case_Clone", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+		assert.NoError(t, tree.Remove(0))
+
+		clone := tree.Clone()
+		assert.NoError(t, tree.Remove(2))
+		assert.ElementsMatch(t, []int{2, 5, 8, 10}, clone.Including(4.3))
+		assert.EqualValues(t, 1, clone.Removed())
+	})
+	t.Run("Case_Exports", func(t *testing.T) {
+		bounds := []intree.Bounds{
+			intree.Interval{Lower: 0.0, Upper: 5.0},
+			intree.Interval{Lower: 1.0, Upper: 2.0},
+			intree.Interval{Lower: 3.0, Upper: 4.0},
+		}
+		unordered, err := intree.NewINTreeSortedBy(bounds, func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+
+		for _, tree := range []*intree.INTree{intree.NewINTree(bounds), unordered} {
+			assert.NoError(t, tree.Remove(0))
+			live := intree.NewINTree(bounds[1:])
+			other := intree.NewINTree([]intree.Bounds{intree.Interval{Lower: 1.5, Upper: 3.5}})
+
+			assert.InDelta(t, live.CoverageEntropy(), tree.CoverageEntropy(), 1e-12)
+			assert.EqualValues(t, []float64{3.0, 2.0, 0.0}, tree.CoverageByTier([]float64{0, 1, 2}))
+			assert.EqualValues(t, [][2]float64{{1.0, 2.0}}, tree.CoverageLostIfRemoved(1))
+			assert.Nil(t, tree.CoverageLostIfRemoved(0))
+			assert.EqualValues(t, [][2]float64{{1.0, 2.0}, {3.0, 4.0}}, tree.Intervals())
+			assert.EqualValues(t, live.Union(other).Intervals(), tree.Union(other).Intervals())
+			assert.EqualValues(t, live.Intersect(other).Intervals(), tree.Intersect(other).Intervals())
+			assert.EqualValues(t, live.Subtract(other).Intervals(), tree.Subtract(other).Intervals())
+
+			data, err := tree.MarshalJSON()
+			assert.NoError(t, err)
+
+			decoded := &intree.INTree{}
+			assert.NoError(t, decoded.UnmarshalJSON(data))
+			assert.EqualValues(t, [][2]float64{{1.0, 2.0}, {3.0, 4.0}}, decoded.Intervals())
+			assert.ElementsMatch(t, []int{1}, decoded.Including(3.5))
+			assert.NoError(t, decoded.Validate())
+		}
+	})
+}
+
+func Test_Tree_Compact(t *testing.T) {
+	t.Run("Case_Default", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithRetainedBounds())
+		assert.False(t, tree.Compact())
+
+		assert.NoError(t, tree.Remove(0))
+		assert.NoError(t, tree.Remove(8))
+		assert.True(t, tree.Compact())
+		assert.NoError(t, tree.CheckInvariants())
+
+		assert.EqualValues(t, 11, tree.Len())
+		assert.EqualValues(t, 0, tree.Removed())
+		assert.ElementsMatch(t, []int{1, 4, 8}, tree.Including(4.3))
+		assert.ElementsMatch(t, []intree.Bounds{
+			&testBounds{Lower: 4.0, Upper: 8.0},
+			&testBounds{Lower: 3.0, Upper: 6.0},
+			&testBounds{Lower: 4.1, Upper: 4.9},
+		}, tree.IncludingBounds(4.3))
+	})
+	t.Run("Case_Threshold", func(t *testing.T) {
+		tree := intree.NewINTree(randomBounds(10, 100.0, 10.0), intree.WithCompactionThreshold(0.3))
+
+		assert.NoError(t, tree.Remove(0))
+		assert.NoError(t, tree.Remove(1))
+		assert.False(t, tree.Compact())
+		assert.EqualValues(t, 10, tree.Len())
+
+		assert.NoError(t, tree.Remove(2))
+		assert.True(t, tree.Compact())
+		assert.EqualValues(t, 7, tree.Len())
+		assert.NoError(t, tree.CheckInvariants())
+	})
+}
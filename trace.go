@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import "time"
+
+// QueryEvent describes a tree traversal, as reported to the hook set by WithTraceHook.
+type QueryEvent struct {
+	// Lower and Upper are the searched range, after applying the tree endpoints
+	Lower, Upper float64
+	// Visited is the number of nodes whose limits were compared against the range
+	Visited int
+	// PrunedByMax is the number of subtrees skipped as no upper limit in them reaches the range
+	PrunedByMax int
+	// PrunedByLower is the number of subtrees skipped as all their lower limits lie past the range
+	PrunedByLower int
+	// Matches is the number of nodes overlapping with the range that were reported
+	Matches int
+	// Stopped reports whether the search stopped the traversal before it was complete
+	Stopped  bool
+	Duration time.Duration
+}
+
+// WithTraceHook makes the tree call the given hook once per traversal of its main search, shared by Including,
+// Overlapping, Covering and their counterparts, with the nodes visited, the subtrees pruned and the matches found,
+// so that unexpectedly slow or wrong searches can be debugged or reported as tracing spans. The hook runs
+// synchronously, after the traversal; traced traversals are slower than untraced ones.
+func WithTraceHook(hook func(ev QueryEvent)) Option {
+	return func(o *options) {
+		o.traceHook = hook
+	}
+}
+
+// traceClosed is the traced counterpart of traverseClosed, reporting the traversal to the tree trace hook.
+func (t *INTree) traceClosed(lower, upper float64, fn func(pos int) bool) {
+	ev := QueryEvent{Lower: lower, Upper: upper}
+	start := time.Now()
+
+	traced := func(pos int) bool {
+		ev.Matches++
+		ev.Stopped = !fn(pos)
+
+		return !ev.Stopped
+	}
+
+	// Nodes buffered by Insert are scanned first
+	stopped := false
+	for pos := t.treeLen(); pos < len(t.indexes) && !stopped; pos++ {
+		ev.Visited++
+		stopped = t.limits[3*pos] <= upper && lower <= t.limits[3*pos+1] && !traced(pos)
+	}
+
+	switch {
+	case stopped:
+	case t.unordered:
+		for pos := range t.indexes {
+			ev.Visited++
+
+			if t.limits[3*pos] <= upper && lower <= t.limits[3*pos+1] && !traced(pos) {
+				break
+			}
+		}
+	case t.layout != nil:
+		t.layout.trace(lower, upper, traced, &ev)
+	default:
+		t.traceInOrder(lower, upper, traced, &ev)
+	}
+
+	ev.Duration = time.Since(start)
+	t.traceHook(ev)
+}
+
+// traceInOrder is an internal utility function, mirroring the in-order traversal of traverseClosed
+// while counting the visited nodes and pruned subtrees into the given event.
+func (t *INTree) traceInOrder(lower, upper float64, fn func(pos int) bool, ev *QueryEvent) {
+	n := t.treeLen()
+	if n == 0 {
+		return
+	}
+
+	var stock [stockSize]int
+	idxStock := append(stock[:0], 0, n-1)
+
+	for len(idxStock) > 0 {
+		n := len(idxStock)
+		lBoundIdx, rBoundIdx := idxStock[n-2], idxStock[n-1]
+		idxStock = idxStock[:n-2]
+
+		centerIdx := (lBoundIdx + rBoundIdx + 1) >> 1
+		node := (*[3]float64)(t.limits[3*centerIdx:])
+		ev.Visited++
+
+		if lBoundIdx < centerIdx {
+			if lower <= node[2] {
+				idxStock = append(idxStock, lBoundIdx, centerIdx-1)
+			} else {
+				ev.PrunedByMax++
+			}
+		}
+
+		if node[0] > upper {
+			if centerIdx < rBoundIdx {
+				ev.PrunedByLower++
+			}
+
+			continue
+		}
+
+		if centerIdx < rBoundIdx {
+			idxStock = append(idxStock, centerIdx+1, rBoundIdx)
+		}
+
+		if lower <= node[1] && !fn(centerIdx) {
+			return
+		}
+	}
+}
+
+// trace is an internal utility function, mirroring the traversal of traverse
+// while counting the visited slots and pruned subtrees into the given event.
+func (e *eytzinger) trace(lower, upper float64, fn func(pos int) bool, ev *QueryEvent) {
+	n := len(e.positions)
+	if n == 0 {
+		return
+	}
+
+	var stock [stockSize]int
+	slotStock := append(stock[:0], 0)
+
+	for len(slotStock) > 0 {
+		k := slotStock[len(slotStock)-1]
+		slotStock = slotStock[:len(slotStock)-1]
+
+		node := (*[3]float64)(e.limits[3*k:])
+		ev.Visited++
+
+		if lower > node[2] {
+			ev.PrunedByMax++
+			continue
+		}
+
+		if left := 2*k + 1; left < n {
+			slotStock = append(slotStock, left)
+		}
+
+		if node[0] > upper {
+			if 2*k+2 < n {
+				ev.PrunedByLower++
+			}
+
+			continue
+		}
+
+		if right := 2*k + 2; right < n {
+			slotStock = append(slotStock, right)
+		}
+
+		if lower <= node[1] && !fn(e.positions[k]) {
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_WithTraceHook(t *testing.T) {
+	t.Run("Case_Events", func(t *testing.T) {
+		bounds := randomBounds(5000, 1000.0, 10.0)
+
+		for _, opts := range [][]intree.Option{nil, {intree.WithEytzingerLayout()}} {
+			events := []intree.QueryEvent{}
+			hook := func(ev intree.QueryEvent) { events = append(events, ev) }
+
+			tree := intree.NewINTree(bounds, append(opts, intree.WithTraceHook(hook))...)
+			reference := intree.NewINTree(bounds, opts...)
+
+			assert.EqualValues(t, reference.Including(500.0), tree.Including(500.0))
+			assert.EqualValues(t, reference.Overlapping(10.0, 20.0), tree.Overlapping(10.0, 20.0))
+			assert.Len(t, events, 2)
+
+			assert.EqualValues(t, 500.0, events[0].Lower)
+			assert.EqualValues(t, 500.0, events[0].Upper)
+			assert.EqualValues(t, len(reference.Including(500.0)), events[0].Matches)
+			assert.EqualValues(t, len(reference.Overlapping(10.0, 20.0)), events[1].Matches)
+
+			for _, ev := range events {
+				assert.False(t, ev.Stopped)
+				assert.GreaterOrEqual(t, ev.Visited, ev.Matches)
+				assert.Less(t, ev.Visited, tree.Len()/10, "searches must prune most of the tree")
+				assert.Positive(t, ev.PrunedByMax)
+				assert.Positive(t, ev.PrunedByLower)
+			}
+		}
+	})
+	t.Run("Case_Stopped", func(t *testing.T) {
+		var last intree.QueryEvent
+		tree := intree.NewINTree(exampleBounds(), intree.WithTraceHook(func(ev intree.QueryEvent) { last = ev }))
+
+		_, ok := tree.AnyIncluding(4.3)
+		assert.True(t, ok)
+		assert.True(t, last.Stopped)
+		assert.EqualValues(t, 1, last.Matches)
+	})
+	t.Run("Case_Endpoints", func(t *testing.T) {
+		var last intree.QueryEvent
+		tree := intree.NewINTree(exampleBounds(), intree.WithEndpoints(intree.UpperOpen),
+			intree.WithTraceHook(func(ev intree.QueryEvent) { last = ev }))
+
+		tree.Including(4.3)
+		assert.Greater(t, last.Lower, 4.3)
+		assert.EqualValues(t, 4.3, last.Upper)
+	})
+	t.Run("Case_Border/empty_tree", func(t *testing.T) {
+		calls := 0
+		tree := intree.NewINTree(nil, intree.WithTraceHook(func(ev intree.QueryEvent) {
+			calls++
+			assert.Zero(t, ev.Visited)
+		}))
+
+		assert.Empty(t, tree.Including(1.0))
+		assert.EqualValues(t, 1, calls)
+	})
+}
@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TTLBounds is the main interface expected by NewTTLTree(); requires Limits method to access interval limits
+// and ExpiresAt method to access the instant the interval expires at.
+type TTLBounds interface {
+	Bounds
+	ExpiresAt() time.Time
+}
+
+// TTLInterval is a plain TTLBounds implementation.
+type TTLInterval struct {
+	Lower, Upper float64
+	Expires      time.Time
+}
+
+// Limits accesses the interval limits.
+func (i TTLInterval) Limits() (float64, float64) {
+	return i.Lower, i.Upper
+}
+
+// ExpiresAt accesses the interval expiration instant.
+func (i TTLInterval) ExpiresAt() time.Time {
+	return i.Expires
+}
+
+// TTLTree is a thread-safe tree of expiring intervals, for rate limit windows and lease tracking:
+// searches exclude intervals from their expiration instant on, and Evict drops them from the tree.
+// Returns indices to the stored intervals, which shift down on eviction as with Compact; IncludingBounds
+// returns the intervals themselves, for callers needing stable identities.
+type TTLTree struct {
+	mu      sync.RWMutex
+	tree    *INTree
+	expires []time.Time
+}
+
+// NewTTLTree is the expiring initialization function;
+// creates the tree from the given Slice of TTLBounds with the given options, retaining the bounds.
+func NewTTLTree(bounds []TTLBounds, opts ...Option) *TTLTree {
+	t := &TTLTree{expires: make([]time.Time, len(bounds))}
+	plain := make([]Bounds, len(bounds))
+
+	for i, b := range bounds {
+		plain[i] = b
+		t.expires[i] = b.ExpiresAt()
+	}
+
+	t.tree = NewINTree(plain, append(opts[:len(opts):len(opts)], WithRetainedBounds())...)
+
+	return t
+}
+
+// Len returns the number of intervals stored in the tree, including expired ones not evicted yet.
+func (t *TTLTree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Len()
+}
+
+// Insert adds the given interval to the tree, returning its index: the next available one.
+func (t *TTLTree) Insert(b TTLBounds) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.expires = append(t.expires, b.ExpiresAt())
+
+	return t.tree.Insert(b)
+}
+
+// Including collects the intervals that contain the given value and have not expired yet.
+func (t *TTLTree) Including(val float64) []int {
+	return t.IncludingAt(val, time.Now())
+}
+
+// IncludingAt collects the intervals that contain the given value and have not expired at the given instant.
+func (t *TTLTree) IncludingAt(val float64, now time.Time) []int {
+	result := []int{}
+
+	t.includingAt(val, now, func(idx int) {
+		result = append(result, idx)
+	})
+
+	return result
+}
+
+// IncludingBounds collects the intervals that contain the given value and have not expired yet,
+// in the same order as Including.
+func (t *TTLTree) IncludingBounds(val float64) []TTLBounds {
+	result := []TTLBounds{}
+	now := time.Now()
+
+	t.includingAt(val, now, func(idx int) {
+		result = append(result, t.tree.bounds[idx].(TTLBounds))
+	})
+
+	return result
+}
+
+// includingAt is an internal utility function, calling fn under a read lock with the index of every interval
+// containing the given value and not expired at the given instant.
+func (t *TTLTree) includingAt(val float64, now time.Time, fn func(idx int)) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	t.tree.IncludingFunc(val, func(idx int) bool {
+		if now.Before(t.expires[idx]) {
+			fn(idx)
+		}
+
+		return true
+	})
+}
+
+// Evict drops the intervals expired at the given instant from the tree in a single O(n) pass, shifting the
+// following indices down as Compact does. Returns the number of evicted intervals.
+func (t *TTLTree) Evict(now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	removed := make([]bool, len(t.expires))
+	evicted := 0
+
+	for idx, expires := range t.expires {
+		if !now.Before(expires) {
+			removed[idx] = true
+			evicted++
+		}
+	}
+
+	if evicted == 0 {
+		return 0
+	}
+
+	t.tree.flush()
+
+	flagged := make([]bool, len(t.tree.indexes))
+	for pos, idx := range t.tree.indexes {
+		flagged[pos] = removed[idx]
+	}
+
+	t.tree.removeNodes(flagged)
+	t.tree.compactIndexes(removed)
+	t.tree.reaugment()
+
+	n := 0
+
+	for idx, expires := range t.expires {
+		if !removed[idx] {
+			t.expires[n] = expires
+			n++
+		}
+	}
+
+	t.expires = t.expires[:n]
+
+	return evicted
+}
+
+// RunEviction evicts expired intervals every given period in a background goroutine, until the given context
+// is done.
+func (t *TTLTree) RunEviction(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				t.Evict(now)
+			}
+		}
+	}()
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TTLTree(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Case_Example", func(t *testing.T) {
+		tree := intree.NewTTLTree([]intree.TTLBounds{
+			intree.TTLInterval{Lower: 0.0, Upper: 10.0, Expires: base.Add(time.Minute)},
+			intree.TTLInterval{Lower: 5.0, Upper: 15.0, Expires: base.Add(time.Hour)},
+			intree.TTLInterval{Lower: 2.0, Upper: 6.0, Expires: base.Add(2 * time.Minute)},
+		})
+
+		assert.ElementsMatch(t, []int{0, 1, 2}, tree.IncludingAt(5.5, base))
+		// Intervals expire at their expiration instant
+		assert.ElementsMatch(t, []int{1, 2}, tree.IncludingAt(5.5, base.Add(time.Minute)))
+		assert.ElementsMatch(t, []int{1}, tree.IncludingAt(5.5, base.Add(5*time.Minute)))
+
+		assert.EqualValues(t, 3, tree.Insert(intree.TTLInterval{Lower: 4.0, Upper: 5.0, Expires: base.Add(3 * time.Hour)}))
+		assert.EqualValues(t, 4, tree.Len())
+
+		assert.EqualValues(t, 0, tree.Evict(base))
+		assert.EqualValues(t, 2, tree.Evict(base.Add(5*time.Minute)))
+		assert.EqualValues(t, 2, tree.Len())
+
+		// Remaining indices shift down
+		assert.ElementsMatch(t, []int{0, 1}, tree.IncludingAt(5.0, base.Add(5*time.Minute)))
+		assert.ElementsMatch(t, []int{1}, tree.IncludingAt(5.0, base.Add(2*time.Hour)))
+	})
+	t.Run("Case_Wall_clock", func(t *testing.T) {
+		now := time.Now()
+		lease := intree.TTLInterval{Lower: 0.0, Upper: 1.0, Expires: now.Add(time.Hour)}
+		tree := intree.NewTTLTree([]intree.TTLBounds{
+			lease,
+			intree.TTLInterval{Lower: 0.0, Upper: 1.0, Expires: now.Add(-time.Second)},
+		})
+
+		assert.EqualValues(t, []int{0}, tree.Including(0.5))
+		assert.EqualValues(t, []intree.TTLBounds{lease}, tree.IncludingBounds(0.5))
+	})
+	t.Run("Case_Background_eviction", func(t *testing.T) {
+		tree := intree.NewTTLTree([]intree.TTLBounds{
+			intree.TTLInterval{Lower: 0.0, Upper: 1.0, Expires: time.Now().Add(-time.Second)},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tree.RunEviction(ctx, time.Millisecond)
+		assert.Eventually(t, func() bool { return tree.Len() == 0 }, time.Second, time.Millisecond)
+	})
+	t.Run("Case_Border/nil_bounds", func(t *testing.T) {
+		tree := intree.NewTTLTree(nil)
+
+		assert.EqualValues(t, 0, tree.Len())
+		assert.Empty(t, tree.Including(0.0))
+		assert.EqualValues(t, 0, tree.Evict(base))
+	})
+}
@@ -0,0 +1,209 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrCorruptedTree is returned by Validate when the tree nodes break the invariants searches rely on.
+var ErrCorruptedTree = errors.New("intree: corrupted tree")
+
+// Validate checks the invariants searches rely on, so that trees loaded from corrupted files fail loudly
+// instead of returning wrong matches: node Slices lengths, the reverse index mapping, the node order by lower
+// limit (unless the tree has a custom node order) and every augmented limit, past the nodes buffered by Insert.
+// Takes O(n) time.
+// Returns an ErrCorruptedTree wrapped error describing the first broken invariant.
+func (t *INTree) Validate() error {
+	if len(t.limits) != 3*len(t.indexes) || len(t.positions) != len(t.indexes) {
+		return fmt.Errorf("%w: %d indexes, %d positions and %d limits", ErrCorruptedTree,
+			len(t.indexes), len(t.positions), len(t.limits))
+	}
+
+	if t.buffered < 0 || t.buffered > len(t.indexes) {
+		return fmt.Errorf("%w: %d buffered nodes for %d nodes", ErrCorruptedTree, t.buffered, len(t.indexes))
+	}
+
+	for pos, idx := range t.indexes {
+		if idx < 0 || idx >= len(t.positions) || t.positions[idx] != pos {
+			return fmt.Errorf("%w: index %d at position %d is not mapped back", ErrCorruptedTree, idx, pos)
+		}
+	}
+
+	n := t.treeLen()
+
+	if !t.unordered {
+		for pos := 1; pos < n; pos++ {
+			if t.limits[3*pos] < t.limits[3*pos-3] {
+				return fmt.Errorf("%w: lower limit %v at position %d is below the previous one", ErrCorruptedTree,
+					t.limits[3*pos], pos)
+			}
+		}
+	}
+
+	if _, err := validateAugmented(t.limits[:3*n], 0); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateAugmented is an internal utility function, checking the augmented limits of the given nodes
+// bottom-up, as computed by augment. Returns the maximum value of the given nodes.
+func validateAugmented(limits []float64, offset int) (float64, error) {
+	n := len(limits) / 3
+	if n < 1 {
+		return math.NaN(), nil
+	}
+
+	r := n >> 1
+
+	left, err := validateAugmented(limits[:3*r], offset)
+	if err != nil {
+		return 0, err
+	}
+
+	right, err := validateAugmented(limits[3*r+3:], offset+r+1)
+	if err != nil {
+		return 0, err
+	}
+
+	max := maxLimit(limits[3*r+1], left, right)
+
+	if !sameLimit(limits[3*r+2], max) {
+		return 0, fmt.Errorf("%w: augmented limit %v at position %d does not match %v", ErrCorruptedTree,
+			limits[3*r+2], offset+r, max)
+	}
+
+	return max, nil
+}
+
+// CheckInvariants is the exhaustive counterpart of Validate, intended for use after deserialization, after
+// mutations and inside fuzz tests: on top of the Validate invariants, checks the lengths of the stored values,
+// bounds and priorities, the removed intervals, that no live interval has its upper limit below its lower one
+// and, if the tree has an Eytzinger layout, that its slots mirror the nodes in order along with their
+// augmented limits. NaN limits are accepted, as augment ignores them. Takes O(n) time.
+// Returns an ErrCorruptedTree wrapped error describing the first broken invariant.
+func (t *INTree) CheckInvariants() error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	if t.values != nil && len(t.values) != len(t.indexes) {
+		return fmt.Errorf("%w: %d values for %d nodes", ErrCorruptedTree, len(t.values), len(t.indexes))
+	}
+
+	if t.bounds != nil && len(t.bounds) != len(t.indexes) {
+		return fmt.Errorf("%w: %d retained bounds for %d nodes", ErrCorruptedTree, len(t.bounds), len(t.indexes))
+	}
+
+	if t.tombstones != nil {
+		if len(t.tombstones) != len(t.indexes) {
+			return fmt.Errorf("%w: %d tombstones for %d nodes", ErrCorruptedTree, len(t.tombstones), len(t.indexes))
+		}
+
+		removed := 0
+
+		for idx, dead := range t.tombstones {
+			if !dead {
+				continue
+			}
+
+			if removed++; !math.IsInf(t.limits[3*t.positions[idx]+1], -1) {
+				return fmt.Errorf("%w: removed index %d is still searchable", ErrCorruptedTree, idx)
+			}
+		}
+
+		if removed != t.removed {
+			return fmt.Errorf("%w: %d tombstones counted as %d", ErrCorruptedTree, removed, t.removed)
+		}
+	}
+
+	for pos, idx := range t.indexes {
+		if (t.tombstones == nil || !t.tombstones[idx]) && t.limits[3*pos+1] < t.limits[3*pos] {
+			return fmt.Errorf("%w: upper limit %v of index %d is below its lower limit %v", ErrCorruptedTree,
+				t.limits[3*pos+1], idx, t.limits[3*pos])
+		}
+	}
+
+	if t.priorities != nil && (len(t.priorities) != len(t.indexes) || len(t.priorityMax) != len(t.indexes)) {
+		return fmt.Errorf("%w: %d priorities and %d subtree priorities for %d nodes", ErrCorruptedTree,
+			len(t.priorities), len(t.priorityMax), len(t.indexes))
+	}
+
+	if t.layout != nil {
+		return t.layout.check(t.limits[:3*t.treeLen()])
+	}
+
+	return nil
+}
+
+// check is an internal utility function, validating the layout against the given in-order node limits.
+func (e *eytzinger) check(limits []float64) error {
+	n := len(e.positions)
+	if n != len(limits)/3 || len(e.limits) != 3*n {
+		return fmt.Errorf("%w: layout of %d slots and %d limits for %d nodes", ErrCorruptedTree,
+			n, len(e.limits), len(limits)/3)
+	}
+
+	// An in-order walk of the complete tree must visit every node position in order
+	expected, stack := 0, []int{}
+
+	for k := 0; k < n || len(stack) > 0; {
+		if k < n {
+			stack = append(stack, k)
+			k = 2*k + 1
+
+			continue
+		}
+
+		k, stack = stack[len(stack)-1], stack[:len(stack)-1]
+
+		if pos := e.positions[k]; pos != expected || !sameLimit(e.limits[3*k], limits[3*pos]) ||
+			!sameLimit(e.limits[3*k+1], limits[3*pos+1]) {
+			return fmt.Errorf("%w: layout slot %d does not mirror node %d", ErrCorruptedTree, k, expected)
+		}
+
+		expected++
+		k = 2*k + 2
+	}
+
+	for k := n - 1; k >= 0; k-- {
+		max := e.limits[3*k+1]
+
+		for _, child := range [2]int{2*k + 1, 2*k + 2} {
+			if child < n {
+				max = maxLimit(max, e.limits[3*child+2])
+			}
+		}
+
+		if !sameLimit(e.limits[3*k+2], max) {
+			return fmt.Errorf("%w: augmented limit %v at layout slot %d does not match %v", ErrCorruptedTree,
+				e.limits[3*k+2], k, max)
+		}
+	}
+
+	return nil
+}
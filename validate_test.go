@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2021 Luis Gomez
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package intree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lggomez/intree"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tree_Validate(t *testing.T) {
+	t.Run("Case_Built", func(t *testing.T) {
+		for _, n := range []int{0, 1, 2, 3, 100, 1000} {
+			assert.NoError(t, intree.NewINTree(randomBounds(n, 100.0, 5.0)).Validate(), "n = %d", n)
+		}
+
+		tree, err := intree.NewINTreeSortedBy(exampleBounds(), func(i, j int) bool { return i > j })
+		assert.NoError(t, err)
+		assert.NoError(t, tree.Validate())
+	})
+	t.Run("Case_Updates", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds())
+
+		tree.Insert(&testBounds{Lower: 4.0, Upper: 5.0})
+		assert.NoError(t, tree.Validate())
+
+		assert.NoError(t, tree.Delete(3))
+		assert.NoError(t, tree.Validate())
+
+		tree.InsertCoalesce(&testBounds{Lower: 0.0, Upper: 20.0}, 0.0)
+		assert.NoError(t, tree.Validate())
+	})
+	t.Run("Case_Border/nan_upper", func(t *testing.T) {
+		bounds := []intree.Bounds{
+			&testBounds{Lower: 1.0, Upper: math.NaN()},
+			&testBounds{Lower: 2.0, Upper: 3.0},
+			&testBounds{Lower: 4.0, Upper: math.NaN()},
+			&testBounds{Lower: 5.0, Upper: math.NaN()},
+		}
+
+		for _, opts := range [][]intree.Option{nil, {intree.WithEytzingerLayout()}} {
+			tree := intree.NewINTree(bounds, opts...)
+			assert.NoError(t, tree.Validate())
+			assert.NoError(t, tree.CheckInvariants())
+
+			data, err := tree.MarshalBinary()
+			assert.NoError(t, err)
+
+			decoded := &intree.INTree{}
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.ElementsMatch(t, []int{1}, decoded.Including(2.5))
+		}
+	})
+	t.Run("Case_Border/zero_value", func(t *testing.T) {
+		assert.NoError(t, (&intree.INTree{}).Validate())
+	})
+}
+
+func Test_Tree_CheckInvariants(t *testing.T) {
+	t.Run("Case_Built", func(t *testing.T) {
+		for _, n := range []int{0, 1, 2, 3, 100, 1000} {
+			bounds := randomBounds(n, 100.0, 5.0)
+
+			assert.NoError(t, intree.NewINTree(bounds).CheckInvariants(), "n = %d", n)
+			assert.NoError(t, intree.NewINTree(bounds, intree.WithEytzingerLayout()).CheckInvariants(), "n = %d", n)
+			assert.NoError(t, intree.NewINTree(bounds, intree.WithRetainedBounds()).CheckInvariants(), "n = %d", n)
+		}
+	})
+	t.Run("Case_Updates", func(t *testing.T) {
+		tree := intree.NewINTree(exampleBounds(), intree.WithEytzingerLayout(), intree.WithRetainedBounds())
+
+		tree.Insert(&testBounds{Lower: 4.0, Upper: 5.0})
+		assert.NoError(t, tree.CheckInvariants())
+
+		assert.NoError(t, tree.Delete(3))
+		assert.NoError(t, tree.CheckInvariants())
+	})
+	t.Run("Case_Border/inverted", func(t *testing.T) {
+		tree := intree.NewINTree([]intree.Bounds{
+			&testBounds{Lower: 1.0, Upper: 2.0},
+			&testBounds{Lower: 4.0, Upper: 3.0},
+			&testBounds{Lower: 5.0, Upper: math.NaN()},
+		})
+		assert.NoError(t, tree.Validate())
+		assert.ErrorIs(t, tree.CheckInvariants(), intree.ErrCorruptedTree)
+
+		// Removed intervals are left with a -Inf upper limit
+		assert.NoError(t, tree.Remove(1))
+		assert.NoError(t, tree.CheckInvariants())
+	})
+	t.Run("Case_Border/zero_value", func(t *testing.T) {
+		assert.NoError(t, (&intree.INTree{}).CheckInvariants())
+	})
+}